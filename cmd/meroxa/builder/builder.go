@@ -26,6 +26,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/meroxa/cli/cmd/meroxa/clierrors"
 	"github.com/meroxa/cli/cmd/meroxa/github"
 
 	"github.com/cased/cased-go"
@@ -36,6 +37,7 @@ import (
 	"github.com/meroxa/cli/cmd/meroxa/global"
 	"github.com/meroxa/cli/config"
 	"github.com/meroxa/cli/log"
+	"github.com/meroxa/cli/utils/display"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
 )
 
@@ -80,6 +82,20 @@ type CommandWithNoHeaders interface {
 	HideHeaders(hide bool)
 }
 
+type CommandWithListOptions interface {
+	Command
+	// ListOptions receives how listed items are filtered, sorted and limited with --filter, --sort-by, --columns and
+	// --limit.
+	ListOptions(o display.ListOptions)
+}
+
+type CommandWithWatch interface {
+	CommandWithExecute
+	CommandWithLogger
+	// Watchable reports whether the result of the command can be refreshed with --watch and --until-state.
+	Watchable() bool
+}
+
 type CommandWithConfirmWithValue interface {
 	Command
 	// ValueToConfirm adds a prompt before the command is executed where the user is asked to write the exact value as
@@ -156,6 +172,14 @@ type Flag struct {
 	Hidden bool
 }
 
+// CommandWithOutputFile is implemented by commands saving their result to the file given to --output with --dry-run,
+// rather than printing it in an output format.
+type CommandWithOutputFile interface {
+	Command
+	// OutputFile receives the file given to --output, empty when none was given.
+	OutputFile(path string)
+}
+
 type CommandWithHidden interface {
 	Command
 	// Hidden returns the desired hidden value for the command.
@@ -215,6 +239,11 @@ func BuildCobraCommand(c Command) *cobra.Command {
 	buildCommandWithDeprecated(cmd, c)
 	buildCommandWithLogger(cmd, c)
 	buildCommandWithNoHeaders(cmd, c)
+	buildCommandWithListOptions(cmd, c)
+	buildCommandWithOutputFile(cmd, c)
+	// buildCommandWithWatch needs to go after buildCommandWithLogger and buildCommandWithExecute to replace the
+	// logger of the command and repeat its execution.
+	buildCommandWithWatch(cmd, c)
 	buildCommandWithSubCommands(cmd, c)
 
 	// this will run for all commands using PostRun hook
@@ -249,7 +278,10 @@ func buildCommandWithArgs(cmd *cobra.Command, c Command) {
 				return err
 			}
 		}
-		return v.ParseArgs(args)
+		if err := v.ParseArgs(args); err != nil {
+			return clierrors.Wrap(err, clierrors.CategoryValidation, "invalid_arguments")
+		}
+		return nil
 	}
 }
 
@@ -282,7 +314,7 @@ func buildCommandWithConfig(cmd *cobra.Command, c Command) {
 		return
 	}
 
-	// Inject global.Config.
+	// Inject global.Config, with credentials kept in the configured credentials store.
 	oldPreRunE := cmd.PreRunE
 	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
 		if oldPreRunE != nil {
@@ -292,7 +324,11 @@ func buildCommandWithConfig(cmd *cobra.Command, c Command) {
 			}
 		}
 
-		v.Config(global.Config)
+		cfg, err := global.Credentials()
+		if err != nil {
+			return err
+		}
+		v.Config(cfg)
 		return nil
 	}
 
@@ -334,6 +370,67 @@ func buildCommandWithNoHeaders(cmd *cobra.Command, c Command) {
 	}
 }
 
+func buildCommandWithListOptions(cmd *cobra.Command, c Command) {
+	v, ok := c.(CommandWithListOptions)
+	if !ok {
+		return
+	}
+
+	var (
+		filters []string
+		sortBy  string
+		columns []string
+		limit   int
+	)
+
+	cmd.Flags().StringArrayVar(&filters, "filter", nil, "only show items matching key=value, e.g. state=running (can be repeated)")
+	cmd.Flags().StringVar(&sortBy, "sort-by", "", "sort items by a column, e.g. name, created_at or state")
+	cmd.Flags().StringSliceVar(&columns, "columns", nil, "comma-separated list of columns to show, e.g. name,state")
+	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of items to show")
+
+	old := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if old != nil {
+			err := old(cmd, args)
+			if err != nil {
+				return err
+			}
+		}
+
+		o, err := display.ParseListOptions(filters, sortBy, columns, limit)
+		if err != nil {
+			return err
+		}
+		v.ListOptions(o)
+		return nil
+	}
+}
+
+func buildCommandWithOutputFile(cmd *cobra.Command, c Command) {
+	v, ok := c.(CommandWithOutputFile)
+	if !ok {
+		return
+	}
+
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[global.OutputFileAnnotation] = "true"
+
+	old := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if old != nil {
+			err := old(cmd, args)
+			if err != nil {
+				return err
+			}
+		}
+
+		v.OutputFile(global.OutputFile())
+		return nil
+	}
+}
+
 func buildCommandWithConfirmWithValue(cmd *cobra.Command, c Command) {
 	v, ok := c.(CommandWithConfirmWithValue)
 	if !ok {
@@ -484,8 +581,8 @@ func buildCommandAutoUpdate(cmd *cobra.Command) {
 			}
 		}
 
-		// Do not check and show warning to update when using --json
-		if cmd.Flags().Changed("json") {
+		// Do not check and show warning to update when the output is parsed, e.g. --output json
+		if global.StructuredOutput() {
 			return nil
 		}
 
@@ -529,7 +626,8 @@ func buildCommandWithExecute(cmd *cobra.Command, c Command) {
 		}
 		err := v.Execute(cmd.Context())
 		if err != nil && strings.Contains(err.Error(), "Unknown or invalid refresh token") {
-			return fmt.Errorf("unknown or invalid refresh token, please run `meroxa login` again")
+			return clierrors.New(clierrors.CategoryAuth, "invalid_refresh_token",
+				"unknown or invalid refresh token, please run `meroxa login` again")
 		}
 		return err
 	}
@@ -685,7 +783,7 @@ func buildCommandWithDeprecated(cmd *cobra.Command, c Command) {
 				return err
 			}
 
-			if cmd.Flags().Changed("json") {
+			if global.StructuredOutput() {
 				return nil
 			}
 
@@ -715,7 +813,9 @@ func buildCommandWithLogger(cmd *cobra.Command, c Command) {
 			}
 		}
 
-		v.Logger(global.NewLogger())
+		// Tables rendered from the result of the command follow its --no-headers flag.
+		hideHeaders, _ := cmd.Flags().GetBool("no-headers")
+		v.Logger(global.NewLogger(hideHeaders))
 		return nil
 	}
 }
@@ -790,6 +890,10 @@ func CheckFeatureFlag(featureFlag string) bool {
 }
 
 func writeConfigFile() error {
+	if err := global.SaveCredentials(); err != nil {
+		return fmt.Errorf("meroxa: could not save credentials: %v", err)
+	}
+
 	err := global.Config.WriteConfig()
 	if err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
@@ -0,0 +1,210 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/cli/utils/display"
+)
+
+const defaultWatchInterval = 2 * time.Second
+
+// clearScreen moves the cursor to the top left corner of the terminal and clears it.
+const clearScreen = "\033[H\033[2J"
+
+func buildCommandWithWatch(cmd *cobra.Command, c Command) {
+	v, ok := c.(CommandWithWatch)
+	if !ok || !v.Watchable() {
+		return
+	}
+
+	var (
+		interval time.Duration
+		states   []string
+		w        *watcher
+	)
+
+	cmd.Flags().DurationVar(&interval, "watch", 0, "refresh the output at an interval, e.g. --watch=5s")
+	cmd.Flags().Lookup("watch").NoOptDefVal = defaultWatchInterval.String()
+	cmd.Flags().StringSliceVar(&states, "until-state", nil,
+		"keep watching until the state is one of these states, e.g. running,failed (implies --watch)")
+
+	oldPreRunE := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if oldPreRunE != nil {
+			err := oldPreRunE(cmd, args)
+			if err != nil {
+				return err
+			}
+		}
+
+		if interval < 0 {
+			return fmt.Errorf("--watch interval must be positive, got %s", interval)
+		}
+		if len(states) > 0 && interval == 0 {
+			interval = defaultWatchInterval
+		}
+		if interval == 0 {
+			return nil
+		}
+
+		hideHeaders, _ := cmd.Flags().GetBool("no-headers")
+		w = &watcher{
+			interval: interval,
+			states:   states,
+			out:      os.Stdout,
+			redraw:   term.IsTerminal(int(os.Stdout.Fd())),
+			format:   global.NewOutputFormatter(hideHeaders),
+			header:   fmt.Sprintf("Every %s: %s", interval, strings.Join(append([]string{cmd.CommandPath()}, args...), " ")),
+		}
+		v.Logger(w.logger())
+		return nil
+	}
+
+	oldRunE := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if w == nil {
+			return oldRunE(cmd, args)
+		}
+		return w.run(cmd.Context(), func() error {
+			return oldRunE(cmd, args)
+		})
+	}
+}
+
+// watcher executes a command repeatedly, redrawing its result on a terminal or printing an event every time it
+// changes otherwise.
+type watcher struct {
+	interval time.Duration
+	// states stops watching once the state of the result is one of them.
+	states []string
+	out    io.Writer
+	redraw bool
+	format log.Formatter
+	header string
+
+	result interface{}
+	last   []byte
+}
+
+// watchEvent is printed as a line of JSON every time the result of a watched command changes.
+type watchEvent struct {
+	Time   time.Time       `json:"time"`
+	Type   string          `json:"type"`
+	State  string          `json:"state,omitempty"`
+	Object json.RawMessage `json:"object"`
+}
+
+// logger returns the logger of the watched command, capturing its result so that it's printed by the watcher.
+// Only warnings and errors are printed otherwise.
+func (w *watcher) logger() log.Logger {
+	return log.New(
+		log.NewLeveledLogger(os.Stderr, log.Warn),
+		log.NewJSONLogger(io.Discard),
+		log.NewSpinnerLogger(io.Discard),
+		w,
+	)
+}
+
+func (w *watcher) Output(_ context.Context, data interface{}) {
+	w.result = data
+}
+
+func (w *watcher) run(ctx context.Context, execute func() error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for {
+		w.result = nil
+		if err := execute(); err != nil {
+			return err
+		}
+		if err := w.print(); err != nil {
+			return err
+		}
+
+		if len(w.states) > 0 {
+			states, ok := display.States(w.result)
+			if !ok {
+				return errors.New("--until-state can't be used with this command, its output has no state")
+			}
+			if w.reached(states) {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(w.interval):
+		}
+	}
+}
+
+// reached reports whether all the states are one of the states watched for. An empty list hasn't reached any state.
+func (w *watcher) reached(states []string) bool {
+	if len(states) == 0 {
+		return false
+	}
+	for _, s := range states {
+		found := false
+		for _, want := range w.states {
+			if strings.EqualFold(s, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *watcher) print() error {
+	if w.redraw {
+		s, err := w.format(w.result)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w.out, "%s%s\t%s\n\n%s\n", clearScreen, w.header, time.Now().Format(time.RFC1123), s)
+		return err
+	}
+
+	object, err := json.Marshal(w.result)
+	if err != nil {
+		return err
+	}
+	if w.last != nil && string(object) == string(w.last) {
+		return nil
+	}
+
+	e := watchEvent{Time: time.Now().UTC(), Type: "MODIFIED", Object: object}
+	if w.last == nil {
+		e.Type = "ADDED"
+	}
+	// The state of a single object is shown alongside it, lists have the state of each of their items.
+	if states, ok := display.States(w.result); ok && len(states) == 1 && object[0] != '[' {
+		e.State = states[0]
+	}
+	w.last = object
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w.out, "%s\n", b)
+	return err
+}
@@ -0,0 +1,172 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+type watchCmd struct {
+	logger log.Logger
+}
+
+var _ CommandWithWatch = (*watchCmd)(nil)
+
+func (c *watchCmd) Usage() string {
+	return "watch"
+}
+
+func (c *watchCmd) Execute(ctx context.Context) error {
+	return nil
+}
+
+func (c *watchCmd) Logger(logger log.Logger) {
+	c.logger = logger
+}
+
+func (c *watchCmd) Watchable() bool {
+	return true
+}
+
+func TestBuildCommandWithWatchFlags(t *testing.T) {
+	cmd := BuildCobraCommand(&watchCmd{})
+
+	watch := cmd.Flags().Lookup("watch")
+	if watch == nil {
+		t.Fatal("expected flag \"watch\" to be present")
+	}
+	if watch.NoOptDefVal != "2s" {
+		t.Fatalf("expected --watch to default to 2s, got %q", watch.NoOptDefVal)
+	}
+	if cmd.Flags().Lookup("until-state") == nil {
+		t.Fatal("expected flag \"until-state\" to be present")
+	}
+}
+
+// executeResults returns a function executing a watched command, which outputs the next result every time.
+func executeResults(t *testing.T, w *watcher, results ...interface{}) (execute func() error, executions *int) {
+	t.Helper()
+	var i int
+	logger := w.logger()
+	return func() error {
+		if i >= len(results) {
+			t.Fatalf("unexpected execution %d", i+1)
+		}
+		logger.Output(context.Background(), results[i])
+		i++
+		return nil
+	}, &i
+}
+
+func TestWatcherPrintsEventsUntilState(t *testing.T) {
+	env := func(state meroxa.EnvironmentState) *meroxa.Environment {
+		return &meroxa.Environment{Name: "my-env", Status: meroxa.EnvironmentViewStatus{State: state}}
+	}
+
+	var out bytes.Buffer
+	w := &watcher{interval: time.Millisecond, states: []string{"ready", "provisioning_error"}, out: &out}
+	execute, executions := executeResults(t, w,
+		env(meroxa.EnvironmentStateProvisioning),
+		env(meroxa.EnvironmentStateProvisioning),
+		env(meroxa.EnvironmentStateReady),
+	)
+
+	if err := w.run(context.Background(), execute); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if *executions != 3 {
+		t.Fatalf("expected 3 executions, got %d", *executions)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected an event per change, got:\n%s", out.String())
+	}
+	for i, want := range []struct{ typ, state string }{{"ADDED", "provisioning"}, {"MODIFIED", "ready"}} {
+		var e watchEvent
+		if err := json.Unmarshal([]byte(lines[i]), &e); err != nil {
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+		if e.Type != want.typ || e.State != want.state {
+			t.Fatalf("expected event %s with state %s, got %s with state %s", want.typ, want.state, e.Type, e.State)
+		}
+		var got meroxa.Environment
+		if err := json.Unmarshal(e.Object, &got); err != nil {
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+		if string(got.Status.State) != want.state {
+			t.Fatalf("expected object with state %s, got %s", want.state, got.Status.State)
+		}
+	}
+}
+
+func TestWatcherUntilStateOfLists(t *testing.T) {
+	connectors := func(states ...meroxa.ConnectorState) []*meroxa.Connector {
+		var cc []*meroxa.Connector
+		for _, s := range states {
+			cc = append(cc, &meroxa.Connector{State: s})
+		}
+		return cc
+	}
+
+	var out bytes.Buffer
+	w := &watcher{interval: time.Millisecond, states: []string{"running"}, out: &out}
+	execute, executions := executeResults(t, w,
+		connectors(),
+		connectors(meroxa.ConnectorStatePending, meroxa.ConnectorStateRunning),
+		connectors(meroxa.ConnectorStateRunning, meroxa.ConnectorStateRunning),
+	)
+
+	if err := w.run(context.Background(), execute); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if *executions != 3 {
+		t.Fatalf("expected 3 executions, got %d", *executions)
+	}
+}
+
+func TestWatcherUntilStateWithoutState(t *testing.T) {
+	w := &watcher{interval: time.Millisecond, states: []string{"running"}, out: &bytes.Buffer{}}
+	execute, _ := executeResults(t, w, []meroxa.ResourceType{{Name: "postgres"}})
+
+	err := w.run(context.Background(), execute)
+	if err == nil || !strings.Contains(err.Error(), "--until-state can't be used with this command") {
+		t.Fatalf("expected an --until-state error, got %v", err)
+	}
+}
+
+func TestWatcherRedraws(t *testing.T) {
+	var out bytes.Buffer
+	w := &watcher{
+		interval: time.Millisecond,
+		states:   []string{"running"},
+		out:      &out,
+		redraw:   true,
+		format:   func(data interface{}) (string, error) { return string(data.(*meroxa.Connector).State), nil },
+		header:   "Every 1ms: meroxa connectors describe my-conn",
+	}
+	execute, _ := executeResults(t, w,
+		&meroxa.Connector{State: meroxa.ConnectorStatePending},
+		&meroxa.Connector{State: meroxa.ConnectorStateRunning},
+	)
+
+	if err := w.run(context.Background(), execute); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	screens := strings.Split(out.String(), clearScreen)
+	if len(screens) != 3 {
+		t.Fatalf("expected the screen to be redrawn twice, got %q", out.String())
+	}
+	for i, want := range []string{"pending", "running"} {
+		if !strings.HasPrefix(screens[i+1], w.header) || !strings.HasSuffix(screens[i+1], "\n\n"+want+"\n") {
+			t.Fatalf("unexpected screen %q", screens[i+1])
+		}
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clierrors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+// Category is the kind of failure of a command, each category exits with its own code.
+type Category string
+
+const (
+	// CategoryInternal is used for errors that couldn't be categorized.
+	CategoryInternal   Category = "internal"
+	CategoryValidation Category = "validation"
+	CategoryAuth       Category = "auth"
+	CategoryNotFound   Category = "not-found"
+	CategoryConflict   Category = "conflict"
+	CategoryRemote     Category = "remote"
+	CategoryTimeout    Category = "timeout"
+)
+
+var exitCodes = map[Category]int{
+	CategoryInternal:   1,
+	CategoryValidation: 2,
+	CategoryAuth:       3,
+	CategoryNotFound:   4,
+	CategoryConflict:   5,
+	CategoryRemote:     6,
+	CategoryTimeout:    7,
+}
+
+// ExitCode is the exit code of the CLI when a command fails with an error of this category.
+func (c Category) ExitCode() int {
+	if code, ok := exitCodes[c]; ok {
+		return code
+	}
+	return exitCodes[CategoryInternal]
+}
+
+// Error is an error of a command meant to be handled by scripts as well as people.
+type Error struct {
+	Category Category
+	// Code identifies the error within its category, e.g. app_not_found.
+	Code    string
+	Message string
+	// Hints are suggestions to fix the error.
+	Hints []string
+	// Details are the details of errors returned by the Meroxa API, by field.
+	Details map[string][]string
+
+	err error
+}
+
+// New returns an error of the given category.
+func New(category Category, code, message string, hints ...string) *Error {
+	return &Error{Category: category, Code: code, Message: message, Hints: hints}
+}
+
+// Newf returns an error of the given category with a formatted message.
+func Newf(category Category, code, format string, args ...interface{}) *Error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Category: category, Code: code, Message: err.Error(), err: errors.Unwrap(err)}
+}
+
+// Wrap returns an error of the given category wrapping err, keeping its message.
+func Wrap(err error, category Category, code string, hints ...string) *Error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Category: category, Code: code, Message: err.Error(), Hints: hints, err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// WithHints returns a copy of e with additional hints.
+func (e *Error) WithHints(hints ...string) *Error {
+	c := *e
+	c.Hints = append(append([]string{}, e.Hints...), hints...)
+	return &c
+}
+
+// ExitCode is the exit code of the CLI when a command fails with this error.
+func (e *Error) ExitCode() int {
+	return e.Category.ExitCode()
+}
+
+// MarshalJSON encodes e as a JSON error object, e.g. {"error":{"category":"not-found","code":"not_found",...}}.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	type object struct {
+		Category Category            `json:"category"`
+		Code     string              `json:"code"`
+		Message  string              `json:"message"`
+		Hints    []string            `json:"hints,omitempty"`
+		Details  map[string][]string `json:"details,omitempty"`
+		ExitCode int                 `json:"exit_code"`
+	}
+	return json.Marshal(struct {
+		Error object `json:"error"`
+	}{
+		Error: object{
+			Category: e.Category,
+			Code:     e.Code,
+			Message:  e.Message,
+			Hints:    e.Hints,
+			Details:  e.Details,
+			ExitCode: e.ExitCode(),
+		},
+	})
+}
+
+// meroxaPkgPath is the package of meroxa-go, whose type of errors returned by the Meroxa API isn't exported.
+var meroxaPkgPath = reflect.TypeOf(meroxa.Application{}).PkgPath()
+
+// apiError has the fields of errors returned by the Meroxa API.
+type apiError struct {
+	Code    string              `json:"code"`
+	Message string              `json:"message"`
+	Details map[string][]string `json:"details"`
+}
+
+// httpStatusError matches errors returned by meroxa-go when the body of a failed response isn't JSON,
+// e.g. "HTTP/1.1 502 Bad Gateway".
+var httpStatusError = regexp.MustCompile(`\bHTTP/[\d.]+ (\d{3}) `)
+
+// usageErrors are the prefixes of the errors returned by cobra when a command is misused.
+var usageErrors = []string{
+	"unknown command",
+	"unknown flag",
+	"unknown shorthand flag",
+	"invalid argument",
+	"flag needs an argument",
+	"required flag(s)",
+	"accepts ",
+	"requires at least",
+	"requires at most",
+}
+
+// From returns err as an *Error. Errors that aren't one already are categorized, e.g. errors returned by the Meroxa
+// API, network errors or errors returned by cobra when a command is misused.
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	if e = fromAPIError(err); e != nil {
+		return e
+	}
+
+	if m := httpStatusError.FindStringSubmatch(err.Error()); m != nil {
+		status, _ := strconv.Atoi(m[1])
+		category, code := fromHTTPStatus(status)
+		return Wrap(err, category, code)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Wrap(err, CategoryTimeout, "timeout")
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return Wrap(err, CategoryTimeout, "network_timeout", "Check your network connection and try again")
+		}
+		return Wrap(err, CategoryRemote, "network_error", "Check your network connection and try again")
+	}
+
+	for _, prefix := range usageErrors {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return Wrap(err, CategoryValidation, "invalid_usage", "Run the command with --help to see its usage")
+		}
+	}
+
+	return Wrap(err, CategoryInternal, "error")
+}
+
+func fromAPIError(err error) *Error {
+	var target error
+	for target = err; target != nil; target = errors.Unwrap(target) {
+		t := reflect.TypeOf(target)
+		if t.Kind() == reflect.Ptr && t.Elem().PkgPath() == meroxaPkgPath && t.Elem().Name() == "errResponse" {
+			break
+		}
+	}
+	if target == nil {
+		return nil
+	}
+
+	b, mErr := json.Marshal(target)
+	if mErr != nil {
+		return nil
+	}
+	var apiErr apiError
+	if mErr = json.Unmarshal(b, &apiErr); mErr != nil {
+		return nil
+	}
+
+	e := Wrap(err, fromAPICode(apiErr.Code, apiErr.Message), apiErr.Code)
+	if e.Code == "" {
+		e.Code = "api_error"
+	}
+	e.Details = apiErr.Details
+	if e.Category == CategoryAuth {
+		e.Hints = []string{"Run `meroxa login` to log in again"}
+	}
+	return e
+}
+
+// fromAPICode categorizes errors returned by the Meroxa API by their code, or their message when the code is unknown.
+func fromAPICode(code, message string) Category {
+	s := strings.ToLower(code)
+	if s == "" {
+		s = strings.ToLower(message)
+	}
+
+	switch {
+	case strings.Contains(s, "not_found"), strings.Contains(s, "not found"):
+		return CategoryNotFound
+	case strings.Contains(s, "unauthorized"), strings.Contains(s, "unauthenticated"),
+		strings.Contains(s, "forbidden"), strings.Contains(s, "permission"):
+		return CategoryAuth
+	case strings.Contains(s, "conflict"), strings.Contains(s, "already_exists"), strings.Contains(s, "already exists"):
+		return CategoryConflict
+	case strings.Contains(s, "invalid"), strings.Contains(s, "bad_request"), strings.Contains(s, "validation"):
+		return CategoryValidation
+	case strings.Contains(s, "timeout"):
+		return CategoryTimeout
+	}
+	return CategoryRemote
+}
+
+func fromHTTPStatus(status int) (Category, string) {
+	switch {
+	case status == 401 || status == 403:
+		return CategoryAuth, "unauthorized"
+	case status == 404:
+		return CategoryNotFound, "not_found"
+	case status == 409:
+		return CategoryConflict, "conflict"
+	case status == 408 || status == 504:
+		return CategoryTimeout, "timeout"
+	case status >= 400 && status < 500:
+		return CategoryValidation, "bad_request"
+	}
+	return CategoryRemote, "api_error"
+}
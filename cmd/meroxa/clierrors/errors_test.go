@@ -0,0 +1,201 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clierrors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+func apiClient(t *testing.T, status int, body string) meroxa.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := meroxa.New(meroxa.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	return client
+}
+
+func TestFromAPIError(t *testing.T) {
+	tests := []struct {
+		desc     string
+		status   int
+		body     string
+		category Category
+		code     string
+		hints    bool
+	}{
+		{
+			desc:     "not found",
+			status:   http.StatusNotFound,
+			body:     `{"code":"not_found","message":"could not find resource \"pg\""}`,
+			category: CategoryNotFound,
+			code:     "not_found",
+		},
+		{
+			desc:     "unauthorized",
+			status:   http.StatusUnauthorized,
+			body:     `{"code":"unauthorized","message":"unauthorized"}`,
+			category: CategoryAuth,
+			code:     "unauthorized",
+			hints:    true,
+		},
+		{
+			desc:     "validation",
+			status:   http.StatusUnprocessableEntity,
+			body:     `{"code":"invalid_input","message":"invalid input","details":{"name":["is required"]}}`,
+			category: CategoryValidation,
+			code:     "invalid_input",
+		},
+		{
+			desc:     "without a JSON body",
+			status:   http.StatusBadGateway,
+			body:     "bad gateway",
+			category: CategoryRemote,
+			code:     "api_error",
+		},
+		{
+			desc:     "conflict without a JSON body",
+			status:   http.StatusConflict,
+			body:     "conflict",
+			category: CategoryConflict,
+			code:     "conflict",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := apiClient(t, tc.status, tc.body).GetResourceByNameOrID(context.Background(), "pg")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			e := From(fmt.Errorf("describing resource: %w", err))
+			if e.Category != tc.category || e.Code != tc.code {
+				t.Fatalf("expected %s error %q, got %s error %q", tc.category, tc.code, e.Category, e.Code)
+			}
+			if e.Error() != "describing resource: "+err.Error() {
+				t.Fatalf("expected the message to be kept, got %q", e.Error())
+			}
+			if (len(e.Hints) > 0) != tc.hints {
+				t.Fatalf("unexpected hints %v", e.Hints)
+			}
+		})
+	}
+}
+
+func TestFromAPIErrorDetails(t *testing.T) {
+	_, err := apiClient(t, http.StatusUnprocessableEntity,
+		`{"code":"invalid_input","message":"invalid input","details":{"name":["is required"]}}`).
+		GetResourceByNameOrID(context.Background(), "pg")
+
+	e := From(err)
+	if got := e.Details["name"]; len(got) != 1 || got[0] != "is required" {
+		t.Fatalf("expected details of the field name, got %v", e.Details)
+	}
+}
+
+type netError struct {
+	timeout bool
+}
+
+func (e netError) Error() string   { return "dial tcp: connection refused" }
+func (e netError) Timeout() bool   { return e.timeout }
+func (e netError) Temporary() bool { return false }
+
+var _ net.Error = netError{}
+
+func TestFrom(t *testing.T) {
+	typed := New(CategoryConflict, "app_exists", "app already exists")
+
+	tests := []struct {
+		desc     string
+		err      error
+		category Category
+		code     string
+	}{
+		{desc: "typed error", err: fmt.Errorf("deploying: %w", typed), category: CategoryConflict, code: "app_exists"},
+		{desc: "deadline", err: fmt.Errorf("waiting: %w", context.DeadlineExceeded), category: CategoryTimeout, code: "timeout"},
+		{desc: "network error", err: &net.OpError{Op: "dial", Err: netError{}}, category: CategoryRemote, code: "network_error"},
+		{desc: "network timeout", err: netError{timeout: true}, category: CategoryTimeout, code: "network_timeout"},
+		{desc: "unknown flag", err: errors.New("unknown flag: --foo"), category: CategoryValidation, code: "invalid_usage"},
+		{desc: "arguments", err: errors.New(`accepts 1 arg(s), received 2`), category: CategoryValidation, code: "invalid_usage"},
+		{desc: "other", err: errors.New("something went wrong"), category: CategoryInternal, code: "error"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			e := From(tc.err)
+			if e.Category != tc.category || e.Code != tc.code {
+				t.Fatalf("expected %s error %q, got %s error %q", tc.category, tc.code, e.Category, e.Code)
+			}
+			if !errors.Is(e, tc.err) && e != typed {
+				t.Fatalf("expected the error to wrap %v", tc.err)
+			}
+		})
+	}
+
+	if From(nil) != nil {
+		t.Fatal("expected no error")
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	codes := map[Category]int{
+		CategoryInternal:   1,
+		CategoryValidation: 2,
+		CategoryAuth:       3,
+		CategoryNotFound:   4,
+		CategoryConflict:   5,
+		CategoryRemote:     6,
+		CategoryTimeout:    7,
+		Category("other"):  1,
+	}
+	for c, want := range codes {
+		if got := New(c, "code", "message").ExitCode(); got != want {
+			t.Errorf("expected %s errors to exit with %d, got %d", c, want, got)
+		}
+	}
+}
+
+func TestErrorMarshalJSON(t *testing.T) {
+	e := New(CategoryNotFound, "app_not_found", `app "my-app" not found`, "Run `meroxa apps list` to list your apps")
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	want := `{"error":{"category":"not-found","code":"app_not_found","message":"app \"my-app\" not found",` +
+		`"hints":["Run ` + "`meroxa apps list`" + ` to list your apps"],"exit_code":4}}`
+	if string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+}
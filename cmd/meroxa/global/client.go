@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -46,6 +47,11 @@ func userInfoStale() bool {
 }
 
 func GetCLIUserInfo() (actor, actorUUID string, err error) {
+	// There's no user behind an API key, and nothing to refresh.
+	if UsingServiceAccount() {
+		return ServiceAccountActor, "", nil
+	}
+
 	// Require login
 	_, _, err = GetUserToken()
 
@@ -105,77 +111,160 @@ func GetCLIUserInfo() (actor, actorUUID string, err error) {
 }
 
 func GetUserToken() (accessToken, refreshToken string, err error) {
-	accessToken = Config.GetString(AccessTokenEnv)
-	refreshToken = Config.GetString(RefreshTokenEnv)
+	c, err := Credentials()
+	if err != nil {
+		return "", "", err
+	}
+
+	accessToken = c.GetString(AccessTokenEnv)
+	refreshToken = c.GetString(RefreshTokenEnv)
+	if err = c.Err(); err != nil {
+		return "", "", fmt.Errorf("could not read credentials from the %s store: %w", c.Store().Name(), err)
+	}
 	if accessToken == "" && refreshToken == "" {
 		// we need at least one token for creating an authenticated client
-		return "", "", errors.New("please login or signup by running 'meroxa login'")
+		return "", "", errNotLoggedIn
 	}
 
 	return accessToken, refreshToken, nil
 }
 
+// apiKeyAccountUUID is the account requests authenticated with an API key are made for. It's only kept in
+// memory, so that it doesn't replace the account of the user logged in.
+var apiKeyAccountUUID string
+
 func SetAccountUUID(client meroxa.Client) error {
+	account, err := defaultAccountUUID(client)
+	if err != nil {
+		return err
+	}
+	// write account uuid
+	Config.Set(UserAccountUUID, account) // TODO add account ID
+	return nil
+}
+
+// defaultAccountUUID returns the first account the client has access to.
+func defaultAccountUUID(client meroxa.Client) (string, error) {
 	// loading current user accounts
 	accounts, err := client.ListAccounts(context.Background())
 	if err != nil {
-		return fmt.Errorf("meroxa: could not fetch user accounts: %v", err)
+		return "", fmt.Errorf("meroxa: could not fetch user accounts: %v", err)
 	}
 	if len(accounts) <= 0 {
-		return fmt.Errorf("meroxa: no accounts created for this account, please create them in the website")
+		return "", fmt.Errorf("meroxa: no accounts created for this account, please create them in the website")
 	}
-	// write account uuid
-	Config.Set(UserAccountUUID, accounts[0].UUID) // TODO add account ID
-	return nil
+	return accounts[0].UUID, nil
 }
 
 func NewClient() (meroxa.Client, error) {
-	accessToken, refreshToken, err := GetUserToken()
+	apiKey := GetAPIKey()
+
+	var accessToken, refreshToken string
+	if apiKey == "" {
+		var err error
+		// Recordings can be replayed without logging in.
+		if accessToken, refreshToken, err = GetUserToken(); err != nil && !(Replaying() && errors.Is(err, errNotLoggedIn)) {
+			return nil, err
+		}
+	}
+
+	// Requests are recorded as they're sent, with the credentials added to them and once for every attempt.
+	transport, err := cassetteTransport(http.DefaultTransport)
 	if err != nil {
 		return nil, err
 	}
-
+	if apiKey != "" {
+		transport = &apiKeyTransport{apiKey: apiKey, base: transport}
+	}
 	options := []meroxa.Option{
 		meroxa.WithUserAgent(fmt.Sprintf("Meroxa CLI %s", Version)),
+		// WithClient needs to be added first since it replaces the client other options configure.
+		// The timeout applies to each attempt of a request rather than to all of them.
+		meroxa.WithClient(&http.Client{
+			Transport: &retryTransport{
+				base:    transport,
+				retries: Retries(),
+				backoff: retryBackoff,
+				timeout: flagTimeout,
+			},
+		}),
 	}
 
 	if flagDebug {
 		options = append(options, meroxa.WithDumpTransport(os.Stdout))
 	}
-	if flagTimeout != 0 {
-		options = append(options, meroxa.WithClientTimeout(flagTimeout))
+	options = append(options, meroxa.WithBaseURL(APIURL()))
+
+	if apiKey == "" && (accessToken != "" || refreshToken != "") {
+		// WithAuthentication needs to be added after WithDumpTransport
+		// to catch requests to auth0
+		options = append(options, meroxa.WithAuthentication(
+			&oauth2.Config{
+				ClientID: GetMeroxaAuthClientID(),
+				Endpoint: oauthEndpoint(GetMeroxaAuthDomain()),
+			},
+			accessToken,
+			refreshToken,
+			onTokenRefreshed,
+		))
 	}
-	if flagAPIURL != "" {
-		options = append(options, meroxa.WithBaseURL(flagAPIURL))
-	}
-
-	// WithAuthentication needs to be added after WithDumpTransport
-	// to catch requests to auth0
-	options = append(options, meroxa.WithAuthentication(
-		&oauth2.Config{
-			ClientID: GetMeroxaAuthClientID(),
-			Endpoint: oauthEndpoint(GetMeroxaAuthDomain()),
-		},
-		accessToken,
-		refreshToken,
-		onTokenRefreshed,
-	))
-
-	// If account is not set, set account as the default account
-	if Config.GetString(UserAccountUUID) == "" {
-		client, err := meroxa.New(options...)
-		if err != nil {
-			return nil, err
+
+	if apiKey != "" {
+		// The account of the user logged in isn't used nor replaced with the account of the API key.
+		apiKeyAccountUUID = getEnvVal([]string{DefaultAccountEnv}, "")
+		if apiKeyAccountUUID == "" {
+			client, err := meroxa.New(options...)
+			if err != nil {
+				return nil, err
+			}
+			if apiKeyAccountUUID, err = defaultAccountUUID(client); err != nil {
+				return nil, err
+			}
 		}
-		if err = SetAccountUUID(client); err != nil {
-			return nil, err
+	} else if Config.GetString(UserAccountUUID) == "" {
+		// If account is not set, set account as the default account
+		if account := getEnvVal([]string{DefaultAccountEnv}, ""); account != "" {
+			// The account chosen for a context is kept across logins.
+			Config.Set(UserAccountUUID, account)
+		} else {
+			client, err := meroxa.New(options...)
+			if err != nil {
+				return nil, err
+			}
+			if err = SetAccountUUID(client); err != nil {
+				return nil, err
+			}
 		}
 	}
-	options = append(options, meroxa.WithAccountUUID(Config.GetString(UserAccountUUID)))
+	options = append(options, meroxa.WithAccountUUID(AccountUUID()))
 	options = append(options, meroxa.WithHeader("Meroxa-CLI-Version", Version))
 	return meroxa.New(options...)
 }
 
+// APIURL returns the base URL of the Meroxa API requests are sent to, set with --api-url, by the context
+// (e.g. staging) or with MEROXA_API_URL.
+func APIURL() string {
+	if flagAPIURL != "" {
+		return flagAPIURL
+	}
+	// Contexts can point to another API, e.g. staging.
+	if apiURL := getEnvVal([]string{APIURLEnv}, ""); apiURL != "" {
+		return apiURL
+	}
+	return defaultAPIURL
+}
+
+// AccountUUID returns the account requests to the Meroxa API are made for.
+func AccountUUID() string {
+	if UsingServiceAccount() {
+		return apiKeyAccountUUID
+	}
+	if Config == nil {
+		return ""
+	}
+	return Config.GetString(UserAccountUUID)
+}
+
 func oauthEndpoint(domain string) oauth2.Endpoint {
 	return oauth2.Endpoint{
 		AuthURL:  fmt.Sprintf("https://%s/authorize", domain),
@@ -185,7 +274,13 @@ func oauthEndpoint(domain string) oauth2.Endpoint {
 
 // onTokenRefreshed tries to save the new token in the config.
 func onTokenRefreshed(token *oauth2.Token) {
-	Config.Set(AccessTokenEnv, token.AccessToken)
-	Config.Set(RefreshTokenEnv, token.RefreshToken)
-	_ = Config.WriteConfig() // ignore error, it's a best effort
+	c, err := Credentials()
+	if err != nil {
+		return
+	}
+	c.Set(AccessTokenEnv, token.AccessToken)
+	c.Set(RefreshTokenEnv, token.RefreshToken)
+	// ignore errors, it's a best effort
+	_ = c.Save()
+	_ = Config.WriteConfig()
 }
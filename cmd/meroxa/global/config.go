@@ -31,10 +31,17 @@ const (
 	envPrefix = "MEROXA"
 	envName   = "config"
 	envType   = "env"
+
+	defaultAPIURL = "https://api.meroxa.io"
 )
 
 func GetMeroxaAPIURL() string {
-	return getEnvVal([]string{"MEROXA_API_URL"}, "https://api.meroxa.io")
+	return getEnvVal([]string{APIURLEnv}, defaultAPIURL)
+}
+
+// BuildCacheFile returns the file where the images built for Turbine apps are remembered, next to the configuration.
+func BuildCacheFile() string {
+	return filepath.Join(configDir, "build-cache.json")
 }
 
 func GetMeroxaAuthAudience() string {
@@ -99,18 +106,56 @@ func getEnvVal(keys []string, defaultVal string) string {
 }
 
 func readConfig() (*viper.Viper, error) {
+	cfg, err := readMainConfig()
+	if err != nil {
+		return nil, err
+	}
+	mainConfig = cfg
+
+	// Credentials and settings of a context other than the default one are read from its own file.
+	name := resolveContext(cfg)
+	if name != DefaultContext {
+		if cfg, err = readContextConfig(name); err != nil {
+			return nil, err
+		}
+	}
+	currentContext = name
+	credentials = nil
+
+	// TODO remove this code once we migrate acceptance tests to use new env variable
+	if apiURL, ok := os.LookupEnv("API_URL"); ok {
+		os.Setenv("MEROXA_API_URL", apiURL)
+	}
+
+	// When we bind flags to environment variables expect that the
+	// environment variables are prefixed, e.g. a flag like --number
+	// binds to an environment variable MEROXA_NUMBER. This helps
+	// avoid conflicts.
+	cfg.SetEnvPrefix(envPrefix)
+
+	// Add support for flags like --favorite-color by replacing - with _.
+	cfg.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+
+	// Bind to environment variables.
+	cfg.AutomaticEnv()
+
+	return cfg, nil
+}
+
+func readMainConfig() (*viper.Viper, error) {
 	cfg := viper.New()
 
 	if flagCLIConfigFile != "" {
 		// Use config file from the flag.
 		cfg.SetConfigFile(flagCLIConfigFile)
+		configDir = filepath.Dir(flagCLIConfigFile)
 	} else {
 		// Find home directory.
-		configDir, err := os.UserConfigDir()
+		dir, err := os.UserConfigDir()
 		if err != nil {
 			return nil, fmt.Errorf("could not get config directory: %w", err)
 		}
-		configDir = filepath.Join(configDir, "meroxa")
+		configDir = filepath.Join(dir, "meroxa")
 
 		// create subdirectory if it doesn't exist, otherwise viper will complain
 		err = os.MkdirAll(configDir, 0o755)
@@ -139,23 +184,6 @@ func readConfig() (*viper.Viper, error) {
 		}
 	}
 
-	// TODO remove this code once we migrate acceptance tests to use new env variable
-	if apiURL, ok := os.LookupEnv("API_URL"); ok {
-		os.Setenv("MEROXA_API_URL", apiURL)
-	}
-
-	// When we bind flags to environment variables expect that the
-	// environment variables are prefixed, e.g. a flag like --number
-	// binds to an environment variable MEROXA_NUMBER. This helps
-	// avoid conflicts.
-	cfg.SetEnvPrefix(envPrefix)
-
-	// Add support for flags like --favorite-color by replacing - with _.
-	cfg.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
-
-	// Bind to environment variables.
-	cfg.AutomaticEnv()
-
 	return cfg, nil
 }
 
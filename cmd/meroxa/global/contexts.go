@@ -0,0 +1,243 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package global
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// DefaultContext is the context using the main configuration file.
+	DefaultContext = "default"
+
+	contextsDir = "contexts"
+)
+
+var contextNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+var (
+	// configDir is the directory of the main configuration file, contexts are stored next to it.
+	configDir string
+	// mainConfig is the main configuration file, which holds the current context.
+	mainConfig     *viper.Viper
+	currentContext = DefaultContext
+)
+
+// Context is a named set of credentials and settings, stored in its own configuration file.
+type Context struct {
+	Name        string `json:"name"`
+	Current     bool   `json:"current"`
+	APIURL      string `json:"api_url,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	AccountUUID string `json:"account_uuid,omitempty"`
+	Actor       string `json:"actor,omitempty"`
+	LoggedIn    bool   `json:"logged_in"`
+}
+
+// CurrentContext returns the name of the context used by this command.
+func CurrentContext() string {
+	return currentContext
+}
+
+// GetDefaultEnvironment returns the environment used by commands when none is given.
+func GetDefaultEnvironment() string {
+	return getEnvVal([]string{DefaultEnvironmentEnv}, "")
+}
+
+// resolveContext returns the context to use, in order of precedence: the --context flag, the MEROXA_CONTEXT
+// environment variable and the current context saved in the main configuration file.
+func resolveContext(main *viper.Viper) string {
+	if flagContext != "" {
+		return flagContext
+	}
+	if name, ok := os.LookupEnv(ContextEnv); ok && name != "" {
+		return name
+	}
+	if name := main.GetString(CurrentContextEnv); name != "" {
+		return name
+	}
+	return DefaultContext
+}
+
+func contextFile(name string) string {
+	return filepath.Join(configDir, contextsDir, name+"."+envType)
+}
+
+func readContextConfig(name string) (*viper.Viper, error) {
+	path := contextFile(name)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("context %q does not exist, run `meroxa contexts create %s` to create it", name, name)
+		}
+		return nil, err
+	}
+
+	cfg := viper.New()
+	cfg.SetConfigFile(path)
+	cfg.SetConfigType(envType)
+	if err := cfg.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("could not read context %q: %w", name, err)
+	}
+	return cfg, nil
+}
+
+func validateContextName(name string) error {
+	if !contextNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid context name %q, use letters, digits, '.', '-' and '_' only", name)
+	}
+	return nil
+}
+
+// ListContexts returns the default context followed by the other contexts sorted by name.
+func ListContexts() ([]Context, error) {
+	contexts := []Context{newContext(DefaultContext, mainConfig)}
+
+	files, err := filepath.Glob(filepath.Join(configDir, contextsDir, "*."+envType))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), "."+envType)
+		cfg, err := readContextConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		contexts = append(contexts, newContext(name, cfg))
+	}
+	return contexts, nil
+}
+
+func newContext(name string, cfg *viper.Viper) Context {
+	c := Context{
+		Name:    name,
+		Current: name == currentContext,
+		APIURL:  "https://api.meroxa.io",
+	}
+	if cfg == nil {
+		return c
+	}
+	if url := cfg.GetString(APIURLEnv); url != "" {
+		c.APIURL = url
+	}
+	c.Environment = cfg.GetString(DefaultEnvironmentEnv)
+	c.AccountUUID = cfg.GetString(DefaultAccountEnv)
+	if c.AccountUUID == "" {
+		c.AccountUUID = cfg.GetString(UserAccountUUID)
+	}
+	c.Actor = cfg.GetString(ActorEnv)
+	c.LoggedIn = hasCredentials(cfg, name)
+	return c
+}
+
+// CreateContext creates a context with the given settings, e.g. MEROXA_API_URL.
+func CreateContext(name string, settings map[string]string) error {
+	if err := validateContextName(name); err != nil {
+		return err
+	}
+	if name == DefaultContext {
+		return fmt.Errorf("context %q already exists", name)
+	}
+
+	path := contextFile(name)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("context %q already exists", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("could not create contexts directory: %w", err)
+	}
+
+	cfg := viper.New()
+	cfg.SetConfigFile(path)
+	cfg.SetConfigType(envType)
+	for k, v := range settings {
+		if v != "" {
+			cfg.Set(k, v)
+		}
+	}
+	if err := cfg.WriteConfig(); err != nil {
+		return fmt.Errorf("could not write context %q: %w", name, err)
+	}
+	// Contexts hold credentials once logged in.
+	return os.Chmod(path, 0o600)
+}
+
+// UseContext saves name as the current context, used by the following commands.
+func UseContext(name string) error {
+	if name != DefaultContext {
+		if _, err := readContextConfig(name); err != nil {
+			return err
+		}
+		mainConfig.Set(CurrentContextEnv, name)
+	} else {
+		mainConfig.Set(CurrentContextEnv, "")
+	}
+	return writeMainConfig()
+}
+
+// DeleteContext deletes a context, switching back to the default context when it's the current one.
+func DeleteContext(name string) error {
+	if name == DefaultContext {
+		return fmt.Errorf("context %q can't be deleted", name)
+	}
+	if _, err := readContextConfig(name); err != nil {
+		return err
+	}
+	if err := os.Remove(contextFile(name)); err != nil {
+		return err
+	}
+
+	if mainConfig.GetString(CurrentContextEnv) == name {
+		return UseContext(DefaultContext)
+	}
+	return nil
+}
+
+func writeMainConfig() error {
+	err := mainConfig.WriteConfig()
+	if err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			err = mainConfig.SafeWriteConfig()
+		}
+		if err != nil {
+			return fmt.Errorf("could not write config file: %w", err)
+		}
+	}
+	return nil
+}
+
+// hasCredentials reports whether a context holds tokens, without reading them from their store.
+func hasCredentials(cfg *viper.Viper, name string) bool {
+	creds, err := credentialsFor(cfg, name)
+	if err != nil {
+		return false
+	}
+	for _, k := range credentialKeys {
+		if ok, err := creds.Has(k); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package global
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setupContextsTest(t *testing.T) {
+	t.Helper()
+
+	oldConfig, oldMain, oldFile, oldContext := Config, mainConfig, flagCLIConfigFile, flagContext
+	t.Cleanup(func() {
+		Config, mainConfig, flagCLIConfigFile, flagContext = oldConfig, oldMain, oldFile, oldContext
+		currentContext = DefaultContext
+	})
+
+	flagCLIConfigFile = filepath.Join(t.TempDir(), "config.env")
+	if err := os.WriteFile(flagCLIConfigFile, []byte("ACCESS_TOKEN=main-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	flagContext = ""
+	t.Setenv(ContextEnv, "")
+}
+
+func loadConfig(t *testing.T) {
+	t.Helper()
+
+	cfg, err := readConfig()
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	Config = cfg
+}
+
+func TestContexts(t *testing.T) {
+	setupContextsTest(t)
+	loadConfig(t)
+
+	err := CreateContext("staging", map[string]string{
+		APIURLEnv:             "https://api.staging.meroxa.io",
+		DefaultEnvironmentEnv: "my-env",
+	})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if err = CreateContext("staging", nil); err == nil || err.Error() != `context "staging" already exists` {
+		t.Fatalf("expected context to already exist, got %v", err)
+	}
+	if err = CreateContext("../prod", nil); err == nil {
+		t.Fatal("expected invalid context name error")
+	}
+
+	if err = UseContext("staging"); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	loadConfig(t)
+
+	if got := CurrentContext(); got != "staging" {
+		t.Fatalf("expected current context %q, got %q", "staging", got)
+	}
+	if got := Config.GetString(AccessTokenEnv); got != "" {
+		t.Fatalf("expected no credentials in context %q, got %q", "staging", got)
+	}
+	if got := GetDefaultEnvironment(); got != "my-env" {
+		t.Fatalf("expected default environment %q, got %q", "my-env", got)
+	}
+
+	contexts, err := ListContexts()
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if len(contexts) != 2 {
+		t.Fatalf("expected 2 contexts, got %d", len(contexts))
+	}
+	if c := contexts[0]; c.Name != DefaultContext || c.Current || !c.LoggedIn {
+		t.Fatalf("unexpected default context %+v", c)
+	}
+	if c := contexts[1]; c.Name != "staging" || !c.Current || c.APIURL != "https://api.staging.meroxa.io" {
+		t.Fatalf("unexpected staging context %+v", c)
+	}
+
+	if err = DeleteContext("staging"); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	loadConfig(t)
+
+	if got := CurrentContext(); got != DefaultContext {
+		t.Fatalf("expected current context %q, got %q", DefaultContext, got)
+	}
+	if got := Config.GetString(AccessTokenEnv); got != "main-token" {
+		t.Fatalf("expected credentials of the default context, got %q", got)
+	}
+}
+
+func TestResolveContext(t *testing.T) {
+	setupContextsTest(t)
+	loadConfig(t)
+
+	for _, name := range []string{"staging", "production"} {
+		if err := CreateContext(name, nil); err != nil {
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+	}
+	if err := UseContext("staging"); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	t.Setenv(ContextEnv, "production")
+	loadConfig(t)
+	if got := CurrentContext(); got != "production" {
+		t.Fatalf("expected %s to override the current context, got %q", ContextEnv, got)
+	}
+
+	flagContext = DefaultContext
+	loadConfig(t)
+	if got := CurrentContext(); got != DefaultContext {
+		t.Fatalf("expected --context to override %s, got %q", ContextEnv, got)
+	}
+
+	flagContext = "unknown"
+	_, err := readConfig()
+	want := "context \"unknown\" does not exist, run `meroxa contexts create unknown` to create it"
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+}
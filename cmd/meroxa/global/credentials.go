@@ -0,0 +1,225 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package global
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+
+	"github.com/meroxa/cli/cmd/meroxa/clierrors"
+	"github.com/meroxa/cli/config"
+)
+
+const (
+	// CredentialsStoreConfig keeps credentials in the configuration file, in plaintext.
+	CredentialsStoreConfig        = "config"
+	CredentialsStoreSecretService = "secret-service"
+	CredentialsStoreEncryptedFile = "encrypted-file"
+	CredentialsStoreFile          = "file"
+)
+
+// CredentialsStores are the names of the available credentials stores.
+var CredentialsStores = []string{
+	CredentialsStoreConfig,
+	CredentialsStoreSecretService,
+	CredentialsStoreEncryptedFile,
+	CredentialsStoreFile,
+}
+
+// credentialKeys are kept in the credentials store instead of the configuration file.
+var credentialKeys = []string{AccessTokenEnv, RefreshTokenEnv}
+
+var credentials *config.CredentialConfig
+
+// Credentials returns the configuration of the current context, with its credentials read from and written to
+// the configured credentials store. SaveCredentials needs to be called for changes to be written.
+func Credentials() (*config.CredentialConfig, error) {
+	if Config == nil {
+		return nil, errors.New("meroxa: configuration was not read")
+	}
+	if credentials == nil {
+		c, err := credentialsFor(Config, currentContext)
+		if err != nil {
+			return nil, err
+		}
+		credentials = c
+	}
+	return credentials, nil
+}
+
+// SaveCredentials writes the credentials changed through Credentials to their store.
+func SaveCredentials() error {
+	if credentials == nil {
+		return nil
+	}
+	return credentials.Save()
+}
+
+// credentialsFor returns the configuration of a context, cfg, with its credentials read from and written to the
+// store configured either in cfg or through the MEROXA_CREDENTIALS_STORE environment variable.
+func credentialsFor(cfg *viper.Viper, context string) (*config.CredentialConfig, error) {
+	kind := cfg.GetString(CredentialsStoreEnv)
+	if v, ok := os.LookupEnv(CredentialsStoreEnv); ok && v != "" {
+		kind = v
+	}
+	store, err := NewCredentialStore(kind, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return config.NewCredentialConfig(cfg, store, context, credentialKeys...), nil
+}
+
+// NewCredentialStore returns the credentials store named kind. The config store keeps credentials in cfg.
+func NewCredentialStore(kind string, cfg *viper.Viper) (config.CredentialStore, error) {
+	switch kind {
+	case "", CredentialsStoreConfig:
+		return &configStore{cfg: cfg}, nil
+	case CredentialsStoreSecretService:
+		return config.NewSecretServiceStore(), nil
+	case CredentialsStoreEncryptedFile:
+		return config.NewEncryptedFileStore(filepath.Join(configDir, "credentials.enc.json"), credentialsPassphrase), nil
+	case CredentialsStoreFile:
+		return config.NewFileStore(filepath.Join(configDir, "credentials.json")), nil
+	}
+	return nil, fmt.Errorf("unknown credentials store %q, use one of %s", kind, strings.Join(CredentialsStores, ", "))
+}
+
+// AppSecrets returns the store of the secrets of the Turbine app named appName. They are encrypted with the same
+// passphrase as the encrypted credentials file.
+func AppSecrets(appName string) *config.EncryptedFileStore {
+	return config.NewEncryptedFileStore(filepath.Join(configDir, "apps", appName, "secrets.enc.json"), credentialsPassphrase)
+}
+
+// credentialsPassphrase reads the passphrase of the encrypted credentials file from the environment, or asks
+// for it when running in a terminal.
+func credentialsPassphrase() (string, error) {
+	if v, ok := os.LookupEnv(CredentialsPassphraseEnv); ok {
+		return v, nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("set %s to read the encrypted credentials", CredentialsPassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, "Passphrase of your Meroxa credentials: ")
+	b, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// configStore keeps credentials in the configuration file, which is what the CLI always did.
+type configStore struct {
+	cfg *viper.Viper
+}
+
+func (s *configStore) Name() string {
+	return CredentialsStoreConfig
+}
+
+// key drops the namespace since each context has its own configuration file.
+func (s *configStore) key(key string) string {
+	return filepath.Base(key)
+}
+
+func (s *configStore) Get(key string) (string, error) {
+	v := s.cfg.GetString(s.key(key))
+	if v == "" {
+		return "", config.ErrCredentialNotFound
+	}
+	return v, nil
+}
+
+func (s *configStore) Has(key string) (bool, error) {
+	return s.cfg.GetString(s.key(key)) != "", nil
+}
+
+func (s *configStore) Set(key, value string) error {
+	s.cfg.Set(s.key(key), value)
+	return nil
+}
+
+func (s *configStore) Delete(key string) error {
+	s.cfg.Set(s.key(key), "")
+	return nil
+}
+
+var errNotLoggedIn = clierrors.New(clierrors.CategoryAuth, "not_logged_in", "please login or signup by running 'meroxa login'")
+
+// MigrateCredentials moves the credentials of the current context to the store named to, and saves it as the
+// store to use from now on. The name of the previous store is returned.
+func MigrateCredentials(to string) (string, error) {
+	from, err := Credentials()
+	if err != nil {
+		return "", err
+	}
+	if from.Store().Name() == to {
+		return "", fmt.Errorf("credentials are already kept in the %s store", to)
+	}
+	store, err := NewCredentialStore(to, Config)
+	if err != nil {
+		return "", err
+	}
+
+	values := make(map[string]string, len(credentialKeys))
+	for _, k := range credentialKeys {
+		values[k] = from.GetString(k)
+	}
+	if err = from.Err(); err != nil {
+		return "", fmt.Errorf("could not read credentials from the %s store: %w", from.Store().Name(), err)
+	}
+
+	target := config.NewCredentialConfig(Config, store, currentContext, credentialKeys...)
+	for k, v := range values {
+		target.Set(k, v)
+	}
+	if err = target.Save(); err != nil {
+		return "", fmt.Errorf("could not write credentials to the %s store: %w", to, err)
+	}
+
+	// Read the credentials back before removing them from the previous store.
+	check := config.NewCredentialConfig(Config, store, currentContext, credentialKeys...)
+	for k, v := range values {
+		if got := check.GetString(k); got != v {
+			return "", fmt.Errorf("could not verify credentials written to the %s store", to)
+		}
+	}
+	if err = check.Err(); err != nil {
+		return "", fmt.Errorf("could not verify credentials written to the %s store: %w", to, err)
+	}
+
+	for k := range values {
+		from.Set(k, "")
+	}
+	if err = from.Save(); err != nil {
+		return "", fmt.Errorf("could not remove credentials from the %s store: %w", from.Store().Name(), err)
+	}
+
+	Config.Set(CredentialsStoreEnv, to)
+	if err = Config.WriteConfig(); err != nil {
+		return "", fmt.Errorf("could not write config file: %w", err)
+	}
+	credentials = check
+	return from.Store().Name(), nil
+}
@@ -23,6 +23,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+
+	"github.com/meroxa/cli/cmd/meroxa/clierrors"
+	"github.com/meroxa/cli/utils/display"
 )
 
 var (
@@ -32,18 +35,39 @@ var (
 
 var (
 	flagCLIConfigFile string
+	flagContext       string
 	flagAPIURL        string
 	flagDebug         bool
 	flagTimeout       time.Duration
+	flagRetries       int
 	flagJSON          bool
+	flagOutput        string
+	flagRecordHTTP    string
+	flagReplayHTTP    string
+
+	outputFormat = display.OutputFormat{Name: display.OutputTable}
+	outputFile   string
+	retriesFlag  *pflag.Flag
 )
 
+// OutputFileAnnotation marks the commands saving their result to the file given to --output with --dry-run.
+const OutputFileAnnotation = "meroxa.io/output-file"
+
 const (
 	AccessTokenEnv               = "ACCESS_TOKEN"
 	ActorEnv                     = "ACTOR"
 	ActorUUIDEnv                 = "ACTOR_UUID"
+	APIKeyEnv                    = "MEROXA_API_KEY"
+	APIURLEnv                    = "MEROXA_API_URL"
+	APIRetriesEnv                = "MEROXA_API_RETRIES"
 	CasedDebugEnv                = "CASED_DEBUG"
 	CasedPublishKeyEnv           = "CASED_PUBLISH_KEY"
+	ContextEnv                   = "MEROXA_CONTEXT"
+	CurrentContextEnv            = "CURRENT_CONTEXT"
+	CredentialsPassphraseEnv     = "MEROXA_CREDENTIALS_PASSPHRASE"
+	CredentialsStoreEnv          = "MEROXA_CREDENTIALS_STORE"
+	DefaultAccountEnv            = "MEROXA_ACCOUNT"
+	DefaultEnvironmentEnv        = "MEROXA_ENVIRONMENT"
 	LatestCLIVersionUpdatedAtEnv = "LATEST_CLI_VERSION_UPDATED_AT"
 	DisableNotificationsUpdate   = "DISABLE_NOTIFICATIONS_UPDATE"
 	MeroxaAuthCallbackHost       = "MEROXA_AUTH_CALLBACK_HOST"
@@ -58,11 +82,21 @@ const (
 )
 
 func RegisterGlobalFlags(cmd *cobra.Command) {
-	cmd.PersistentFlags().BoolVar(&flagJSON, "json", false, "output json")
+	cmd.PersistentFlags().BoolVar(&flagJSON, "json", false, "output json, same as --output json")
+	cmd.PersistentFlags().StringVarP(&flagOutput, "output", "o", "",
+		"output format, one of table, json, yaml, csv, wide, template=TEMPLATE or jsonpath=EXPRESSION (default table)")
 	cmd.PersistentFlags().StringVar(&flagCLIConfigFile, "cli-config-file", "", "meroxa configuration file")
+	cmd.PersistentFlags().StringVar(&flagContext, "context", "", "meroxa context to use (overrides MEROXA_CONTEXT and the current context)")
 	cmd.PersistentFlags().StringVar(&flagAPIURL, "api-url", "", "API url")
 	cmd.PersistentFlags().BoolVar(&flagDebug, "debug", false, "display any debugging information")
 	cmd.PersistentFlags().DurationVar(&flagTimeout, "timeout", time.Second*10, "set the duration of the client timeout in seconds") //nolint:lll
+	cmd.PersistentFlags().IntVar(&flagRetries, "retries", DefaultRetries,
+		"number of times to retry API requests failing with a transient error (overrides MEROXA_API_RETRIES)")
+	retriesFlag = cmd.PersistentFlags().Lookup("retries")
+	cmd.PersistentFlags().StringVar(&flagRecordHTTP, "record-http", "",
+		"record requests to the Meroxa API and their responses to a HAR file, without credentials")
+	cmd.PersistentFlags().StringVar(&flagReplayHTTP, "replay-http", "",
+		"replay responses recorded with --record-http instead of sending requests to the Meroxa API")
 
 	if err := cmd.PersistentFlags().MarkHidden("api-url"); err != nil {
 		panic(fmt.Sprintf("could not mark flag as hidden: %v", err))
@@ -81,9 +115,60 @@ func PersistentPreRunE(cmd *cobra.Command) error {
 		return err
 	}
 
+	if flagRetries < 0 {
+		return clierrors.Newf(clierrors.CategoryValidation, "invalid_flag", "--retries must be zero or positive, got %d", flagRetries)
+	}
+	if flagRecordHTTP != "" && flagReplayHTTP != "" {
+		return clierrors.New(clierrors.CategoryValidation, "invalid_flag", "--record-http can't be used with --replay-http")
+	}
+	// Each command starts a new recording.
+	httpCassette = nil
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	return parseOutput(dryRun && cmd.Annotations[OutputFileAnnotation] != "")
+}
+
+// parseOutput parses --output. When the command saves its result to a file, a value that isn't an output format is
+// that file, e.g. apps deploy --dry-run --output spec.json. Other commands only accept output formats.
+func parseOutput(savesFile bool) error {
+	outputFile = ""
+	if savesFile && flagOutput != "" {
+		if _, err := display.ParseOutputFormat(flagOutput); err != nil {
+			outputFile, flagOutput = flagOutput, ""
+		}
+	}
+	return parseOutputFormat()
+}
+
+func parseOutputFormat() error {
+	if flagJSON {
+		if flagOutput != "" && flagOutput != display.OutputJSON {
+			return clierrors.Newf(clierrors.CategoryValidation, "invalid_output_format",
+				"--json can't be used with --output %s", flagOutput)
+		}
+		flagOutput = display.OutputJSON
+	}
+
+	f, err := display.ParseOutputFormat(flagOutput)
+	if err != nil {
+		return clierrors.Wrap(err, clierrors.CategoryValidation, "invalid_output_format")
+	}
+	outputFormat = f
 	return nil
 }
 
+// OutputFile returns the file given to --output with --dry-run, if any, for commands annotated with
+// OutputFileAnnotation.
+func OutputFile() string {
+	return outputFile
+}
+
+// StructuredOutput reports whether the output is meant for scripts, e.g. --output json, in which case
+// only the result of commands is printed.
+func StructuredOutput() bool {
+	return flagJSON || outputFormat.Structured()
+}
+
 // Bind each cobra flag to its associated viper configuration (config file and environment variable).
 func bindFlags(cmd *cobra.Command, v *viper.Viper) error {
 	var err error
@@ -0,0 +1,128 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package global
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/meroxa/cli/utils/display"
+	"github.com/spf13/viper"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		desc    string
+		json    bool
+		output  string
+		want    string
+		wantErr string
+	}{
+		{desc: "defaults to table", want: display.OutputTable},
+		{desc: "--output", output: "yaml", want: display.OutputYAML},
+		{desc: "--json", json: true, want: display.OutputJSON},
+		{desc: "--json with --output json", json: true, output: "json", want: display.OutputJSON},
+		{desc: "--json with another format", json: true, output: "yaml", wantErr: "--json can't be used with --output yaml"},
+		{desc: "unknown format", output: "xml", wantErr: `unknown output format "xml"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			oldJSON, oldOutput, oldFormat := flagJSON, flagOutput, outputFormat
+			t.Cleanup(func() { flagJSON, flagOutput, outputFormat = oldJSON, oldOutput, oldFormat })
+			flagJSON, flagOutput = tc.json, tc.output
+
+			err := parseOutputFormat()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if outputFormat.Name != tc.want {
+				t.Fatalf("expected format %q, got %q", tc.want, outputFormat.Name)
+			}
+			if StructuredOutput() != (tc.want != display.OutputTable) {
+				t.Fatalf("unexpected StructuredOutput() for %q", tc.want)
+			}
+		})
+	}
+}
+
+func TestParseOutputWithDryRun(t *testing.T) {
+	tests := []struct {
+		desc      string
+		savesFile bool
+		output    string
+		wantFile  string
+		want      string
+		wantErr   string
+	}{
+		{desc: "file for a command saving files", savesFile: true, output: "spec.json", wantFile: "spec.json", want: display.OutputTable},
+		{desc: "format for a command saving files", savesFile: true, output: "yaml", want: display.OutputYAML},
+		{desc: "file for other commands", output: "spec.json", wantErr: `unknown output format "spec.json"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			oldJSON, oldOutput, oldFormat, oldFile := flagJSON, flagOutput, outputFormat, outputFile
+			t.Cleanup(func() { flagJSON, flagOutput, outputFormat, outputFile = oldJSON, oldOutput, oldFormat, oldFile })
+			flagJSON, flagOutput = false, tc.output
+
+			err := parseOutput(tc.savesFile)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if OutputFile() != tc.wantFile {
+				t.Fatalf("expected output file %q, got %q", tc.wantFile, OutputFile())
+			}
+			if outputFormat.Name != tc.want {
+				t.Fatalf("expected format %q, got %q", tc.want, outputFormat.Name)
+			}
+		})
+	}
+}
+
+func TestAPIURL(t *testing.T) {
+	oldFlag, oldConfig := flagAPIURL, Config
+	t.Cleanup(func() { flagAPIURL, Config = oldFlag, oldConfig })
+	t.Setenv(APIURLEnv, "")
+
+	flagAPIURL, Config = "", viper.New()
+	if got := APIURL(); got != "https://api.meroxa.io" {
+		t.Fatalf("expected the default API URL, got %q", got)
+	}
+
+	Config.Set(APIURLEnv, "https://api.staging.meroxa.io")
+	if got := APIURL(); got != "https://api.staging.meroxa.io" {
+		t.Fatalf("expected the API URL of the context, got %q", got)
+	}
+
+	flagAPIURL = "http://localhost:8080"
+	if got := APIURL(); got != flagAPIURL {
+		t.Fatalf("expected --api-url, got %q", got)
+	}
+}
@@ -0,0 +1,482 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package global
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/meroxa/cli/cmd/meroxa/clierrors"
+)
+
+// redacted replaces secrets in HTTP recordings.
+const redacted = "REDACTED"
+
+// redactedHeaders are the headers whose values are never recorded.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// redactedFields are the substrings identifying fields of JSON and form bodies and query parameters whose values are
+// never recorded, e.g. tokens refreshed with auth0 or the credentials of resources. They are matched against names
+// without their case and separators, so that aws.secretAccessKey or connection.password are redacted too.
+var redactedFields = []string{
+	"password",
+	"secret",
+	"token",
+	"privatekey",
+	"accesskey",
+	"apikey",
+	"clientcertkey",
+	"sslkey",
+	"credentials",
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+func isRedactedField(name string) bool {
+	name = nonAlphanumeric.ReplaceAllString(strings.ToLower(name), "")
+	for _, f := range redactedFields {
+		if strings.Contains(name, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// errNotRecorded is returned when replaying a request that isn't in the recording.
+var errNotRecorded = errors.New("request not recorded")
+
+// har is an HTTP Archive, see http://www.softwareishard.com/blog/har-12-spec.
+type har struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string      `json:"version"`
+	Creator harCreator  `json:"creator"`
+	Entries []*harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// httpCassette is the recording used by the clients of a command, shared so that requests of all the clients
+// made by a command end up in the same recording.
+var httpCassette http.RoundTripper
+
+// cassetteTransport returns the transport recording requests with --record-http or replaying them with
+// --replay-http, or base when neither is set.
+func cassetteTransport(base http.RoundTripper) (http.RoundTripper, error) {
+	if httpCassette != nil {
+		return httpCassette, nil
+	}
+
+	switch {
+	case flagRecordHTTP != "":
+		httpCassette = &harRecorder{
+			path: flagRecordHTTP,
+			base: base,
+			har:  har{Log: harLog{Version: "1.2", Creator: harCreator{Name: "meroxa", Version: Version}}},
+		}
+	case flagReplayHTTP != "":
+		r, err := loadHARReplayer(flagReplayHTTP)
+		if err != nil {
+			return nil, err
+		}
+		httpCassette = r
+	default:
+		return base, nil
+	}
+	return httpCassette, nil
+}
+
+// Replaying reports whether responses are replayed from a recording, with --replay-http, instead of sent to
+// the Meroxa API.
+func Replaying() bool {
+	return flagReplayHTTP != ""
+}
+
+// harRecorder saves the requests it sends and their responses as an HTTP Archive, without their secrets.
+type harRecorder struct {
+	path string
+	base http.RoundTripper
+
+	mu  sync.Mutex
+	har har
+}
+
+func (t *harRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		// RoundTrippers must not modify the request they're given.
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	elapsed := float64(time.Since(start).Milliseconds())
+
+	entry := &harEntry{
+		StartedDateTime: start.UTC(),
+		Time:            elapsed,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         redactURL(withRedactedQuery(req.URL).String()),
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(req.Header),
+			QueryString: harQuery(req.URL.Query()),
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Headers:     harHeaders(resp.Header),
+			Content: harContent{
+				Size:     len(respBody),
+				MimeType: resp.Header.Get("Content-Type"),
+				Text:     redactBody(resp.Header.Get("Content-Type"), respBody),
+			},
+			HeadersSize: -1,
+			BodySize:    len(respBody),
+		},
+		Timings: harTimings{Send: -1, Wait: elapsed, Receive: -1},
+	}
+	if reqBody != nil {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     redactBody(req.Header.Get("Content-Type"), reqBody),
+		}
+	}
+
+	if err = t.record(entry); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// record adds entry to the recording, saved after every request so that it's kept even if the command fails.
+func (t *harRecorder) record(entry *harEntry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.har.Log.Entries = append(t.har.Log.Entries, entry)
+	b, err := json.MarshalIndent(t.har, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(t.path, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("could not save HTTP recording: %w", err)
+	}
+	return nil
+}
+
+// harReplayer answers requests with the responses recorded for them, in the order they were recorded.
+// Requests are matched on their method, path and query, regardless of the API they were sent to.
+type harReplayer struct {
+	path string
+
+	mu      sync.Mutex
+	entries []*harEntry
+	used    []bool
+}
+
+func loadHARReplayer(path string) (*harReplayer, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, clierrors.Wrap(fmt.Errorf("could not read HTTP recording: %w", err), clierrors.CategoryValidation, "invalid_flag")
+	}
+	var h har
+	if err = json.Unmarshal(b, &h); err != nil {
+		return nil, clierrors.Wrap(fmt.Errorf("could not parse HTTP recording %s: %w", path, err), clierrors.CategoryValidation, "invalid_flag")
+	}
+	return &harReplayer{path: path, entries: h.Log.Entries, used: make([]bool, len(h.Log.Entries))}, nil
+}
+
+func (t *harReplayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		// The body is only read to behave like a server would.
+		_, _ = io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	entry := t.next(req)
+	if entry == nil {
+		return nil, fmt.Errorf("%w: no response to %s %s in %s", errNotRecorded, req.Method, req.URL.RequestURI(), t.path)
+	}
+
+	header := make(http.Header)
+	for _, h := range entry.Response.Headers {
+		header.Add(h.Name, h.Value)
+	}
+	body := entry.Response.Content.Text
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Response.Status, entry.Response.StatusText),
+		StatusCode:    entry.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// next returns the first response recorded for req that wasn't replayed yet. Once they were all replayed,
+// the last one is replayed again, e.g. when polling for longer than during the recording.
+func (t *harReplayer) next(req *http.Request) *harEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var last *harEntry
+	for i, e := range t.entries {
+		if e.Request.Method != req.Method {
+			continue
+		}
+		u, err := url.Parse(e.Request.URL)
+		if err != nil || u.Path != req.URL.Path || u.RawQuery != withRedactedQuery(req.URL).RawQuery {
+			continue
+		}
+		if !t.used[i] {
+			t.used[i] = true
+			return e
+		}
+		last = e
+	}
+	return last
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	var values []harNameValue
+	for name, vv := range header {
+		for _, v := range vv {
+			if redactedHeaders[http.CanonicalHeaderKey(name)] {
+				v = redacted
+			}
+			values = append(values, harNameValue{Name: name, Value: v})
+		}
+	}
+	// Headers are sorted so that recordings of the same requests are the same.
+	sortNameValues(values)
+	return values
+}
+
+func harQuery(query url.Values) []harNameValue {
+	values := []harNameValue{}
+	for name, vv := range query {
+		for _, v := range vv {
+			if isRedactedField(name) {
+				v = redacted
+			}
+			values = append(values, harNameValue{Name: name, Value: v})
+		}
+	}
+	sortNameValues(values)
+	return values
+}
+
+// withRedactedQuery returns a copy of u without the values of its secret query parameters, keeping their order so
+// replayed requests can be matched against the recorded ones.
+func withRedactedQuery(u *url.URL) *url.URL {
+	r := *u
+	if r.RawQuery == "" {
+		return &r
+	}
+	params := strings.Split(r.RawQuery, "&")
+	for i, p := range params {
+		k, _, _ := strings.Cut(p, "=")
+		if name, err := url.QueryUnescape(k); err == nil && isRedactedField(name) {
+			params[i] = k + "=" + redacted
+		}
+	}
+	r.RawQuery = strings.Join(params, "&")
+	return &r
+}
+
+func sortNameValues(values []harNameValue) {
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+}
+
+// redactBody returns body without its secrets, depending on its content type.
+func redactBody(contentType string, body []byte) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return redacted
+		}
+		for name := range form {
+			if isRedactedField(name) {
+				form[name] = []string{redacted}
+			}
+		}
+		return form.Encode()
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || json.Valid(body):
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return string(body)
+		}
+		v, changed := redactJSON(v, false)
+		if !changed {
+			// Bodies without secrets are kept as they were sent.
+			return string(body)
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return redacted
+		}
+		return string(b)
+	default:
+		return string(body)
+	}
+}
+
+// redactJSON returns the decoded JSON value v without its secrets, or with all of its strings redacted when
+// it's secret, and reports whether anything was redacted.
+func redactJSON(v interface{}, secret bool) (interface{}, bool) {
+	changed := false
+	switch v := v.(type) {
+	case string:
+		if secret && v != "" {
+			return redacted, true
+		}
+		r := redactURL(v)
+		return r, r != v
+	case map[string]interface{}:
+		for k, field := range v {
+			if r, ok := redactJSON(field, secret || isRedactedField(k)); ok {
+				v[k] = r
+				changed = true
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if r, ok := redactJSON(item, secret); ok {
+				v[i] = r
+				changed = true
+			}
+		}
+	}
+	return v, changed
+}
+
+// redactURL returns s without the password it contains when it's a URL, e.g. the URL of a resource.
+func redactURL(s string) string {
+	if !strings.Contains(s, "@") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); !ok {
+		return s
+	}
+	u.User = url.UserPassword(u.User.Username(), redacted)
+	return u.String()
+}
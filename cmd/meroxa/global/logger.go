@@ -21,19 +21,24 @@ import (
 	"os"
 
 	"github.com/meroxa/cli/log"
+	"github.com/meroxa/cli/utils/display"
 )
 
-func NewLogger() log.Logger {
+// NewLogger returns the logger of a command, printing its result in the format chosen with --output.
+func NewLogger(hideHeaders bool) log.Logger {
 	var (
 		logLevel         = log.Info
 		leveledLoggerOut = os.Stdout
-		jsonLoggerOut    = io.Discard
 		spinnerLoggerOut = os.Stdout
+		formatter        = NewOutputFormatter(hideHeaders)
+		jsonLogger       = log.NewJSONLogger(io.Discard)
 	)
 
-	if flagJSON {
+	if outputFormat.Structured() {
+		// Only the result of the command is printed to stdout so that it can be parsed.
 		logLevel = log.Warn
-		jsonLoggerOut = os.Stdout
+		spinnerLoggerOut = os.Stderr
+		jsonLogger = log.NewFormattedJSONLogger(os.Stdout, formatter)
 	}
 	if flagDebug {
 		logLevel = log.Debug
@@ -41,7 +46,13 @@ func NewLogger() log.Logger {
 
 	return log.New(
 		log.NewLeveledLogger(leveledLoggerOut, logLevel),
-		log.NewJSONLogger(jsonLoggerOut),
+		jsonLogger,
 		log.NewSpinnerLogger(spinnerLoggerOut),
+		log.NewOutputLogger(os.Stdout, formatter),
 	)
 }
+
+// NewOutputFormatter returns the function rendering the result of commands in the format chosen with --output.
+func NewOutputFormatter(hideHeaders bool) log.Formatter {
+	return display.NewFormatter(outputFormat, display.TableOptions{HideHeaders: hideHeaders})
+}
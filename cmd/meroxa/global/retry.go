@@ -0,0 +1,221 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package global
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/meroxa/cli/cmd/meroxa/poll"
+)
+
+const (
+	// DefaultRetries is how many times a request to the Meroxa API is retried, unless configured otherwise.
+	DefaultRetries = 3
+
+	// IdempotencyKeyHeader lets an API honoring it, such as the emulator, recognize a create request that's sent
+	// again, so that it's only carried out once.
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// Retries returns how many times a failing request to the Meroxa API is retried, set with --retries or
+// MEROXA_API_RETRIES.
+func Retries() int {
+	if f := retriesFlag; f != nil && f.Changed {
+		return flagRetries
+	}
+	if v := getEnvVal([]string{APIRetriesEnv}, ""); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return flagRetries
+}
+
+// retryBackoff is how long to wait between attempts of a request when the API doesn't say how long to wait.
+var retryBackoff = poll.Exponential(500*time.Millisecond, 10*time.Second)
+
+// maxRetryAfter is the longest the API can ask to wait before retrying, responses asking to wait longer are returned.
+const maxRetryAfter = time.Minute
+
+// retryTransport retries requests failing with a network error or a transient response, e.g. 502 or 429.
+// Only idempotent requests are retried on transient errors. Since the Meroxa API isn't known to honor idempotency
+// keys, other requests, e.g. creating a build or a deployment, are only retried when rate limited or when they
+// couldn't be sent. POST requests are still sent with an idempotency key, the same for all of their attempts.
+type retryTransport struct {
+	base http.RoundTripper
+	// retries is the number of times a request is retried, 0 disables retries.
+	retries int
+	backoff poll.Backoff
+	// timeout bounds each attempt, including reading the body of its response.
+	timeout time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if req.Method == http.MethodPost && req.Header.Get(IdempotencyKeyHeader) == "" {
+		// RoundTrippers must not modify the request they're given.
+		req = req.Clone(req.Context())
+		req.Header.Set(IdempotencyKeyHeader, uuid.NewString())
+	}
+	// Requests can only be sent again if their body can be read again.
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	retryable := replayable && idempotent(req)
+
+	interval := t.backoff.Initial
+	for attempt := 0; ; attempt++ {
+		r, err := t.attemptRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		var sent atomic.Bool
+		r = r.WithContext(httptrace.WithClientTrace(r.Context(), &httptrace.ClientTrace{
+			WroteHeaderField: func(string, []string) { sent.Store(true) },
+		}))
+
+		resp, err := t.roundTrip(base, r)
+		last := attempt >= t.retries
+		wait := jitter(interval)
+		switch {
+		case err != nil:
+			// Requests failing before any of them was sent, e.g. when connecting, weren't carried out.
+			if last || !replayable || !retryable && sent.Load() || req.Context().Err() != nil || errors.Is(err, errNotRecorded) {
+				return nil, err
+			}
+		// Rate limited requests weren't carried out, they can be sent again even if they aren't idempotent.
+		case replayable && resp.StatusCode == http.StatusTooManyRequests || retryable && transientStatus(resp.StatusCode):
+			if last {
+				return resp, nil
+			}
+			if d, ok := retryAfter(resp); ok {
+				if d > maxRetryAfter {
+					return resp, nil
+				}
+				wait = d
+			}
+			// The body is drained so that the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		interval = t.backoff.Next(interval)
+	}
+}
+
+// attemptRequest returns the request to send for an attempt, with a new body for every retry.
+func (t *retryTransport) attemptRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+// roundTrip sends a single attempt, cancelling it once its timeout is reached or its response body is closed.
+func (t *retryTransport) roundTrip(base http.RoundTripper, req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// idempotent reports whether sending req more than once has the same effect as sending it once.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func transientStatus(status int) bool {
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns how long the Retry-After header of resp asks to wait, in seconds or until a date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// jitter returns a random duration between half of d and d, so that clients don't retry all at once.
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) //nolint:gosec // no need for a secure random number
+}
@@ -0,0 +1,370 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package global
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meroxa/cli/cmd/meroxa/poll"
+)
+
+// faultServer fails the first requests it receives with the given responses, then succeeds.
+type faultServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	faults   []func(w http.ResponseWriter)
+	requests []*http.Request
+	bodies   []string
+}
+
+func newFaultServer(t *testing.T, faults ...func(w http.ResponseWriter)) *faultServer {
+	t.Helper()
+	s := &faultServer{faults: faults}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, string(body))
+		var fault func(w http.ResponseWriter)
+		if len(s.faults) > 0 {
+			fault, s.faults = s.faults[0], s.faults[1:]
+		}
+		s.mu.Unlock()
+
+		if fault != nil {
+			fault(w)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func status(code int, headers ...string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(code)
+	}
+}
+
+func testRetryClient(retries int) *http.Client {
+	return &http.Client{Transport: &retryTransport{retries: retries, backoff: poll.Constant(time.Millisecond)}}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		desc     string
+		method   string
+		path     string
+		retries  int
+		faults   []func(w http.ResponseWriter)
+		status   int
+		requests int
+	}{
+		{
+			desc:     "retries transient errors",
+			method:   http.MethodGet,
+			retries:  3,
+			faults:   []func(w http.ResponseWriter){status(http.StatusBadGateway), status(http.StatusServiceUnavailable)},
+			status:   http.StatusOK,
+			requests: 3,
+		},
+		{
+			desc:     "gives up after the retries",
+			method:   http.MethodDelete,
+			retries:  1,
+			faults:   []func(w http.ResponseWriter){status(http.StatusBadGateway), status(http.StatusGatewayTimeout)},
+			status:   http.StatusGatewayTimeout,
+			requests: 2,
+		},
+		{
+			desc:     "disabled",
+			method:   http.MethodGet,
+			faults:   []func(w http.ResponseWriter){status(http.StatusBadGateway)},
+			status:   http.StatusBadGateway,
+			requests: 1,
+		},
+		{
+			desc:     "doesn't retry other errors",
+			method:   http.MethodGet,
+			retries:  3,
+			faults:   []func(w http.ResponseWriter){status(http.StatusInternalServerError)},
+			status:   http.StatusInternalServerError,
+			requests: 1,
+		},
+		{
+			desc:     "doesn't retry creates",
+			method:   http.MethodPost,
+			path:     "/v1/builds",
+			retries:  3,
+			faults:   []func(w http.ResponseWriter){status(http.StatusBadGateway)},
+			status:   http.StatusBadGateway,
+			requests: 1,
+		},
+		{
+			desc:     "doesn't retry deployments",
+			method:   http.MethodPost,
+			path:     "/v1/applications/my-app/deployments",
+			retries:  3,
+			faults:   []func(w http.ResponseWriter){status(http.StatusServiceUnavailable)},
+			status:   http.StatusServiceUnavailable,
+			requests: 1,
+		},
+		{
+			desc:     "retries rate limited creates",
+			method:   http.MethodPost,
+			path:     "/v1/connectors",
+			retries:  3,
+			faults:   []func(w http.ResponseWriter){status(http.StatusTooManyRequests, "Retry-After", "0")},
+			status:   http.StatusOK,
+			requests: 2,
+		},
+		{
+			desc:     "doesn't retry updates",
+			method:   http.MethodPatch,
+			retries:  3,
+			faults:   []func(w http.ResponseWriter){status(http.StatusBadGateway)},
+			status:   http.StatusBadGateway,
+			requests: 1,
+		},
+		{
+			desc:     "retries rate limited updates",
+			method:   http.MethodPatch,
+			retries:  3,
+			faults:   []func(w http.ResponseWriter){status(http.StatusTooManyRequests, "Retry-After", "0")},
+			status:   http.StatusOK,
+			requests: 2,
+		},
+		{
+			desc:     "doesn't wait too long",
+			method:   http.MethodGet,
+			retries:  3,
+			faults:   []func(w http.ResponseWriter){status(http.StatusTooManyRequests, "Retry-After", "3600")},
+			status:   http.StatusTooManyRequests,
+			requests: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := newFaultServer(t, tc.faults...)
+
+			req, err := http.NewRequest(tc.method, s.URL+tc.path, strings.NewReader(`{"name":"my-app"}`))
+			if err != nil {
+				t.Fatalf("not expected error, got %q", err.Error())
+			}
+			resp, err := testRetryClient(tc.retries).Do(req)
+			if err != nil {
+				t.Fatalf("not expected error, got %q", err.Error())
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, resp.StatusCode)
+			}
+			if len(s.requests) != tc.requests {
+				t.Fatalf("expected %d requests, got %d", tc.requests, len(s.requests))
+			}
+			for _, body := range s.bodies {
+				if body != `{"name":"my-app"}` {
+					t.Fatalf("expected the body to be sent with every request, got %q", body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportIdempotencyKey(t *testing.T) {
+	s := newFaultServer(t, status(http.StatusTooManyRequests, "Retry-After", "0"), status(http.StatusTooManyRequests, "Retry-After", "0"))
+
+	resp, err := testRetryClient(3).Post(s.URL+"/v1/builds", "application/json", bytes.NewBufferString("{}"))
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	resp.Body.Close()
+
+	key := s.requests[0].Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		t.Fatal("expected creates to have an idempotency key")
+	}
+	for _, r := range s.requests[1:] {
+		if got := r.Header.Get(IdempotencyKeyHeader); got != key {
+			t.Fatalf("expected retries to have the idempotency key %q, got %q", key, got)
+		}
+	}
+	if len(s.requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(s.requests))
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	s := newFaultServer(t, status(http.StatusTooManyRequests, "Retry-After", "1"))
+
+	start := time.Now()
+	resp, err := testRetryClient(1).Get(s.URL)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if d := time.Since(start); d < time.Second {
+		t.Fatalf("expected to wait as long as Retry-After, waited %s", d)
+	}
+}
+
+func TestRetryTransportNetworkErrors(t *testing.T) {
+	s := newFaultServer(t)
+	url := s.URL
+	s.Close()
+
+	_, err := testRetryClient(2).Get(url)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if _, err = testRetryClient(2).Do(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the request to be cancelled, got %v", err)
+	}
+}
+
+// failingTransport fails the first requests with err without sending them, then sends them with base.
+type failingTransport struct {
+	base     http.RoundTripper
+	failures int
+	err      error
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.failures > 0 {
+		t.failures--
+		return nil, t.err
+	}
+	return t.base.RoundTrip(req)
+}
+
+func TestRetryTransportNetworkErrorsNotIdempotent(t *testing.T) {
+	// Requests that couldn't be sent are retried.
+	s := newFaultServer(t)
+	client := &http.Client{Transport: &retryTransport{
+		base:    &failingTransport{base: http.DefaultTransport, failures: 1, err: errors.New("connection refused")},
+		retries: 2,
+		backoff: poll.Constant(time.Millisecond),
+	}}
+	resp, err := client.Post(s.URL+"/v1/connectors", "application/json", bytes.NewBufferString("{}"))
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	resp.Body.Close()
+	if len(s.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(s.requests))
+	}
+
+	// Requests failing once sent might have been carried out, they aren't sent again.
+	var mu sync.Mutex
+	var calls int
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer hs.Close()
+
+	_, err = testRetryClient(2).Post(hs.URL+"/v1/connectors", "application/json", bytes.NewBufferString("{}"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 1 {
+		t.Fatalf("expected 1 request, got %d", calls)
+	}
+}
+
+func TestRetryTransportTimeout(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		first := calls == 1
+		mu.Unlock()
+		if first {
+			// The first attempt hangs until it times out.
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer s.Close()
+
+	client := &http.Client{Transport: &retryTransport{
+		retries: 1,
+		backoff: poll.Constant(time.Millisecond),
+		timeout: 50 * time.Millisecond,
+	}}
+	resp, err := client.Get(s.URL)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil || string(b) != `{"ok":true}` {
+		t.Fatalf("expected the second attempt to succeed, got %q (%v)", b, err)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	oldRetries, oldFlag := flagRetries, retriesFlag
+	defer func() { flagRetries, retriesFlag = oldRetries, oldFlag }()
+	retriesFlag = nil
+	flagRetries = DefaultRetries
+
+	t.Setenv(APIRetriesEnv, "")
+	if got := Retries(); got != DefaultRetries {
+		t.Fatalf("expected %d retries, got %d", DefaultRetries, got)
+	}
+
+	t.Setenv(APIRetriesEnv, "5")
+	if got := Retries(); got != 5 {
+		t.Fatalf("expected 5 retries, got %d", got)
+	}
+}
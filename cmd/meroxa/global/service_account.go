@@ -0,0 +1,52 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package global
+
+import (
+	"net/http"
+)
+
+// ServiceAccountActor is the actor reported for commands run with an API key instead of a user's credentials.
+const ServiceAccountActor = "service-account"
+
+// GetAPIKey returns the API key of a service account, used instead of a user's credentials when set.
+func GetAPIKey() string {
+	return getEnvVal([]string{APIKeyEnv}, "")
+}
+
+// UsingServiceAccount reports whether the CLI authenticates with an API key, e.g. in CI, rather than as a user.
+func UsingServiceAccount() bool {
+	return GetAPIKey() != ""
+}
+
+// apiKeyTransport authenticates requests with a static API key.
+type apiKeyTransport struct {
+	apiKey string
+	base   http.RoundTripper
+}
+
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the request they're given.
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+t.apiKey)
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(r)
+}
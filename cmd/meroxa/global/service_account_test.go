@@ -0,0 +1,105 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package global
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewClientWithAPIKey(t *testing.T) {
+	setupContextsTest(t)
+	loadConfig(t)
+
+	var auth, account string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		account = r.Header.Get("Meroxa-Account-UUID")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"uuid":"1234","email":"ci@meroxa.io"}`)
+	}))
+	defer server.Close()
+
+	t.Setenv(APIKeyEnv, "my-api-key")
+	t.Setenv(DefaultAccountEnv, "account-uuid")
+	Config.Set(APIURLEnv, server.URL)
+	// The account of the user logged in isn't used with an API key.
+	Config.Set(UserAccountUUID, "user-account-uuid")
+
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if _, err = client.GetUser(context.Background()); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if auth != "Bearer my-api-key" {
+		t.Fatalf("expected requests to be authenticated with the API key, got %q", auth)
+	}
+	if account != "account-uuid" {
+		t.Fatalf("expected account %q, got %q", "account-uuid", account)
+	}
+	if got := Config.GetString(UserAccountUUID); got != "user-account-uuid" {
+		t.Fatalf("expected the account of the user to be kept, got %q", got)
+	}
+
+	actor, actorUUID, err := GetCLIUserInfo()
+	if err != nil || actor != ServiceAccountActor || actorUUID != "" {
+		t.Fatalf("expected service account actor, got %q, %q (%v)", actor, actorUUID, err)
+	}
+}
+
+func TestNewClientWithAPIKeyDefaultAccount(t *testing.T) {
+	setupContextsTest(t)
+	loadConfig(t)
+
+	var account string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/accounts" {
+			fmt.Fprint(w, `[{"uuid":"api-key-account-uuid","name":"ci"}]`)
+			return
+		}
+		account = r.Header.Get("Meroxa-Account-UUID")
+		fmt.Fprint(w, `{"uuid":"1234","email":"ci@meroxa.io"}`)
+	}))
+	defer server.Close()
+
+	t.Setenv(APIKeyEnv, "my-api-key")
+	t.Setenv(DefaultAccountEnv, "")
+	Config.Set(APIURLEnv, server.URL)
+
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if _, err = client.GetUser(context.Background()); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if account != "api-key-account-uuid" {
+		t.Fatalf("expected account %q, got %q", "api-key-account-uuid", account)
+	}
+	if AccountUUID() != "api-key-account-uuid" {
+		t.Fatalf("expected account %q, got %q", "api-key-account-uuid", AccountUUID())
+	}
+	if Config.IsSet(UserAccountUUID) {
+		t.Fatalf("expected the account of the API key not to be saved, got %q", Config.GetString(UserAccountUUID))
+	}
+}
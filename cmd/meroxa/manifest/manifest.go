@@ -0,0 +1,374 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	KindEnvironment = "environment"
+	KindResource    = "resource"
+	KindPipeline    = "pipeline"
+	KindConnector   = "connector"
+	KindFunction    = "function"
+	KindApplication = "application"
+)
+
+// Manifest describes the desired state of the entities in a Meroxa account.
+// Entries reuse the same input types the `create` commands send to the API.
+type Manifest struct {
+	Environments []meroxa.CreateEnvironmentInput `json:"environments,omitempty"`
+	Resources    []meroxa.CreateResourceInput    `json:"resources,omitempty"`
+	Pipelines    []meroxa.CreatePipelineInput    `json:"pipelines,omitempty"`
+	Connectors   []meroxa.CreateConnectorInput   `json:"connectors,omitempty"`
+	Functions    []meroxa.CreateFunctionInput    `json:"functions,omitempty"`
+	Applications []meroxa.CreateApplicationInput `json:"applications,omitempty"`
+}
+
+// Read parses the manifest at path. Use "-" to read it from stdin. When path is a directory, every YAML and JSON file
+// in it is read and merged into a single manifest, which is the layout written by `meroxa export --dir`.
+func Read(path string) (*Manifest, error) {
+	if path != "-" {
+		if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+			return readDir(path)
+		}
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest %q: %w", path, err)
+	}
+
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse manifest %q: %w", path, err)
+	}
+	return m, nil
+}
+
+func readDir(dir string) (*Manifest, error) {
+	m := &Manifest{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("could not read manifest %q: %w", path, err)
+		}
+		part, err := decode(data)
+		if err != nil {
+			return fmt.Errorf("could not parse manifest %q: %w", path, err)
+		}
+		m.Merge(part)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, m.Validate()
+}
+
+// Parse decodes a YAML or JSON manifest. Unknown fields are rejected so typos don't go unnoticed.
+func Parse(data []byte) (*Manifest, error) {
+	m, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return m, m.Validate()
+}
+
+func decode(data []byte) (*Manifest, error) {
+	// YAML is a superset of JSON, so decode generically and re-encode as JSON
+	// to make use of the json tags on the meroxa-go input types.
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	if raw == nil {
+		return m, nil
+	}
+
+	missing := make(map[string]bool)
+	raw = expandEnv(raw, missing)
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("environment variables referenced in manifest are not set: %s", strings.Join(names, ", "))
+	}
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var (
+	envPlaceholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+	urlScheme      = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://`)
+)
+
+// Placeholder returns the reference to an environment variable that is substituted when a manifest is read.
+func Placeholder(name string) string {
+	return "${" + name + "}"
+}
+
+// expandEnv substitutes ${NAME} placeholders in the string values of a decoded manifest with the value of the
+// environment variable NAME, so values can't change the structure of the document. Values going into a URL are
+// escaped. Referencing a variable that isn't set is an error rather than silently sending an empty secret to the API,
+// the names of those variables are added to missing.
+func expandEnv(v interface{}, missing map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = expandEnv(e, missing)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = expandEnv(e, missing)
+		}
+	case string:
+		inURL := urlScheme.MatchString(v)
+		return envPlaceholder.ReplaceAllStringFunc(v, func(match string) string {
+			name := envPlaceholder.FindStringSubmatch(match)[1]
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				missing[name] = true
+			}
+			if inURL {
+				// %20 stands for a space both in the user info and in the query of a URL, unlike +.
+				value = strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+			}
+			return value
+		})
+	}
+	return v
+}
+
+// Merge appends the entries of other to m.
+func (m *Manifest) Merge(other *Manifest) {
+	m.Environments = append(m.Environments, other.Environments...)
+	m.Resources = append(m.Resources, other.Resources...)
+	m.Pipelines = append(m.Pipelines, other.Pipelines...)
+	m.Connectors = append(m.Connectors, other.Connectors...)
+	m.Functions = append(m.Functions, other.Functions...)
+	m.Applications = append(m.Applications, other.Applications...)
+}
+
+// Marshal encodes the manifest as YAML, or as JSON when asJSON is set. Empty fields are left out to keep the output
+// readable, since most of the meroxa-go input types don't omit them.
+func (m *Manifest) Marshal(asJSON bool) ([]byte, error) {
+	// Kinds are compacted one by one into a struct, rather than a map, so they keep their dependency order.
+	var out struct {
+		Environments interface{} `json:"environments,omitempty" yaml:"environments,omitempty"`
+		Resources    interface{} `json:"resources,omitempty" yaml:"resources,omitempty"`
+		Pipelines    interface{} `json:"pipelines,omitempty" yaml:"pipelines,omitempty"`
+		Connectors   interface{} `json:"connectors,omitempty" yaml:"connectors,omitempty"`
+		Functions    interface{} `json:"functions,omitempty" yaml:"functions,omitempty"`
+		Applications interface{} `json:"applications,omitempty" yaml:"applications,omitempty"`
+	}
+	for _, kind := range []struct {
+		dst *interface{}
+		src interface{}
+	}{
+		{&out.Environments, m.Environments},
+		{&out.Resources, m.Resources},
+		{&out.Pipelines, m.Pipelines},
+		{&out.Connectors, m.Connectors},
+		{&out.Functions, m.Functions},
+		{&out.Applications, m.Applications},
+	} {
+		b, err := json.Marshal(kind.src)
+		if err != nil {
+			return nil, err
+		}
+		var raw interface{}
+		if err = json.Unmarshal(b, &raw); err != nil {
+			return nil, err
+		}
+		*kind.dst = compact(raw)
+	}
+
+	if asJSON {
+		return json.MarshalIndent(out, "", "  ")
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+// compact drops zero values from a generically decoded JSON document.
+func compact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if e = compact(e); e == nil {
+				delete(v, k)
+			} else {
+				v[k] = e
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case []interface{}:
+		out := v[:0]
+		for _, e := range v {
+			if e = compact(e); e != nil {
+				out = append(out, e)
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case string:
+		if v == "" {
+			return nil
+		}
+	case bool:
+		if !v {
+			return nil
+		}
+	}
+	return v
+}
+
+// Validate makes sure every entry has a name and that names are unique per kind.
+func (m *Manifest) Validate() error {
+	var errs []string
+
+	check := func(kind string, names []string) {
+		seen := make(map[string]bool)
+		for i, n := range names {
+			switch {
+			case n == "":
+				errs = append(errs, fmt.Sprintf("%s #%d is missing a name", kind, i+1))
+			case seen[n]:
+				errs = append(errs, fmt.Sprintf("%s %q is declared more than once", kind, n))
+			}
+			seen[n] = true
+		}
+	}
+
+	check(KindEnvironment, m.EnvironmentNames())
+	check(KindResource, m.ResourceNames())
+	check(KindPipeline, m.PipelineNames())
+	check(KindConnector, m.ConnectorNames())
+	check(KindFunction, m.FunctionNames())
+	check(KindApplication, m.ApplicationNames())
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid manifest:\n\t%s", strings.Join(errs, "\n\t"))
+	}
+	return nil
+}
+
+func (m *Manifest) EnvironmentNames() []string {
+	names := make([]string, 0, len(m.Environments))
+	for _, e := range m.Environments {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func (m *Manifest) ResourceNames() []string {
+	names := make([]string, 0, len(m.Resources))
+	for _, r := range m.Resources {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func (m *Manifest) PipelineNames() []string {
+	names := make([]string, 0, len(m.Pipelines))
+	for _, p := range m.Pipelines {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func (m *Manifest) ConnectorNames() []string {
+	names := make([]string, 0, len(m.Connectors))
+	for _, c := range m.Connectors {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func (m *Manifest) FunctionNames() []string {
+	names := make([]string, 0, len(m.Functions))
+	for _, f := range m.Functions {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func (m *Manifest) ApplicationNames() []string {
+	names := make([]string, 0, len(m.Applications))
+	for _, a := range m.Applications {
+		names = append(names, a.Name)
+	}
+	return names
+}
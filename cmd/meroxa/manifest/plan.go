@@ -0,0 +1,82 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alexeyco/simpletable"
+)
+
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionReplace Action = "replace"
+	ActionDelete  Action = "delete"
+)
+
+// Change is a single step needed to converge an account towards a manifest.
+type Change struct {
+	Action Action   `json:"action"`
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	Fields []string `json:"fields,omitempty"`
+
+	// Apply performs the change against the API.
+	Apply func(ctx context.Context) error `json:"-"`
+}
+
+// Plan is an ordered list of changes.
+type Plan []*Change
+
+func (p Plan) Empty() bool {
+	return len(p) == 0
+}
+
+// Table renders the plan the same way entity lists are rendered in utils/display.
+func (p Plan) Table(hideHeaders bool) string {
+	if p.Empty() {
+		return ""
+	}
+
+	table := simpletable.New()
+	if !hideHeaders {
+		table.Header = &simpletable.Header{
+			Cells: []*simpletable.Cell{
+				{Align: simpletable.AlignCenter, Text: "ACTION"},
+				{Align: simpletable.AlignCenter, Text: "KIND"},
+				{Align: simpletable.AlignCenter, Text: "NAME"},
+				{Align: simpletable.AlignCenter, Text: "FIELDS"},
+			},
+		}
+	}
+
+	for _, c := range p {
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Text: string(c.Action)},
+			{Text: c.Kind},
+			{Text: c.Name},
+			{Text: strings.Join(c.Fields, ", ")},
+		})
+	}
+
+	table.SetStyle(simpletable.StyleCompact)
+	return table.String()
+}
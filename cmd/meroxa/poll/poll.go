@@ -0,0 +1,72 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poll
+
+import (
+	"context"
+	"time"
+)
+
+// Backoff is how long to wait between attempts, growing by Multiplier after each attempt up to Max.
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// Constant waits the same interval between attempts.
+func Constant(interval time.Duration) Backoff {
+	return Backoff{Initial: interval, Max: interval, Multiplier: 1}
+}
+
+// Exponential waits initial after the first attempt, then grows the interval by half up to max.
+func Exponential(initial, max time.Duration) Backoff {
+	return Backoff{Initial: initial, Max: max, Multiplier: 1.5}
+}
+
+// Next returns the interval to wait after waiting d.
+func (b Backoff) Next(d time.Duration) time.Duration {
+	if b.Multiplier > 1 {
+		d = time.Duration(float64(d) * b.Multiplier)
+	}
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	return d
+}
+
+// Func checks the state of something being waited for. Polling stops once it's done or it returns an error.
+type Func func(ctx context.Context) (done bool, err error)
+
+// Until calls f until it's done or returns an error, waiting between calls according to b.
+// It returns the error of ctx if ctx is done first, e.g. context.DeadlineExceeded when it times out.
+func Until(ctx context.Context, b Backoff, f Func) error {
+	interval := b.Initial
+	for {
+		done, err := f(ctx)
+		if err != nil || done {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = b.Next(interval)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poll
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoffNext(t *testing.T) {
+	b := Exponential(time.Second, 2*time.Second)
+
+	d := b.Initial
+	var got []time.Duration
+	for i := 0; i < 4; i++ {
+		got = append(got, d)
+		d = b.Next(d)
+	}
+
+	want := []time.Duration{time.Second, 1500 * time.Millisecond, 2 * time.Second, 2 * time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected intervals %v, got %v", want, got)
+		}
+	}
+
+	if d := Constant(time.Second).Next(time.Second); d != time.Second {
+		t.Fatalf("expected constant interval, got %s", d)
+	}
+}
+
+func TestUntil(t *testing.T) {
+	var calls int
+	err := Until(context.Background(), Constant(time.Millisecond), func(context.Context) (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestUntilError(t *testing.T) {
+	wantErr := errors.New("boom")
+	err := Until(context.Background(), Constant(time.Millisecond), func(context.Context) (bool, error) {
+		return false, wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected error %q, got %v", wantErr, err)
+	}
+}
+
+func TestUntilTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := Until(ctx, Constant(time.Millisecond), func(context.Context) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %q, got %v", context.DeadlineExceeded, err)
+	}
+}
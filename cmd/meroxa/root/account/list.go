@@ -74,7 +74,11 @@ func (l *List) Execute(ctx context.Context) error {
 	}
 
 	l.logger.JSON(ctx, accounts)
-	l.logger.Info(ctx, display.AccountsTable(accounts, l.config.GetString(global.UserAccountUUID), l.hideHeaders))
+	current := l.config.GetString(global.UserAccountUUID)
+	if global.UsingServiceAccount() {
+		current = global.AccountUUID()
+	}
+	l.logger.Info(ctx, display.AccountsTable(accounts, current, l.hideHeaders))
 
 	return nil
 }
@@ -66,6 +66,10 @@ func (s *Set) Config(cfg config.Config) {
 }
 
 func (s *Set) Execute(ctx context.Context) error {
+	if global.UsingServiceAccount() {
+		return fmt.Errorf("the account of an API key is set with %s", global.DefaultAccountEnv)
+	}
+
 	accounts, err := s.client.ListAccounts(ctx)
 	if err != nil {
 		return err
@@ -47,3 +47,22 @@ func TestSetAccountsExecution(t *testing.T) {
 		t.Fatalf("expected configuration:\n%s\ngot:\n%s", want, got)
 	}
 }
+
+func TestSetAccountWithAPIKey(t *testing.T) {
+	t.Setenv(global.APIKeyEnv, "my-api-key")
+	cfg := viper.New()
+	s := &Set{
+		client: mock.NewMockClient(gomock.NewController(t)),
+		config: cfg,
+		logger: log.NewTestLogger(),
+		args:   struct{ UUID string }{"531428f7-4e86-4094-8514-d397d49026f7"},
+	}
+
+	err := s.Execute(context.Background())
+	if err == nil || err.Error() != "the account of an API key is set with MEROXA_ACCOUNT" {
+		t.Fatalf("expected an error, got %v", err)
+	}
+	if cfg.IsSet(global.UserAccountUUID) {
+		t.Fatal("expected the account not to be saved")
+	}
+}
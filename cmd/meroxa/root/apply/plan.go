@@ -0,0 +1,604 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/meroxa/cli/cmd/meroxa/manifest"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+// plan compares the manifest against the account and returns the changes needed to converge. Creates and updates are
+// ordered by dependency (environments, resources, pipelines, connectors, functions, applications), deletions go last
+// in the reverse order.
+func (a *Apply) plan(ctx context.Context, m *manifest.Manifest) (manifest.Plan, error) {
+	planners := []func(context.Context, *manifest.Manifest) (manifest.Plan, manifest.Plan, error){
+		a.planEnvironments,
+		a.planResources,
+		a.planPipelines,
+		a.planConnectors,
+		a.planFunctions,
+		a.planApplications,
+	}
+
+	var plan, deletes manifest.Plan
+	for _, p := range planners {
+		changes, removals, err := p(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		plan, deletes = append(plan, changes...), append(removals, deletes...)
+	}
+
+	return append(plan, deletes...), nil
+}
+
+func (a *Apply) planEnvironments(ctx context.Context, m *manifest.Manifest) (changes, deletes manifest.Plan, err error) {
+	// Kinds missing from the manifest are left untouched, even with --prune.
+	if m.Environments == nil {
+		return nil, nil, nil
+	}
+
+	existing, err := a.client.ListEnvironments(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string]*meroxa.Environment, len(existing))
+	for _, e := range existing {
+		byName[e.Name] = e
+	}
+
+	for i := range m.Environments {
+		want := m.Environments[i]
+		got, ok := byName[want.Name]
+		if !ok {
+			changes = append(changes, &manifest.Change{
+				Action: manifest.ActionCreate,
+				Kind:   manifest.KindEnvironment,
+				Name:   want.Name,
+				Apply: func(ctx context.Context) error {
+					_, err := a.client.CreateEnvironment(ctx, &want)
+					return err
+				},
+			})
+			continue
+		}
+
+		switch {
+		case want.Type != "" && want.Type != got.Type:
+			return nil, nil, fmt.Errorf("environment %q: type cannot be changed from %q to %q", want.Name, got.Type, want.Type)
+		case want.Provider != "" && want.Provider != got.Provider:
+			return nil, nil, fmt.Errorf("environment %q: provider cannot be changed from %q to %q", want.Name, got.Provider, want.Provider)
+		case want.Region != "" && want.Region != got.Region:
+			return nil, nil, fmt.Errorf("environment %q: region cannot be changed from %q to %q", want.Name, got.Region, want.Region)
+		}
+		if mapContains(got.Configuration, want.Configuration) {
+			continue
+		}
+
+		name := want.Name
+		in := &meroxa.UpdateEnvironmentInput{Configuration: want.Configuration}
+		changes = append(changes, &manifest.Change{
+			Action: manifest.ActionUpdate,
+			Kind:   manifest.KindEnvironment,
+			Name:   name,
+			Fields: []string{"config"},
+			Apply: func(ctx context.Context) error {
+				_, err := a.client.UpdateEnvironment(ctx, name, in)
+				return err
+			},
+		})
+	}
+
+	if a.flags.Prune {
+		declared := toSet(m.EnvironmentNames())
+		for _, e := range existing {
+			if declared[e.Name] {
+				continue
+			}
+			name := e.Name
+			deletes = append(deletes, &manifest.Change{
+				Action: manifest.ActionDelete,
+				Kind:   manifest.KindEnvironment,
+				Name:   name,
+				Apply: func(ctx context.Context) error {
+					_, err := a.client.DeleteEnvironment(ctx, name)
+					return err
+				},
+			})
+		}
+	}
+
+	return changes, deletes, nil
+}
+
+func (a *Apply) planResources(ctx context.Context, m *manifest.Manifest) (changes, deletes manifest.Plan, err error) {
+	if m.Resources == nil {
+		return nil, nil, nil
+	}
+
+	existing, err := a.client.ListResources(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string]*meroxa.Resource, len(existing))
+	for _, r := range existing {
+		byName[r.Name] = r
+	}
+
+	for i := range m.Resources {
+		want := m.Resources[i]
+		got, ok := byName[want.Name]
+		if !ok {
+			changes = append(changes, &manifest.Change{
+				Action: manifest.ActionCreate,
+				Kind:   manifest.KindResource,
+				Name:   want.Name,
+				Apply: func(ctx context.Context) error {
+					_, err := a.client.CreateResource(ctx, &want)
+					return err
+				},
+			})
+			continue
+		}
+
+		if want.Type != "" && want.Type != got.Type {
+			return nil, nil, fmt.Errorf("resource %q: type cannot be changed from %q to %q", want.Name, got.Type, want.Type)
+		}
+		if !sameEnvironment(want.Environment, got.Environment) {
+			return nil, nil, fmt.Errorf("resource %q: environment cannot be changed", want.Name)
+		}
+
+		var fields []string
+		in := &meroxa.UpdateResourceInput{Credentials: want.Credentials}
+		if want.URL != "" && want.URL != got.URL {
+			fields = append(fields, "url")
+			in.URL = want.URL
+		}
+		if credentialsChanged(want.Credentials, got.Credentials) {
+			fields = append(fields, "credentials")
+		}
+		if !mapContains(got.Metadata, want.Metadata) {
+			fields = append(fields, "metadata")
+			in.Metadata = want.Metadata
+		}
+		if want.SSHTunnel != nil && (got.SSHTunnel == nil || got.SSHTunnel.Address != want.SSHTunnel.Address) {
+			fields = append(fields, "ssh_tunnel")
+			in.SSHTunnel = want.SSHTunnel
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		name := want.Name
+		changes = append(changes, &manifest.Change{
+			Action: manifest.ActionUpdate,
+			Kind:   manifest.KindResource,
+			Name:   name,
+			Fields: fields,
+			Apply: func(ctx context.Context) error {
+				_, err := a.client.UpdateResource(ctx, name, in)
+				return err
+			},
+		})
+	}
+
+	if a.flags.Prune {
+		declared := toSet(m.ResourceNames())
+		for _, r := range existing {
+			if declared[r.Name] {
+				continue
+			}
+			name := r.Name
+			deletes = append(deletes, &manifest.Change{
+				Action: manifest.ActionDelete,
+				Kind:   manifest.KindResource,
+				Name:   name,
+				Apply: func(ctx context.Context) error {
+					return a.client.DeleteResource(ctx, name)
+				},
+			})
+		}
+	}
+
+	return changes, deletes, nil
+}
+
+func (a *Apply) planPipelines(ctx context.Context, m *manifest.Manifest) (changes, deletes manifest.Plan, err error) {
+	if m.Pipelines == nil {
+		return nil, nil, nil
+	}
+
+	existing, err := a.client.ListPipelines(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string]*meroxa.Pipeline, len(existing))
+	for _, p := range existing {
+		byName[p.Name] = p
+	}
+
+	for i := range m.Pipelines {
+		want := m.Pipelines[i]
+		got, ok := byName[want.Name]
+		if !ok {
+			changes = append(changes, &manifest.Change{
+				Action: manifest.ActionCreate,
+				Kind:   manifest.KindPipeline,
+				Name:   want.Name,
+				Apply: func(ctx context.Context) error {
+					_, err := a.client.CreatePipeline(ctx, &want)
+					return err
+				},
+			})
+			continue
+		}
+
+		if !sameEnvironment(want.Environment, got.Environment) {
+			return nil, nil, fmt.Errorf("pipeline %q: environment cannot be changed", want.Name)
+		}
+		if mapContains(got.Metadata, want.Metadata) {
+			continue
+		}
+
+		in := &meroxa.UpdatePipelineInput{Name: want.Name, Metadata: want.Metadata}
+		changes = append(changes, &manifest.Change{
+			Action: manifest.ActionUpdate,
+			Kind:   manifest.KindPipeline,
+			Name:   want.Name,
+			Fields: []string{"metadata"},
+			Apply: func(ctx context.Context) error {
+				_, err := a.client.UpdatePipeline(ctx, in.Name, in)
+				return err
+			},
+		})
+	}
+
+	if a.flags.Prune {
+		declared := toSet(m.PipelineNames())
+		for _, p := range existing {
+			if declared[p.Name] {
+				continue
+			}
+			name := p.Name
+			deletes = append(deletes, &manifest.Change{
+				Action: manifest.ActionDelete,
+				Kind:   manifest.KindPipeline,
+				Name:   name,
+				Apply: func(ctx context.Context) error {
+					return a.client.DeletePipeline(ctx, name)
+				},
+			})
+		}
+	}
+
+	return changes, deletes, nil
+}
+
+func (a *Apply) planConnectors(ctx context.Context, m *manifest.Manifest) (changes, deletes manifest.Plan, err error) {
+	if m.Connectors == nil {
+		return nil, nil, nil
+	}
+
+	existing, err := a.client.ListConnectors(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string]*meroxa.Connector, len(existing))
+	for _, c := range existing {
+		byName[c.Name] = c
+	}
+
+	for i := range m.Connectors {
+		want := m.Connectors[i]
+		create := func(ctx context.Context) error {
+			_, err := a.client.CreateConnector(ctx, &want)
+			return err
+		}
+
+		got, ok := byName[want.Name]
+		if !ok {
+			changes = append(changes, &manifest.Change{
+				Action: manifest.ActionCreate,
+				Kind:   manifest.KindConnector,
+				Name:   want.Name,
+				Apply:  create,
+			})
+			continue
+		}
+
+		// Connectors can't be moved between resources or pipelines, nor change direction.
+		var replaced []string
+		if want.ResourceName != "" && want.ResourceName != got.ResourceName {
+			replaced = append(replaced, "resource_name")
+		}
+		if want.PipelineName != "" && want.PipelineName != got.PipelineName {
+			replaced = append(replaced, "pipeline_name")
+		}
+		if want.Type != "" && want.Type != got.Type {
+			replaced = append(replaced, "connector_type")
+		}
+		// The input is sent as part of the configuration when creating a connector.
+		if want.Input != "" && fmt.Sprint(got.Configuration["input"]) != want.Input {
+			replaced = append(replaced, "input")
+		}
+		if len(replaced) > 0 {
+			name := want.Name
+			changes = append(changes, &manifest.Change{
+				Action: manifest.ActionReplace,
+				Kind:   manifest.KindConnector,
+				Name:   name,
+				Fields: replaced,
+				Apply: func(ctx context.Context) error {
+					if err := a.client.DeleteConnector(ctx, name); err != nil {
+						return err
+					}
+					return create(ctx)
+				},
+			})
+			continue
+		}
+
+		if mapContains(got.Configuration, want.Configuration) {
+			continue
+		}
+
+		name := want.Name
+		in := &meroxa.UpdateConnectorInput{Configuration: want.Configuration}
+		changes = append(changes, &manifest.Change{
+			Action: manifest.ActionUpdate,
+			Kind:   manifest.KindConnector,
+			Name:   name,
+			Fields: []string{"config"},
+			Apply: func(ctx context.Context) error {
+				_, err := a.client.UpdateConnector(ctx, name, in)
+				return err
+			},
+		})
+	}
+
+	if a.flags.Prune {
+		declared := toSet(m.ConnectorNames())
+		for _, c := range existing {
+			if declared[c.Name] {
+				continue
+			}
+			name := c.Name
+			deletes = append(deletes, &manifest.Change{
+				Action: manifest.ActionDelete,
+				Kind:   manifest.KindConnector,
+				Name:   name,
+				Apply: func(ctx context.Context) error {
+					return a.client.DeleteConnector(ctx, name)
+				},
+			})
+		}
+	}
+
+	return changes, deletes, nil
+}
+
+func (a *Apply) planFunctions(ctx context.Context, m *manifest.Manifest) (changes, deletes manifest.Plan, err error) {
+	if m.Functions == nil {
+		return nil, nil, nil
+	}
+
+	existing, err := a.client.ListFunctions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string]*meroxa.Function, len(existing))
+	for _, f := range existing {
+		byName[f.Name] = f
+	}
+
+	for i := range m.Functions {
+		want := m.Functions[i]
+		create := func(ctx context.Context) error {
+			_, err := a.client.CreateFunction(ctx, &want)
+			return err
+		}
+
+		got, ok := byName[want.Name]
+		if !ok {
+			changes = append(changes, &manifest.Change{
+				Action: manifest.ActionCreate,
+				Kind:   manifest.KindFunction,
+				Name:   want.Name,
+				Apply:  create,
+			})
+			continue
+		}
+
+		// The API can't update functions, any drift means replacing them.
+		fields := functionDrift(&want, got)
+		if len(fields) == 0 {
+			continue
+		}
+
+		name := want.Name
+		changes = append(changes, &manifest.Change{
+			Action: manifest.ActionReplace,
+			Kind:   manifest.KindFunction,
+			Name:   name,
+			Fields: fields,
+			Apply: func(ctx context.Context) error {
+				if _, err := a.client.DeleteFunction(ctx, name); err != nil {
+					return err
+				}
+				return create(ctx)
+			},
+		})
+	}
+
+	if a.flags.Prune {
+		declared := toSet(m.FunctionNames())
+		for _, f := range existing {
+			if declared[f.Name] {
+				continue
+			}
+			name := f.Name
+			deletes = append(deletes, &manifest.Change{
+				Action: manifest.ActionDelete,
+				Kind:   manifest.KindFunction,
+				Name:   name,
+				Apply: func(ctx context.Context) error {
+					_, err := a.client.DeleteFunction(ctx, name)
+					return err
+				},
+			})
+		}
+	}
+
+	return changes, deletes, nil
+}
+
+func functionDrift(want *meroxa.CreateFunctionInput, got *meroxa.Function) []string {
+	var fields []string
+	if want.Image != "" && want.Image != got.Image {
+		fields = append(fields, "image")
+	}
+	if want.InputStream != "" && want.InputStream != got.InputStream {
+		fields = append(fields, "input_stream")
+	}
+	if want.OutputStream != "" && want.OutputStream != got.OutputStream {
+		fields = append(fields, "output_stream")
+	}
+	if want.Pipeline.Name != "" && want.Pipeline.Name != got.Pipeline.Name {
+		fields = append(fields, "pipeline")
+	}
+	if len(want.Command) > 0 && !reflect.DeepEqual(want.Command, got.Command) {
+		fields = append(fields, "command")
+	}
+	if len(want.Args) > 0 && !reflect.DeepEqual(want.Args, got.Args) {
+		fields = append(fields, "args")
+	}
+	if len(want.EnvVars) > 0 && !reflect.DeepEqual(want.EnvVars, got.EnvVars) {
+		fields = append(fields, "env_vars")
+	}
+	return fields
+}
+
+// planApplications only creates missing applications, existing ones are updated by deploying them.
+func (a *Apply) planApplications(ctx context.Context, m *manifest.Manifest) (changes, deletes manifest.Plan, err error) {
+	if m.Applications == nil {
+		return nil, nil, nil
+	}
+
+	existing, err := a.client.ListApplications(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string]*meroxa.Application, len(existing))
+	for _, app := range existing {
+		byName[app.Name] = app
+	}
+
+	for i := range m.Applications {
+		want := m.Applications[i]
+		if got, ok := byName[want.Name]; ok {
+			if want.Language != "" && want.Language != got.Language {
+				return nil, nil, fmt.Errorf("application %q: language cannot be changed from %q to %q", want.Name, got.Language, want.Language)
+			}
+			continue
+		}
+		changes = append(changes, &manifest.Change{
+			Action: manifest.ActionCreate,
+			Kind:   manifest.KindApplication,
+			Name:   want.Name,
+			Apply: func(ctx context.Context) error {
+				_, err := a.client.CreateApplication(ctx, &want)
+				return err
+			},
+		})
+	}
+
+	if a.flags.Prune {
+		declared := toSet(m.ApplicationNames())
+		for _, app := range existing {
+			if declared[app.Name] {
+				continue
+			}
+			name := app.Name
+			deletes = append(deletes, &manifest.Change{
+				Action: manifest.ActionDelete,
+				Kind:   manifest.KindApplication,
+				Name:   name,
+				Apply: func(ctx context.Context) error {
+					return a.client.DeleteApplication(ctx, name)
+				},
+			})
+		}
+	}
+
+	return changes, deletes, nil
+}
+
+// mapContains reports whether every key in want is present in got with the same value. Keys only present in got are
+// ignored since the platform adds its own metadata and configuration defaults.
+func mapContains(got, want map[string]interface{}) bool {
+	for k, v := range want {
+		if !reflect.DeepEqual(got[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameEnvironment compares the environment declared in a manifest with the one an entity lives in.
+// No environment in the manifest means the common environment.
+// credentialsChanged reports whether want sets credentials that differ from got. Secrets, such as passwords, are only
+// compared when the API returns them, since there is nothing to compare them against otherwise.
+func credentialsChanged(want, got *meroxa.Credentials) bool {
+	if want == nil {
+		return false
+	}
+	if got == nil {
+		return true
+	}
+	differs := func(w, g string, secret bool) bool {
+		return w != "" && w != g && (!secret || g != "")
+	}
+	return want.UseSSL != got.UseSSL ||
+		differs(want.Username, got.Username, false) ||
+		differs(want.CACert, got.CACert, false) ||
+		differs(want.ClientCert, got.ClientCert, false) ||
+		differs(want.Password, got.Password, true) ||
+		differs(want.ClientCertKey, got.ClientCertKey, true) ||
+		differs(want.Token, got.Token, true)
+}
+
+func sameEnvironment(want, got *meroxa.EntityIdentifier) bool {
+	if want == nil {
+		return got == nil || got.Name == string(meroxa.EnvironmentTypeCommon)
+	}
+	if got == nil {
+		return want.Name == string(meroxa.EnvironmentTypeCommon)
+	}
+	return (want.UUID != "" && want.UUID == got.UUID) || (want.Name != "" && want.Name == got.Name)
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}
@@ -55,6 +55,7 @@ func (*Apps) Docs() builder.Docs {
 func (*Apps) SubCommands() []*cobra.Command {
 	return []*cobra.Command{
 		builder.BuildCobraCommand(&Deploy{}),
+		builder.BuildCobraCommand(&Deployments{}),
 		builder.BuildCobraCommand(&Describe{}),
 		builder.BuildCobraCommand(&Init{}),
 		builder.BuildCobraCommand(&List{}),
@@ -62,10 +63,17 @@ func (*Apps) SubCommands() []*cobra.Command {
 		builder.BuildCobraCommand(&Open{}),
 		builder.BuildCobraCommand(&Remove{}),
 		builder.BuildCobraCommand(&Run{}),
+		builder.BuildCobraCommand(&Secrets{}),
+		builder.BuildCobraCommand(&Test{}),
 		builder.BuildCobraCommand(&Upgrade{}),
+		builder.BuildCobraCommand(&Validate{}),
 	}
 }
 
+// TurbineCLI returns the turbine.CLI of an app written in lang. It can be replaced to work with apps without the
+// toolchain of their language, e.g. in end-to-end tests.
+var TurbineCLI = getTurbineCLIFromLanguage
+
 // getTurbineCLIFromLanguage will return the appropriate turbine.CLI based on language.
 func getTurbineCLIFromLanguage(logger log.Logger, lang ir.Lang, path string) (turbine.CLI, error) {
 	switch lang {
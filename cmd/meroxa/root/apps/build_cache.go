@@ -0,0 +1,138 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// buildCacheSize is the number of images remembered, the least recently used are forgotten first.
+const buildCacheSize = 100
+
+// buildCacheKey identifies the source an image was built from, images are only reused for the same
+// platform, account and environment.
+type buildCacheKey struct {
+	APIURL      string `json:"api_url"`
+	Account     string `json:"account"`
+	Environment string `json:"environment"`
+	Hash        string `json:"hash"`
+}
+
+type buildCacheEntry struct {
+	buildCacheKey
+	Image  string    `json:"image"`
+	UsedAt time.Time `json:"used_at"`
+	Build  string    `json:"build,omitempty"`
+}
+
+// buildCache remembers the images built by the build service from the content hash of the build context of apps,
+// so that apps whose source didn't change aren't uploaded and built again.
+type buildCache struct {
+	path string
+	now  func() time.Time
+}
+
+func newBuildCache(path string) *buildCache {
+	return &buildCache{path: path, now: time.Now}
+}
+
+// Get returns the image built for key, if any.
+func (c *buildCache) Get(key buildCacheKey) (buildCacheEntry, bool, error) {
+	entries, err := c.read()
+	if err != nil {
+		return buildCacheEntry{}, false, err
+	}
+	for _, e := range entries {
+		if e.buildCacheKey == key {
+			return e, true, nil
+		}
+	}
+	return buildCacheEntry{}, false, nil
+}
+
+// Put remembers the image built for key, or that it was used again.
+func (c *buildCache) Put(key buildCacheKey, image, build string) error {
+	entries, err := c.read()
+	if err != nil {
+		return err
+	}
+
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.buildCacheKey != key {
+			kept = append(kept, e)
+		}
+	}
+	kept = append(kept, buildCacheEntry{buildCacheKey: key, Image: image, Build: build, UsedAt: c.now().UTC()})
+
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].UsedAt.After(kept[j].UsedAt) })
+	if len(kept) > buildCacheSize {
+		kept = kept[:buildCacheSize]
+	}
+	return c.write(kept)
+}
+
+// Delete forgets the image built for key.
+func (c *buildCache) Delete(key buildCacheKey) error {
+	entries, err := c.read()
+	if err != nil {
+		return err
+	}
+
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.buildCacheKey != key {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	return c.write(kept)
+}
+
+func (c *buildCache) read() ([]buildCacheEntry, error) {
+	b, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []buildCacheEntry
+	if err = json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("could not read build cache %s: %w", c.path, err)
+	}
+	return entries, nil
+}
+
+func (c *buildCache) write(entries []buildCacheEntry) error {
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, b, 0o600)
+}
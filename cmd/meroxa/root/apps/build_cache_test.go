@@ -0,0 +1,67 @@
+package apps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCache(t *testing.T) {
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := newBuildCache(filepath.Join(t.TempDir(), "meroxa", "build-cache.json"))
+	c.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	key := buildCacheKey{APIURL: "https://api.meroxa.io", Account: "account", Environment: "common", Hash: "abc"}
+	_, ok, err := c.Get(key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, c.Put(key, "image-1", "build-1"))
+	e, ok, err := c.Get(key)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "image-1", e.Image)
+	assert.Equal(t, "build-1", e.Build)
+
+	// Images are remembered per environment and account.
+	_, ok, err = c.Get(buildCacheKey{APIURL: key.APIURL, Account: key.Account, Environment: "prod", Hash: key.Hash})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = c.Get(buildCacheKey{APIURL: key.APIURL, Account: "other", Environment: key.Environment, Hash: key.Hash})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The least recently used images are forgotten first.
+	for i := 0; i < buildCacheSize; i++ {
+		if i == buildCacheSize/2 {
+			require.NoError(t, c.Put(key, "image-1", "build-1"))
+		}
+		require.NoError(t, c.Put(buildCacheKey{Environment: "common", Hash: fmt.Sprint(i)}, "image", "build"))
+	}
+	entries, err := c.read()
+	require.NoError(t, err)
+	assert.Len(t, entries, buildCacheSize)
+	_, ok, err = c.Get(key)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	_, ok, err = c.Get(buildCacheKey{Environment: "common", Hash: "0"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, c.Delete(key))
+	_, ok, err = c.Get(key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, os.WriteFile(c.path, []byte("{"), 0o600))
+	_, _, err = c.Get(key)
+	require.ErrorContains(t, err, "could not read build cache")
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// buildContextIgnoreFile lists the files of an app which aren't part of its build context, with the syntax of
+// .dockerignore files.
+const buildContextIgnoreFile = ".dockerignore"
+
+type ignorePattern struct {
+	pattern string
+	negate  bool
+}
+
+// buildContextIgnore tells which files of a build context are ignored. Patterns are matched against the path
+// of files relative to the build context, the last matching pattern wins and patterns starting with ! bring back
+// files excluded by previous ones.
+type buildContextIgnore struct {
+	patterns []ignorePattern
+}
+
+// readBuildContextIgnore reads the ignore rules of the build context in dir, if any.
+func readBuildContextIgnore(dir string) (*buildContextIgnore, error) {
+	f, err := os.Open(filepath.Join(dir, buildContextIgnoreFile))
+	if os.IsNotExist(err) {
+		return &buildContextIgnore{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	i := &buildContextIgnore{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p := ignorePattern{}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = strings.TrimSpace(line[1:])
+		}
+		p.pattern = path.Clean(strings.TrimPrefix(filepath.ToSlash(line), "/"))
+		if _, err = path.Match(p.pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in %s: %w", line, buildContextIgnoreFile, err)
+		}
+		i.patterns = append(i.patterns, p)
+	}
+	return i, scanner.Err()
+}
+
+// ignored tells if the file with the slash separated path rel is ignored.
+func (i *buildContextIgnore) ignored(rel string) bool {
+	ignored := false
+	for _, p := range i.patterns {
+		if p.matches(rel) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// matches tells if the pattern matches rel or one of its parent directories. A leading **/ matches any number
+// of directories.
+func (p ignorePattern) matches(rel string) bool {
+	pattern, anyDir := strings.CutPrefix(p.pattern, "**/")
+	for {
+		candidates := []string{rel}
+		if anyDir {
+			for j := range rel {
+				if rel[j] == '/' {
+					candidates = append(candidates, rel[j+1:])
+				}
+			}
+		}
+		for _, c := range candidates {
+			if ok, _ := path.Match(pattern, c); ok {
+				return true
+			}
+		}
+
+		parent := path.Dir(rel)
+		if parent == "." || parent == rel {
+			return false
+		}
+		rel = parent
+	}
+}
+
+// walkBuildContext walks the files of the build context in root which are sent to the build service, in lexical
+// order. Directories of dependencies, fixtures and git, as well as the files ignored by .dockerignore are skipped.
+// The Dockerfile is always part of the build context.
+func walkBuildContext(root string, fn func(file string, fi os.FileInfo) error) error {
+	root = filepath.Clean(root)
+	ignore, err := readBuildContextIgnore(root)
+	if err != nil {
+		return err
+	}
+
+	return filepath.Walk(root, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if shouldSkipDir(fi) {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." && rel != "Dockerfile" && ignore.ignored(rel) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(file, fi)
+	})
+}
+
+// hashBuildContext returns the SHA-256 of the paths, modes and contents of the files in the build context in root,
+// which is the same for the same app source.
+func hashBuildContext(root string) (string, error) {
+	root = filepath.Clean(root)
+	h := sha256.New()
+	err := walkBuildContext(root, func(file string, fi os.FileInfo) error {
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%o\x00", filepath.ToSlash(rel), fi.Mode())
+
+		switch {
+		case fi.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(file)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "%s\x00", target)
+		case fi.Mode().IsRegular():
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			fmt.Fprintf(h, "%d\x00", fi.Size())
+			if _, err = io.Copy(h, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,128 @@
+package apps
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildContextIgnore(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte(`# Not needed to build the app
+*.md
+!CHANGELOG.md
+/tmp
+**/*.log
+docs/*.png
+`), 0o600))
+
+	i, err := readBuildContextIgnore(dir)
+	require.NoError(t, err)
+
+	tests := map[string]bool{
+		"README.md":           true,
+		"CHANGELOG.md":        false,
+		"docs/README.md":      false,
+		"tmp":                 true,
+		"tmp/cache/data.json": true,
+		"src/tmp":             false,
+		"app.log":             true,
+		"logs/2023/app.log":   true,
+		"docs/diagram.png":    true,
+		"docs/img/other.png":  false,
+		"app.go":              false,
+	}
+	for rel, want := range tests {
+		assert.Equal(t, want, i.ignored(rel), rel)
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("[\n"), 0o600))
+	_, err = readBuildContextIgnore(dir)
+	require.ErrorContains(t, err, `invalid pattern "[" in .dockerignore`)
+}
+
+func TestHashBuildContext(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string, perm os.FileMode) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), perm))
+		require.NoError(t, os.Chmod(filepath.Join(dir, name), perm))
+	}
+	hash := func() string {
+		t.Helper()
+		h, err := hashBuildContext(dir)
+		require.NoError(t, err)
+		return h
+	}
+
+	write("app.go", "package main", 0o644)
+	write(".dockerignore", "*.md\n", 0o644)
+	h := hash()
+	assert.Equal(t, h, hash(), "hashes must be stable")
+
+	write("README.md", "# my-app", 0o644)
+	write("fixtures/pg.json", "[]", 0o644)
+	write("node_modules/dep/index.js", "", 0o644)
+	write(".git/HEAD", "ref: refs/heads/main", 0o644)
+	assert.Equal(t, h, hash(), "ignored files must not change the hash")
+
+	write("app.go", "package main\n", 0o644)
+	changed := hash()
+	assert.NotEqual(t, h, changed, "changed files must change the hash")
+
+	write("app.go", "package main\n", 0o755)
+	assert.NotEqual(t, changed, hash(), "changed modes must change the hash")
+
+	// Hashes don't depend on where the app is.
+	other := filepath.Join(t.TempDir(), "other")
+	require.NoError(t, os.Rename(dir, other))
+	moved, err := hashBuildContext(other)
+	require.NoError(t, err)
+	dir = other
+	assert.Equal(t, hash(), moved)
+}
+
+func TestCreateTarAndZipFileIgnoresFiles(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "my-app")
+	for name, content := range map[string]string{
+		"app.go":           "package main",
+		"Dockerfile":       "FROM scratch",
+		".dockerignore":    "*.md\nDockerfile\n",
+		"README.md":        "# my-app",
+		"fixtures/pg.json": "[]",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(src, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, name), []byte(content), 0o600))
+	}
+
+	pwd, err := os.Getwd()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, createTarAndZipFile(src, &buf))
+	require.NoError(t, os.Chdir(pwd))
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	r := tar.NewReader(gz)
+	var names []string
+	for {
+		h, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, h.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"my-app", "my-app/.dockerignore", "my-app/Dockerfile", "my-app/app.go"}, names)
+}
@@ -34,6 +34,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/cmd/meroxa/poll"
 	"github.com/meroxa/cli/cmd/meroxa/turbine"
 	"github.com/meroxa/cli/config"
 	"github.com/meroxa/cli/log"
@@ -97,6 +99,10 @@ type Deploy struct {
 		Spec                     string `long:"spec" usage:"Deployment specification version to use to build and deploy the app" hidden:"true"`
 		SkipCollectionValidation bool   `long:"skip-collection-validation" usage:"Skips unique destination collection and looping validations"` //nolint:lll
 		Verbose                  bool   `long:"verbose" usage:"Prints more logging messages" hidden:"true"`
+		DryRun                   bool   `long:"dry-run" usage:"Validates the app and prints the deployment plan without deploying it"`
+		SpecFile                 string `long:"spec-file" usage:"Saves the deployment spec to a file when used with --dry-run (e.g. spec.json)"`
+		Profile                  string `long:"profile" usage:"Merges the settings of app.PROFILE.json over app.json (e.g. --profile prod)"`
+		NoBuildCache             bool   `long:"no-build-cache" usage:"Builds the process image even if one was built from the same source before"`
 	}
 
 	client        apiClient
@@ -104,6 +110,8 @@ type Deploy struct {
 	logger        log.Logger
 	turbineCLI    turbine.CLI
 	appConfig     *turbine.AppConfig
+	secretStore   secretStore
+	buildCache    *buildCache
 	configAppName string
 	appName       string
 	gitBranch     string
@@ -113,15 +121,20 @@ type Deploy struct {
 	specVersion   string
 	env           *environment
 	gitSha        string
+	outputFile    string
+	// deployed is set by dry runs when the git sha of the app is deployed already, so that the app isn't checked
+	// against itself.
+	deployed bool
 }
 
 var (
-	_ builder.CommandWithClient  = (*Deploy)(nil)
-	_ builder.CommandWithConfig  = (*Deploy)(nil)
-	_ builder.CommandWithDocs    = (*Deploy)(nil)
-	_ builder.CommandWithExecute = (*Deploy)(nil)
-	_ builder.CommandWithFlags   = (*Deploy)(nil)
-	_ builder.CommandWithLogger  = (*Deploy)(nil)
+	_ builder.CommandWithClient     = (*Deploy)(nil)
+	_ builder.CommandWithConfig     = (*Deploy)(nil)
+	_ builder.CommandWithDocs       = (*Deploy)(nil)
+	_ builder.CommandWithExecute    = (*Deploy)(nil)
+	_ builder.CommandWithFlags      = (*Deploy)(nil)
+	_ builder.CommandWithLogger     = (*Deploy)(nil)
+	_ builder.CommandWithOutputFile = (*Deploy)(nil)
 )
 
 func (*Deploy) Usage() string {
@@ -133,10 +146,27 @@ func (*Deploy) Docs() builder.Docs {
 		Short: "Deploy a Turbine Data Application",
 		Long: `This command will deploy the application specified in '--path'
 (or current working directory if not specified) to our Meroxa Platform.
-If deployment was successful, you should expect an application you'll be able to fully manage
+If deployment was successful, you should expect an application you'll be able to fully manage.
+
+Use '--dry-run' to validate the application and print the deployment spec along with a plan of what
+would be deployed, without creating a source, a build or a deployment. '--output FILE' (or '--spec-file FILE')
+also saves the deployment spec to FILE, while output formats such as '--output json' still apply.
+
+Use '--profile' to deploy with the settings of an overlay such as app.prod.json merged over app.json.
+Overlays can set the environment, the Platform resources used for the resources of the app ("resource_names")
+and its secrets, whose values can refer to environment variables with ${VAR}.
+
+Secrets stored with 'meroxa apps secrets set' are added to the deployment as well.
+
+The process image of the app is only built when its source changed since the last build for the same
+environment, as found from a hash of the files sent to the build service. Files matching the patterns of
+a .dockerignore file in the app aren't sent. Use '--no-build-cache' to build it anyway.
 `,
 		Example: `meroxa apps deploy # assumes you run it from the app directory
 meroxa apps deploy --path ./my-app
+meroxa apps deploy --dry-run --output spec.json
+meroxa apps deploy --dry-run --spec-file spec.json
+meroxa apps deploy --profile prod
 `,
 	}
 }
@@ -157,6 +187,10 @@ func (d *Deploy) Logger(logger log.Logger) {
 	d.logger = logger
 }
 
+func (d *Deploy) OutputFile(path string) {
+	d.outputFile = path
+}
+
 // getAppSource will return the proper destination where the application source will be uploaded and fetched.
 func (d *Deploy) getAppSource(ctx context.Context) (*meroxa.Source, error) {
 	in := meroxa.CreateSourceInputV2{}
@@ -170,8 +204,24 @@ func (d *Deploy) getAppSource(ctx context.Context) (*meroxa.Source, error) {
 }
 
 func (d *Deploy) getPlatformImage(ctx context.Context) (string, error) {
-	d.logger.StartSpinner("\t", "Fetching Meroxa Platform source...")
+	d.logger.StartSpinner("\t", fmt.Sprintf("Creating Dockerfile before uploading source in %s", d.path))
+	buildPath, err := d.turbineCLI.CreateDockerfile(ctx, d.appName)
+	if err != nil {
+		d.logger.StopSpinnerWithStatus("\t", log.Failed)
+		return "", err
+	}
+	defer d.turbineCLI.CleanupDockerfile(d.logger, d.path)
+	d.logger.StopSpinnerWithStatus("Dockerfile created", log.Successful)
+
+	key, image, err := d.cachedImage(ctx, buildPath)
+	if err != nil {
+		return "", err
+	}
+	if image != "" {
+		return image, nil
+	}
 
+	d.logger.StartSpinner("\t", "Fetching Meroxa Platform source...")
 	s, err := d.getAppSource(ctx)
 	if err != nil {
 		d.logger.Errorf(ctx, "\t 𐄂 Unable to fetch source")
@@ -180,7 +230,7 @@ func (d *Deploy) getPlatformImage(ctx context.Context) (string, error) {
 	}
 	d.logger.StopSpinnerWithStatus("Platform source fetched", log.Successful)
 
-	err = d.UploadSource(ctx, s.PutUrl)
+	err = d.UploadSource(ctx, buildPath, s.PutUrl)
 	if err != nil {
 		return "", err
 	}
@@ -200,44 +250,92 @@ func (d *Deploy) getPlatformImage(ctx context.Context) (string, error) {
 	}
 	d.logger.StartSpinner("\t", fmt.Sprintf("Building Meroxa Process image (%q)...", build.Uuid))
 
-	for {
+	err = poll.Until(ctx, poll.Constant(platformBuildPollDuration), func(ctx context.Context) (bool, error) {
 		b, err := d.client.GetBuild(ctx, build.Uuid)
 		if err != nil {
 			d.logger.StopSpinnerWithStatus("\t", log.Failed)
-			return "", err
+			return false, err
 		}
 
 		switch b.Status.State {
 		case "error":
 			msg := fmt.Sprintf("build with uuid %q errored\nRun `meroxa build logs %s` for more information", b.Uuid, b.Uuid)
 			d.logger.StopSpinnerWithStatus(msg, log.Failed)
-			return "", fmt.Errorf("build with uuid %q errored", b.Uuid)
+			return false, fmt.Errorf("build with uuid %q errored", b.Uuid)
 		case "complete":
 			d.logger.StopSpinnerWithStatus(fmt.Sprintf("Successfully built process image (%q)\n", build.Uuid), log.Successful)
-			return build.Image, nil
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if key != nil {
+		// The image was built, failing to remember it only means it'll be built again next time.
+		if err = d.buildCache.Put(*key, build.Image, build.Uuid); err != nil {
+			d.logger.Warnf(ctx, "\t Could not save the image in the build cache: %v", err)
 		}
-		time.Sleep(platformBuildPollDuration)
 	}
+	return build.Image, nil
 }
 
-func (d *Deploy) UploadSource(ctx context.Context, url string) error {
-	var (
-		err       error
-		buildPath string
-	)
+// cachedImage returns the key of the build context in buildPath in the build cache, and the image built from the
+// same source before, if any. The key is nil when the build cache isn't used.
+func (d *Deploy) cachedImage(ctx context.Context, buildPath string) (*buildCacheKey, string, error) {
+	if d.flags.NoBuildCache {
+		return nil, "", nil
+	}
+	if d.buildCache == nil {
+		d.buildCache = newBuildCache(global.BuildCacheFile())
+	}
 
-	d.logger.StartSpinner("\t", fmt.Sprintf("Creating Dockerfile before uploading source in %s", d.path))
-	buildPath, err = d.turbineCLI.CreateDockerfile(ctx, d.appName)
+	hash, err := hashBuildContext(buildPath)
 	if err != nil {
-		return err
+		return nil, "", fmt.Errorf("could not hash the source of the app: %w", err)
+	}
+	key := &buildCacheKey{
+		APIURL:      global.APIURL(),
+		Account:     global.AccountUUID(),
+		Environment: turbine.CommonEnvironment,
+		Hash:        hash,
+	}
+	if d.env != nil {
+		key.Environment = d.env.nameOrUUID()
 	}
-	defer d.turbineCLI.CleanupDockerfile(d.logger, d.path)
-	d.logger.StopSpinnerWithStatus("Dockerfile created", log.Successful)
 
+	e, ok, err := d.buildCache.Get(*key)
+	if err != nil {
+		d.logger.Warnf(ctx, "\t Ignoring the build cache: %v", err)
+		return key, "", nil
+	}
+	if !ok {
+		return key, "", nil
+	}
+
+	// The image of a build that failed or was removed since can't be deployed.
+	if b, err := d.client.GetBuild(ctx, e.Build); err != nil || b.Status.State != "complete" {
+		d.logger.Infof(ctx, "\t Can't reuse the process image of build %q, building it again", e.Build)
+		if err = d.buildCache.Delete(*key); err != nil {
+			d.logger.Warnf(ctx, "\t Could not remove the image from the build cache: %v", err)
+		}
+		return key, "", nil
+	}
+
+	d.logger.Infof(ctx, "\t%s Source unchanged since build %q, reusing process image", d.logger.SuccessfulCheck(), e.Build)
+	if err = d.buildCache.Put(*key, e.Image, e.Build); err != nil {
+		d.logger.Warnf(ctx, "\t Could not save the image in the build cache: %v", err)
+	}
+	return key, e.Image, nil
+}
+
+// UploadSource creates an archive of the build context in buildPath and uploads it to url.
+func (d *Deploy) UploadSource(ctx context.Context, buildPath, url string) error {
 	dFile := fmt.Sprintf("turbine-%s.tar.gz", d.appName)
 
 	var buf bytes.Buffer
-	if err = createTarAndZipFile(buildPath, &buf); err != nil {
+	if err := createTarAndZipFile(buildPath, &buf); err != nil {
 		return err
 	}
 
@@ -282,13 +380,7 @@ func createTarAndZipFile(src string, buf io.Writer) error {
 	zipWriter := gzip.NewWriter(buf)
 	tarWriter := tar.NewWriter(zipWriter)
 
-	err = filepath.Walk(appDir, func(file string, fi os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if shouldSkipDir(fi) {
-			return filepath.SkipDir
-		}
+	err = walkBuildContext(appDir, func(file string, fi os.FileInfo) error {
 		header, err := tar.FileInfoHeader(fi, file)
 		if err != nil {
 			return err
@@ -351,6 +443,9 @@ func (d *Deploy) createDeployment(ctx context.Context, imageName, gitSha, specVe
 	if err != nil {
 		return nil, err
 	}
+	if specStr, err = d.applyAppConfig(specStr); err != nil {
+		return nil, err
+	}
 	var spec map[string]interface{}
 	if specStr != "" {
 		if unmarshalErr := json.Unmarshal([]byte(specStr), &spec); unmarshalErr != nil {
@@ -369,6 +464,54 @@ func (d *Deploy) createDeployment(ctx context.Context, imageName, gitSha, specVe
 	return nil, nil
 }
 
+// applyAppConfig applies the resource names of app.json to the deployment spec of the app, and adds the secrets
+// stored for the app and the ones of app.json to it.
+func (d *Deploy) applyAppConfig(specStr string) (string, error) {
+	if specStr == "" || d.appConfig == nil {
+		return specStr, nil
+	}
+
+	secrets, err := appSecrets(appSecretStore(d.secretStore, d.appConfig.Name), d.appConfig)
+	if err != nil {
+		return "", err
+	}
+	if len(d.appConfig.ResourceNames) == 0 && len(secrets) == 0 {
+		return specStr, nil
+	}
+
+	var spec map[string]interface{}
+	if err = json.Unmarshal([]byte(specStr), &spec); err != nil {
+		return "", fmt.Errorf("failed to parse deployment spec into json")
+	}
+
+	connectors, _ := spec["connectors"].([]interface{})
+	for _, c := range connectors {
+		if connector, ok := c.(map[string]interface{}); ok {
+			if name, ok := connector["resource"].(string); ok {
+				connector["resource"] = d.appConfig.PlatformResource(name)
+			}
+		}
+	}
+
+	if len(secrets) > 0 {
+		specSecrets, _ := spec["secrets"].(map[string]interface{})
+		if specSecrets == nil {
+			specSecrets = map[string]interface{}{}
+		}
+		// Stored secrets and the ones of app.json override the ones the app registers.
+		for name, value := range secrets {
+			specSecrets[name] = value
+		}
+		spec["secrets"] = specSecrets
+	}
+
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 // getAppImage will check what type of build needs to perform and ultimately will return the image name to use
 // when deploying.
 func (d *Deploy) getAppImage(ctx context.Context) (string, error) {
@@ -432,9 +575,13 @@ func (d *Deploy) readFromAppJSON(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	if d.appConfig, err = turbine.ReadConfigFile(d.path); err != nil {
+	if d.appConfig, err = turbine.ReadProfileConfigFile(d.path, d.flags.Profile); err != nil {
 		return err
 	}
+	if d.flags.Profile != "" {
+		d.logger.Infof(ctx, "\t%s Using the settings of %s",
+			d.logger.SuccessfulCheck(), filepath.Base(turbine.OverlayPath(d.path, d.flags.Profile)))
+	}
 
 	if d.gitBranch, err = turbine.GetGitBranch(d.path); err != nil {
 		return err
@@ -512,9 +659,15 @@ func (d *Deploy) checkResourceAvailability(ctx context.Context) error {
 		return errors.New("no resources defined in your Turbine app")
 	}
 
+	if d.appConfig != nil {
+		for i := range resources {
+			resources[i].Name = d.appConfig.PlatformResource(resources[i].Name)
+		}
+	}
+
 	if err := d.validateResources(ctx, resources); err != nil {
 		d.logger.StopSpinnerWithStatus("Resource availability check failed", log.Failed)
-		return fmt.Errorf("%w;\n\n%s", err, resourceInvalidError)
+		return newResourceInvalidError(err)
 	}
 
 	if d.flags.SkipCollectionValidation {
@@ -558,74 +711,80 @@ func newResourceCollectionPair(r turbine.ApplicationResource) resourceCollection
 
 // TODO: Eventually remove this and validate fast in Platform API.
 func (d *Deploy) validateCollections(ctx context.Context, resources []turbine.ApplicationResource) error {
-	var (
-		sources      []turbine.ApplicationResource
-		destinations = map[resourceCollectionPair]bool{}
+	sources, destinations, problems := splitCollections(resources)
 
-		errMessage string
-	)
+	all, err := d.client.ListApplications(ctx)
+	if err != nil {
+		return err
+	}
+	apps := make([]*meroxa.Application, 0, len(all))
+	for _, app := range all {
+		if !d.deployed || app.Name != d.appName {
+			apps = append(apps, app)
+		}
+	}
+
+	problems = append(problems, validateNoCollectionLoops(sources, destinations)...)
+	problems = append(problems, validateDestinationCollectionUnique(apps, destinations)...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf(
+			"⚠️%s\n%s %s",
+			"\n\t"+strings.Join(problems, "\n\t"),
+			"Please modify your Turbine data application code. Then run `meroxa app deploy` again.",
+			"To skip collection validation, run `meroxa app deploy --skip-collection-validation`.",
+		)
+	}
+
+	return nil
+}
+
+// splitCollections returns the source and destination collections of an app's resources, and the problems with
+// how they're used which can be found without the Platform API.
+func splitCollections(resources []turbine.ApplicationResource) (
+	sources []turbine.ApplicationResource, destinations map[resourceCollectionPair]bool, problems []string,
+) {
+	destinations = map[resourceCollectionPair]bool{}
 	for _, r := range resources {
 		if r.Source && r.Destination {
-			errMessage = fmt.Sprintf(
-				"%s\n\tApplication resource cannot be used as both a source and destination.",
-				errMessage,
-			)
+			problems = append(problems, "Application resource cannot be used as both a source and destination.")
 		} else if r.Source {
 			sources = append(sources, r)
 		} else if r.Destination {
 			pair := newResourceCollectionPair(r)
 			if destinations[pair] {
-				errMessage = fmt.Sprintf(
-					"%s\n\tApplication resource %q with collection %q cannot be used as a destination more than once.",
-					errMessage,
+				problems = append(problems, fmt.Sprintf(
+					"Application resource %q with collection %q cannot be used as a destination more than once.",
 					r.Name,
 					r.Collection,
-				)
+				))
 			} else {
 				destinations[pair] = true
 			}
 		}
 	}
-
-	apps, err := d.client.ListApplications(ctx)
-	if err != nil {
-		return err
-	}
-
-	errMessage += d.validateNoCollectionLoops(sources, destinations) +
-		d.validateDestinationCollectionUnique(apps, destinations)
-
-	if errMessage != "" {
-		return fmt.Errorf(
-			"⚠️%s\n%s %s",
-			errMessage,
-			"Please modify your Turbine data application code. Then run `meroxa app deploy` again.",
-			"To skip collection validation, run `meroxa app deploy --skip-collection-validation`.",
-		)
-	}
-
-	return nil
+	return sources, destinations, problems
 }
 
 // validateNoCollectionLoops ensures source (resource, collection) doesn't equal any destination (resource, collection).
-func (d *Deploy) validateNoCollectionLoops(sources []turbine.ApplicationResource, destinations map[resourceCollectionPair]bool) string {
-	var errMessage string
+func validateNoCollectionLoops(sources []turbine.ApplicationResource, destinations map[resourceCollectionPair]bool) []string {
+	var problems []string
 	for _, source := range sources {
 		if ok := destinations[newResourceCollectionPair(source)]; ok {
-			errMessage = fmt.Sprintf(
-				"%s\n\tApplication resource %q with collection %q cannot be used as a destination. It is also the source.",
-				errMessage,
+			problems = append(problems, fmt.Sprintf(
+				"Application resource %q with collection %q cannot be used as a destination. It is also the source.",
 				source.Name,
-				source.Collection)
+				source.Collection,
+			))
 		}
 	}
 
-	return errMessage
+	return problems
 }
 
 // validateDestinationCollectionUnique ensures destination (resource, collection) is unique for account.
-func (d *Deploy) validateDestinationCollectionUnique(apps []*meroxa.Application, destinations map[resourceCollectionPair]bool) string {
-	var errMessage string
+func validateDestinationCollectionUnique(apps []*meroxa.Application, destinations map[resourceCollectionPair]bool) []string {
+	var problems []string
 	for _, app := range apps {
 		for _, r := range app.Resources {
 			if r.Collection.Destination == "true" &&
@@ -633,19 +792,18 @@ func (d *Deploy) validateDestinationCollectionUnique(apps []*meroxa.Application,
 					collectionName: r.Collection.Name,
 					resourceName:   r.Name,
 				}] {
-				errMessage = fmt.Sprintf(
-					"%s\n\tApplication resource %q with collection %q cannot be used as a destination. "+
+				problems = append(problems, fmt.Sprintf(
+					"Application resource %q with collection %q cannot be used as a destination. "+
 						"It is also being used as a destination by another application %q.",
-					errMessage,
 					r.Name,
 					r.Collection.Name,
 					app.Name,
-				)
+				))
 			}
 		}
 	}
 
-	return errMessage
+	return problems
 }
 
 func (d *Deploy) prepareAppName(ctx context.Context) string {
@@ -672,48 +830,44 @@ func (d *Deploy) waitForDeployment(ctx context.Context, depUUID string) error {
 	cctx, cancel := context.WithTimeout(ctx, minutesToWaitForDeployment*time.Minute)
 	defer cancel()
 	checkLogsMsg := "Check `meroxa apps logs` for further information"
-	t := time.NewTicker(intervalCheckForDeployment)
-	defer t.Stop()
-
 	prevLine := ""
 
-	for {
-		select {
-		case <-t.C:
-			var deployment *meroxa.Deployment
-			deployment, err := d.client.GetDeployment(ctx, d.appName, depUUID)
-			if err != nil {
-				return fmt.Errorf("couldn't fetch deployment status: %s", err.Error())
-			}
+	err := poll.Until(cctx, poll.Constant(intervalCheckForDeployment), func(ctx context.Context) (bool, error) {
+		deployment, err := d.client.GetDeployment(ctx, d.appName, depUUID)
+		if err != nil {
+			return false, fmt.Errorf("couldn't fetch deployment status: %s", err.Error())
+		}
 
-			logs := strings.Split(deployment.Status.Details, "\n")
+		logs := strings.Split(deployment.Status.Details, "\n")
 
-			if d.flags.Verbose {
-				l := len(logs)
-				if l > 0 && logs[l-1] != prevLine {
-					prevLine = logs[l-1]
-					d.logger.Info(ctx, "\t"+logs[l-1])
-				}
+		if d.flags.Verbose {
+			l := len(logs)
+			if l > 0 && logs[l-1] != prevLine {
+				prevLine = logs[l-1]
+				d.logger.Info(ctx, "\t"+logs[l-1])
 			}
+		}
 
-			switch {
-			case deployment.Status.State == meroxa.DeploymentStateDeployed:
-				return nil
-			case deployment.Status.State == meroxa.DeploymentStateDeployingError:
-				if !d.flags.Verbose {
-					d.logger.Error(ctx, "\n")
-					for _, l := range logs {
-						d.logger.Errorf(ctx, "\t%s", l)
-					}
+		switch {
+		case deployment.Status.State == meroxa.DeploymentStateDeployed:
+			return true, nil
+		case deployment.Status.State == meroxa.DeploymentStateDeployingError:
+			if !d.flags.Verbose {
+				d.logger.Error(ctx, "\n")
+				for _, l := range logs {
+					d.logger.Errorf(ctx, "\t%s", l)
 				}
-				return fmt.Errorf("\n %s", checkLogsMsg)
 			}
-		case <-cctx.Done():
-			return fmt.Errorf(
-				"your Turbine Application Deployment did not finish within %d minutes. %s",
-				minutesToWaitForDeployment, checkLogsMsg)
+			return false, fmt.Errorf("\n %s", checkLogsMsg)
 		}
+		return false, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf(
+			"your Turbine Application Deployment did not finish within %d minutes. %s",
+			minutesToWaitForDeployment, checkLogsMsg)
 	}
+	return err
 }
 
 // TODO: Once builds are done much faster we should move early checks like these to the Platform API.
@@ -730,6 +884,18 @@ func (d *Deploy) validateEnvExists(ctx context.Context) error {
 func (d *Deploy) assignDeploymentValues(ctx context.Context) error {
 	var err error
 
+	// app.json is read first since the overlay of a profile can set the environment to deploy to.
+	if err = d.readFromAppJSON(ctx); err != nil {
+		return err
+	}
+
+	// The environment of app.json alone doesn't take precedence over the default one of the context.
+	if d.flags.Environment == "" && d.flags.Profile != "" {
+		d.flags.Environment = d.appConfig.DeployEnvironment()
+	}
+	if d.flags.Environment == "" {
+		d.flags.Environment = global.GetDefaultEnvironment()
+	}
 	if d.flags.Environment != "" {
 		d.env = envFromFlag(d.flags.Environment)
 		err = d.validateEnvExists(ctx)
@@ -749,11 +915,7 @@ func (d *Deploy) assignDeploymentValues(ctx context.Context) error {
 		return err
 	}
 
-	if err = d.readFromAppJSON(ctx); err != nil {
-		return err
-	}
-
-	if d.turbineCLI, err = getTurbineCLIFromLanguage(d.logger, d.lang, d.path); err != nil {
+	if d.turbineCLI, err = TurbineCLI(d.logger, d.lang, d.path); err != nil {
 		return err
 	}
 
@@ -791,21 +953,30 @@ func (d *Deploy) appModified(ctx context.Context) (bool, error) {
 	return latest.GitSha != d.gitSha, nil
 }
 
+// checkExistingApplication returns a failed application that needs to be cleaned up before deploying,
+// or an error when the application already exists in any other state.
+func (d *Deploy) checkExistingApplication(ctx context.Context) (*meroxa.Application, error) {
+	existing, _ := d.client.GetApplication(ctx, d.appName)
+	if existing == nil || existing.Status.State == meroxa.ApplicationStateFailed {
+		return existing, nil
+	}
+	return nil, fmt.Errorf(
+		"application %q exists in the %q state.\n"+
+			"\tUse 'meroxa apps remove %s' if you want to redeploy to this application",
+		d.appName,
+		existing.Status.State,
+		d.appName,
+	)
+}
+
 func (d *Deploy) createApplication(ctx context.Context) (*meroxa.Application, error) {
-	if existing, _ := d.client.GetApplication(ctx, d.appName); existing != nil {
-		switch existing.Status.State { //nolint:exhaustive
-		case meroxa.ApplicationStateFailed:
-			// Clean up failed application
-			_, _ = d.client.DeleteApplicationEntities(ctx, d.appName)
-		default:
-			return nil, fmt.Errorf(
-				"application %q exists in the %q state.\n"+
-					"\tUse 'meroxa apps remove %s' if you want to redeploy to this application",
-				d.appName,
-				existing.Status.State,
-				d.appName,
-			)
-		}
+	failed, err := d.checkExistingApplication(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if failed != nil {
+		// Clean up failed application
+		_, _ = d.client.DeleteApplicationEntities(ctx, d.appName)
 	}
 
 	app, err := d.client.CreateApplicationV2(ctx, &meroxa.CreateApplicationInput{
@@ -859,6 +1030,13 @@ func (d *Deploy) deployApplication(ctx context.Context) error {
 func (d *Deploy) Execute(ctx context.Context) error {
 	var err error
 
+	if d.flags.SpecFile != "" && !d.flags.DryRun {
+		return errors.New("--spec-file can only be used with --dry-run")
+	}
+	if d.flags.SpecFile != "" && d.outputFile != "" {
+		return errors.New("--spec-file can't be used with --output")
+	}
+
 	if err = d.assignDeploymentValues(ctx); err != nil {
 		return err
 	}
@@ -869,6 +1047,10 @@ func (d *Deploy) Execute(ctx context.Context) error {
 	}
 	addTurbineHeaders(d.client, d.lang, turbineLibVersion)
 
+	if d.flags.DryRun {
+		return d.dryRun(ctx)
+	}
+
 	if err = d.getGitInfo(ctx); err != nil { //nolint:shadow
 		return err
 	}
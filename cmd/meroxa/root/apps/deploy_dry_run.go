@@ -0,0 +1,237 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/google/uuid"
+	"github.com/meroxa/turbine-core/pkg/ir"
+)
+
+const (
+	// dryRunImageName stands in for the process image, which is only built when actually deploying.
+	dryRunImageName = "<built on deploy>"
+	// maskedSecret replaces the values of secrets wherever a spec is shown, only their names are.
+	maskedSecret = "***"
+)
+
+var specUUID = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+type deployPlan struct {
+	Application string                 `json:"application"`
+	Environment string                 `json:"environment"`
+	Language    ir.Lang                `json:"language"`
+	GitSha      string                 `json:"git_sha"`
+	SpecVersion string                 `json:"spec_version"`
+	BuildImage  bool                   `json:"build_image"`
+	Unchanged   bool                   `json:"unchanged"`
+	Spec        map[string]interface{} `json:"spec"`
+
+	spec ir.DeploymentSpec
+}
+
+// dryRun runs the same validations as a deployment and prints the resulting spec,
+// without creating a source, a build nor a deployment.
+func (d *Deploy) dryRun(ctx context.Context) error {
+	var err error
+
+	// Uncommitted changes are fine here since nothing gets deployed.
+	if d.gitSha, err = d.turbineCLI.GetGitSha(ctx, d.path); err != nil {
+		return err
+	}
+
+	changed, err := d.appModified(ctx)
+	if err != nil {
+		return err
+	}
+
+	gracefulStop, err := d.turbineCLI.StartGrpcServer(ctx, d.gitSha)
+	if err != nil {
+		return err
+	}
+	defer gracefulStop()
+
+	// The app is expected to exist when its git sha is the one deployed already.
+	d.deployed = !changed
+	if changed {
+		if _, err = d.checkExistingApplication(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err = d.checkResourceAvailability(ctx); err != nil {
+		return err
+	}
+
+	buildImage, err := d.turbineCLI.NeedsToBuild(ctx)
+	if err != nil {
+		return err
+	}
+	var imageName string
+	if buildImage {
+		imageName = dryRunImageName
+	}
+
+	specStr, err := d.turbineCLI.GetDeploymentSpec(ctx, imageName)
+	if err != nil {
+		return err
+	}
+	if specStr, err = d.applyAppConfig(specStr); err != nil {
+		return err
+	}
+	specStr = d.normalizeSpecUUIDs(specStr)
+
+	plan := &deployPlan{
+		Application: d.appName,
+		Environment: "common",
+		Language:    d.lang,
+		GitSha:      d.gitSha,
+		SpecVersion: d.specVersion,
+		BuildImage:  buildImage,
+		Unchanged:   !changed,
+	}
+	if d.env != nil {
+		plan.Environment = d.env.nameOrUUID()
+	}
+	if err = json.Unmarshal([]byte(specStr), &plan.Spec); err != nil {
+		return fmt.Errorf("failed to parse deployment spec into json")
+	}
+	if err = json.Unmarshal([]byte(specStr), &plan.spec); err != nil {
+		return fmt.Errorf("failed to parse deployment spec into json")
+	}
+	maskSpecSecrets(plan.Spec)
+	plan.spec.Secrets = nil
+
+	// Re-encoding the map sorts the keys, so the same app always produces the same file.
+	out, err := json.MarshalIndent(plan.Spec, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	d.logger.Info(ctx, plan.String())
+	d.logger.Infof(ctx, "Deployment spec:\n%s\n", out)
+
+	if specFile := d.specFile(); specFile != "" {
+		if err = os.WriteFile(specFile, append(out, '\n'), 0o644); err != nil { //nolint:gosec
+			return fmt.Errorf("could not write deployment spec to %q: %w", specFile, err)
+		}
+		d.logger.Infof(ctx, "\t%s Deployment spec saved to %q", d.logger.SuccessfulCheck(), specFile)
+	}
+
+	d.logger.Infof(ctx, "\nThis was a dry run, application %q was not deployed.", d.appName)
+	if !changed {
+		d.logger.Infof(ctx, "Note: git sha %s is already deployed, deploying application %q would do nothing.", d.gitSha, d.appName)
+	}
+	d.logger.JSON(ctx, plan)
+	return nil
+}
+
+// normalizeSpecUUIDs replaces the random UUIDs turbine assigns to connectors, functions and streams with ones derived
+// from the app name and their position in the spec, so that a dry run of the same code always produces the same spec.
+// The actual deployment gets new UUIDs anyway.
+func (d *Deploy) normalizeSpecUUIDs(spec string) string {
+	seen := make(map[string]string)
+	return specUUID.ReplaceAllStringFunc(spec, func(id string) string {
+		if replacement, ok := seen[id]; ok {
+			return replacement
+		}
+		replacement := uuid.NewSHA1(uuid.Nil, []byte(fmt.Sprintf("%s/%d", d.appName, len(seen)))).String()
+		seen[id] = replacement
+		return replacement
+	})
+}
+
+// specFile returns the file the deployment spec is saved to, given with --spec-file or --output.
+func (d *Deploy) specFile() string {
+	if d.flags.SpecFile != "" {
+		return d.flags.SpecFile
+	}
+	return d.outputFile
+}
+
+// maskSpecSecrets replaces the values of the secrets of spec, so that they don't end up in logs, output or spec files.
+func maskSpecSecrets(spec map[string]interface{}) {
+	secrets, ok := spec["secrets"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for name := range secrets {
+		secrets[name] = maskedSecret
+	}
+}
+
+func (p *deployPlan) String() string {
+	image := "not needed"
+	if p.BuildImage {
+		image = "will be built from the app source"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Deployment plan for application %q:\n", p.Application)
+	fmt.Fprintf(&b, "\tEnvironment:   %s\n", p.Environment)
+	fmt.Fprintf(&b, "\tLanguage:      %s\n", p.Language)
+	fmt.Fprintf(&b, "\tGit SHA:       %s\n", p.GitSha)
+	fmt.Fprintf(&b, "\tSpec version:  %s\n", p.SpecVersion)
+	fmt.Fprintf(&b, "\tProcess image: %s\n", image)
+
+	if len(p.spec.Connectors) > 0 {
+		table := simpletable.New()
+		table.Header = &simpletable.Header{
+			Cells: []*simpletable.Cell{
+				{Align: simpletable.AlignCenter, Text: "TYPE"},
+				{Align: simpletable.AlignCenter, Text: "RESOURCE"},
+				{Align: simpletable.AlignCenter, Text: "COLLECTION"},
+			},
+		}
+		for _, c := range p.spec.Connectors {
+			table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+				{Text: string(c.Type)},
+				{Text: c.Resource},
+				{Text: c.Collection},
+			})
+		}
+		table.SetStyle(simpletable.StyleCompact)
+		fmt.Fprintf(&b, "\nConnectors to create:\n%s\n", table.String())
+	}
+
+	if len(p.spec.Functions) > 0 {
+		table := simpletable.New()
+		table.Header = &simpletable.Header{
+			Cells: []*simpletable.Cell{
+				{Align: simpletable.AlignCenter, Text: "NAME"},
+				{Align: simpletable.AlignCenter, Text: "IMAGE"},
+			},
+		}
+		for _, f := range p.spec.Functions {
+			table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+				{Text: f.Name},
+				{Text: f.Image},
+			})
+		}
+		table.SetStyle(simpletable.StyleCompact)
+		fmt.Fprintf(&b, "\nFunctions to create:\n%s\n", table.String())
+	}
+
+	return b.String()
+}
@@ -2,10 +2,13 @@ package apps
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -39,6 +42,10 @@ func TestDeployAppFlags(t *testing.T) {
 		{name: "skip-collection-validation", required: false, hidden: false},
 		{name: "verbose", required: false, hidden: true},
 		{name: "env", required: false, hidden: false},
+		{name: "dry-run", required: false, hidden: false},
+		{name: "spec-file", required: false, hidden: false},
+		{name: "profile", required: false, hidden: false},
+		{name: "no-build-cache", required: false, hidden: false},
 	}
 
 	c := builder.BuildCobraCommand(&Deploy{})
@@ -366,6 +373,32 @@ func TestValidateCollections(t *testing.T) {
 	}
 }
 
+func TestValidateCollectionsOfDeployedApp(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	apps := []*meroxa.Application{{
+		Name: "my-app",
+		Resources: []meroxa.ApplicationResource{{
+			EntityIdentifier: meroxa.EntityIdentifier{Name: "pg"},
+			Collection:       meroxa.ResourceCollection{Name: "copy", Destination: "true"},
+		}},
+	}}
+	client.EXPECT().ListApplications(ctx).Return(apps, nil).Times(2)
+	resources := []turbine.ApplicationResource{{Name: "pg", Destination: true, Collection: "copy"}}
+
+	// Deploying an app with the name of an existing one is checked against it, like against any other app.
+	d := &Deploy{client: client, logger: log.NewTestLogger(), appName: "my-app"}
+	err := d.validateCollections(ctx, resources)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `It is also being used as a destination by another application "my-app".`)
+
+	// A dry run of the git sha already deployed isn't checked against the app itself.
+	d.deployed = true
+	assert.NoError(t, d.validateCollections(ctx, resources))
+}
+
 func TestValidateLanguage(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -655,19 +688,20 @@ func TestGetPlatformImage(t *testing.T) {
 			},
 			mockTurbineCLI: func(ctrl *gomock.Controller) turbine.CLI {
 				mockTurbineCLI := turbineMock.NewMockCLI(ctrl)
+				mockTurbineCLI.EXPECT().
+					CreateDockerfile(ctx, appName).
+					Return(buildPath, nil)
+				mockTurbineCLI.EXPECT().
+					CleanupDockerfile(logger, buildPath).
+					Return()
 				return mockTurbineCLI
 			},
 			err: err,
 		},
 		{
-			name: "Fail to upload source",
+			name: "Fail to create Dockerfile",
 			meroxaClient: func(ctrl *gomock.Controller) meroxa.Client {
-				client := mock.NewMockClient(ctrl)
-				input := meroxa.CreateSourceInputV2{}
-				client.EXPECT().
-					CreateSourceV2(ctx, &input).
-					Return(&meroxa.Source{GetUrl: sourceGetURL, PutUrl: sourcePutURL}, nil)
-				return client
+				return mock.NewMockClient(ctrl)
 			},
 			mockTurbineCLI: func(ctrl *gomock.Controller) turbine.CLI {
 				mockTurbineCLI := turbineMock.NewMockCLI(ctrl)
@@ -751,6 +785,7 @@ func TestGetPlatformImage(t *testing.T) {
 				turbineCLI: tc.mockTurbineCLI(ctrl),
 				logger:     logger,
 				appName:    appName,
+				buildCache: newBuildCache(filepath.Join(t.TempDir(), "build-cache.json")),
 			}
 			if tc.env != "" {
 				d.env = &environment{Name: tc.env}
@@ -767,6 +802,89 @@ func TestGetPlatformImage(t *testing.T) {
 	}
 }
 
+func TestGetPlatformImageBuildCache(t *testing.T) {
+	ctx := context.Background()
+	logger := log.NewTestLogger()
+	appPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, "app.go"), []byte("package main"), 0o600))
+
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	expectBuild := func(env *meroxa.EntityIdentifier, buildUUID, image string) {
+		client.EXPECT().
+			CreateSourceV2(ctx, &meroxa.CreateSourceInputV2{Environment: env}).
+			Return(&meroxa.Source{GetUrl: "http://foo.bar", PutUrl: server.URL}, nil)
+		client.EXPECT().
+			CreateBuild(ctx, gomock.Any()).
+			Return(&meroxa.Build{Uuid: buildUUID, Image: image}, nil)
+		client.EXPECT().
+			GetBuild(ctx, buildUUID).
+			Return(&meroxa.Build{Uuid: buildUUID, Image: image, Status: meroxa.BuildStatus{State: "complete"}}, nil)
+	}
+
+	turbineCLI := turbineMock.NewMockCLI(ctrl)
+	turbineCLI.EXPECT().CreateDockerfile(ctx, "my-app").Return(appPath, nil).AnyTimes()
+	turbineCLI.EXPECT().CleanupDockerfile(logger, appPath).AnyTimes()
+
+	d := &Deploy{
+		client:     client,
+		turbineCLI: turbineCLI,
+		logger:     logger,
+		appName:    "my-app",
+		path:       appPath,
+		buildCache: newBuildCache(filepath.Join(t.TempDir(), "build-cache.json")),
+	}
+
+	expectBuild(nil, "build-1", "image-1")
+	image, err := d.getPlatformImage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "image-1", image)
+
+	// The same source isn't built again, even when a file ignored by the build context changed.
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, ".dockerignore"), []byte("*.md\n"), 0o600))
+	expectBuild(nil, "build-2", "image-2")
+	image, err = d.getPlatformImage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "image-2", image, ".dockerignore is part of the build context")
+
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, "README.md"), []byte("# my-app"), 0o600))
+	client.EXPECT().
+		GetBuild(ctx, "build-2").
+		Return(&meroxa.Build{Uuid: "build-2", Image: "image-2", Status: meroxa.BuildStatus{State: "complete"}}, nil)
+	image, err = d.getPlatformImage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "image-2", image)
+	assert.Contains(t, logger.LeveledOutput(), `Source unchanged since build "build-2", reusing process image`)
+
+	// Images of builds that aren't complete anymore are built again.
+	client.EXPECT().
+		GetBuild(ctx, "build-2").
+		Return(nil, errors.New("build not found"))
+	expectBuild(nil, "build-5", "image-5")
+	image, err = d.getPlatformImage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "image-5", image)
+	assert.Contains(t, logger.LeveledOutput(), `Can't reuse the process image of build "build-2", building it again`)
+
+	// Images are built for each environment.
+	d.env = &environment{Name: "my-env"}
+	expectBuild(&meroxa.EntityIdentifier{Name: "my-env"}, "build-3", "image-3")
+	image, err = d.getPlatformImage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "image-3", image)
+
+	d.env = nil
+	d.flags.NoBuildCache = true
+	expectBuild(nil, "build-4", "image-4")
+	image, err = d.getPlatformImage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "image-4", image)
+}
+
 func TestGetAppImage(t *testing.T) {
 	ctx := context.Background()
 	logger := log.NewTestLogger()
@@ -881,7 +999,7 @@ func TestWaitForDeployment(t *testing.T) {
 				client := mock.NewMockClient(ctrl)
 
 				client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{
 						Status: meroxa.DeploymentStatus{
 							State:   meroxa.DeploymentStateDeployed,
@@ -899,7 +1017,7 @@ func TestWaitForDeployment(t *testing.T) {
 				client := mock.NewMockClient(ctrl)
 
 				client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{
 						Status: meroxa.DeploymentStatus{
 							State:   meroxa.DeploymentStateDeployed,
@@ -918,7 +1036,7 @@ func TestWaitForDeployment(t *testing.T) {
 				client := mock.NewMockClient(ctrl)
 
 				first := client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{
 						Status: meroxa.DeploymentStatus{
 							State:   meroxa.DeploymentStateDeploying,
@@ -926,7 +1044,7 @@ func TestWaitForDeployment(t *testing.T) {
 						},
 					}, nil)
 				second := client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{
 						Status: meroxa.DeploymentStatus{
 							State:   meroxa.DeploymentStateDeploying,
@@ -934,7 +1052,7 @@ func TestWaitForDeployment(t *testing.T) {
 						},
 					}, nil)
 				third := client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{
 						Status: meroxa.DeploymentStatus{
 							State:   meroxa.DeploymentStateDeployed,
@@ -953,7 +1071,7 @@ func TestWaitForDeployment(t *testing.T) {
 				client := mock.NewMockClient(ctrl)
 
 				first := client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{
 						Status: meroxa.DeploymentStatus{
 							State:   meroxa.DeploymentStateDeploying,
@@ -961,7 +1079,7 @@ func TestWaitForDeployment(t *testing.T) {
 						},
 					}, nil)
 				second := client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{
 						Status: meroxa.DeploymentStatus{
 							State:   meroxa.DeploymentStateDeploying,
@@ -969,7 +1087,7 @@ func TestWaitForDeployment(t *testing.T) {
 						},
 					}, nil)
 				third := client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{
 						Status: meroxa.DeploymentStatus{
 							State:   meroxa.DeploymentStateDeployed,
@@ -995,7 +1113,7 @@ func TestWaitForDeployment(t *testing.T) {
 				client := mock.NewMockClient(ctrl)
 
 				client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{
 						Status: meroxa.DeploymentStatus{
 							State:   meroxa.DeploymentStateDeployingError,
@@ -1019,7 +1137,7 @@ func TestWaitForDeployment(t *testing.T) {
 				client := mock.NewMockClient(ctrl)
 
 				client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{
 						Status: meroxa.DeploymentStatus{
 							State:   meroxa.DeploymentStateDeployingError,
@@ -1040,7 +1158,7 @@ func TestWaitForDeployment(t *testing.T) {
 				client := mock.NewMockClient(ctrl)
 
 				client.EXPECT().
-					GetDeployment(ctx, appName, deploymentUuuid).
+					GetDeployment(gomock.Any(), appName, deploymentUuuid).
 					Return(&meroxa.Deployment{}, fmt.Errorf("not today"))
 				return client
 			},
@@ -1320,3 +1438,178 @@ func Test_validateEnvExists(t *testing.T) {
 		})
 	}
 }
+
+func TestDeployDryRun(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	mockTurbineCLI := turbineMock.NewMockCLI(ctrl)
+	logger := log.NewTestLogger()
+
+	appName := "my-app"
+	gitSha := "aabbccdd"
+	resource := utils.GenerateResourceWithNameAndStatus("pg", string(meroxa.ResourceStateReady))
+	specStr := `{
+		"connectors": [
+			{"uuid": "11111111-1111-4111-8111-111111111111", "type": "source", "resource": "pg", "collection": "orders"},
+			{"uuid": "22222222-2222-4222-8222-222222222222", "type": "destination", "resource": "pg", "collection": "copy"}
+		],
+		"streams": [{
+			"uuid": "33333333-3333-4333-8333-333333333333",
+			"name": "11111111-1111-4111-8111-111111111111_22222222-2222-4222-8222-222222222222",
+			"from_uuid": "11111111-1111-4111-8111-111111111111",
+			"to_uuid": "22222222-2222-4222-8222-222222222222"
+		}],
+		"secrets": {"LOG_LEVEL": "registered-value"},
+		"definition": {"git_sha": "aabbccdd", "metadata": {"turbine": {"language": "golang", "version": "v1"}, "spec_version": "0.2.0"}}
+	}`
+
+	mockTurbineCLI.EXPECT().GetGitSha(ctx, "").Return(gitSha, nil)
+	client.EXPECT().GetApplication(ctx, appName).Return(nil, errors.New("could not find application")).Times(2)
+	mockTurbineCLI.EXPECT().StartGrpcServer(ctx, gitSha).Return(func() {}, nil)
+	mockTurbineCLI.EXPECT().GetResources(ctx).Return([]turbine.ApplicationResource{
+		{Name: "pg", Source: true, Collection: "orders"},
+		{Name: "pg", Destination: true, Collection: "copy"},
+	}, nil)
+	client.EXPECT().GetResourceByNameOrID(ctx, "pg").Return(&resource, nil)
+	client.EXPECT().ListApplications(ctx).Return([]*meroxa.Application{}, nil)
+	mockTurbineCLI.EXPECT().NeedsToBuild(ctx).Return(false, nil)
+	mockTurbineCLI.EXPECT().GetDeploymentSpec(ctx, "").Return(specStr, nil)
+
+	output := filepath.Join(t.TempDir(), "spec.json")
+	d := &Deploy{
+		client:      client,
+		turbineCLI:  mockTurbineCLI,
+		logger:      logger,
+		appName:     appName,
+		lang:        ir.GoLang,
+		specVersion: ir.LatestSpecVersion,
+		appConfig:   &turbine.AppConfig{Name: appName},
+		secretStore: fakeSecretStore{"API_KEY": "stored-value"},
+	}
+	d.flags.DryRun = true
+	d.flags.SpecFile = output
+
+	require.NoError(t, d.dryRun(ctx))
+
+	// Secrets are listed, but their values never show up.
+	for _, out := range []string{logger.LeveledOutput(), logger.JSONOutput()} {
+		assert.NotContains(t, out, "stored-value")
+		assert.NotContains(t, out, "registered-value")
+		assert.Contains(t, out, `"API_KEY": "***"`)
+	}
+
+	saved, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.NotContains(t, string(saved), "11111111-1111-4111-8111-111111111111")
+	assert.NotContains(t, string(saved), "stored-value")
+	assert.Contains(t, logger.LeveledOutput(), string(saved))
+	assert.Contains(t, logger.LeveledOutput(), `This was a dry run, application "my-app" was not deployed.`)
+
+	// The same code must always produce the same spec, even though turbine assigns new UUIDs every time.
+	redeployed := strings.NewReplacer(
+		"11111111-1111-4111-8111-111111111111", "aaaaaaaa-aaaa-4aaa-8aaa-aaaaaaaaaaaa",
+		"22222222-2222-4222-8222-222222222222", "bbbbbbbb-bbbb-4bbb-8bbb-bbbbbbbbbbbb",
+		"33333333-3333-4333-8333-333333333333", "cccccccc-cccc-4ccc-8ccc-cccccccccccc",
+	).Replace(specStr)
+	require.NotEqual(t, specStr, redeployed)
+	assert.Equal(t, d.normalizeSpecUUIDs(specStr), d.normalizeSpecUUIDs(redeployed))
+
+	var plan map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(logger.JSONOutput()), &plan))
+	assert.Equal(t, "common", plan["environment"])
+	assert.Equal(t, false, plan["build_image"])
+}
+
+func TestDeployDryRunUnchanged(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	mockTurbineCLI := turbineMock.NewMockCLI(ctrl)
+	logger := log.NewTestLogger()
+
+	appName := "my-app"
+	gitSha := "aabbccdd"
+	resource := utils.GenerateResourceWithNameAndStatus("pg", string(meroxa.ResourceStateReady))
+	app := &meroxa.Application{
+		Name:   appName,
+		Status: meroxa.ApplicationStatus{State: meroxa.ApplicationStateRunning},
+		Resources: []meroxa.ApplicationResource{
+			{EntityIdentifier: meroxa.EntityIdentifier{Name: "pg"}, Collection: meroxa.ResourceCollection{Name: "copy", Destination: "true"}},
+		},
+	}
+	specStr := `{"connectors": [
+		{"uuid": "11111111-1111-4111-8111-111111111111", "type": "source", "resource": "pg", "collection": "orders"},
+		{"uuid": "22222222-2222-4222-8222-222222222222", "type": "destination", "resource": "pg", "collection": "copy"}
+	]}`
+
+	mockTurbineCLI.EXPECT().GetGitSha(ctx, "").Return(gitSha, nil)
+	client.EXPECT().GetApplication(ctx, appName).Return(app, nil)
+	client.EXPECT().GetLatestDeployment(ctx, appName).Return(&meroxa.Deployment{GitSha: gitSha}, nil)
+	mockTurbineCLI.EXPECT().StartGrpcServer(ctx, gitSha).Return(func() {}, nil)
+	mockTurbineCLI.EXPECT().GetResources(ctx).Return([]turbine.ApplicationResource{
+		{Name: "pg", Source: true, Collection: "orders"},
+		{Name: "pg", Destination: true, Collection: "copy"},
+	}, nil)
+	client.EXPECT().GetResourceByNameOrID(ctx, "pg").Return(&resource, nil)
+	client.EXPECT().ListApplications(ctx).Return([]*meroxa.Application{app}, nil)
+	mockTurbineCLI.EXPECT().NeedsToBuild(ctx).Return(false, nil)
+	mockTurbineCLI.EXPECT().GetDeploymentSpec(ctx, "").Return(specStr, nil)
+
+	d := &Deploy{
+		client:      client,
+		turbineCLI:  mockTurbineCLI,
+		logger:      logger,
+		appName:     appName,
+		lang:        ir.GoLang,
+		specVersion: ir.LatestSpecVersion,
+		appConfig:   &turbine.AppConfig{Name: appName},
+		secretStore: fakeSecretStore{},
+	}
+	d.flags.DryRun = true
+
+	// The plan and spec are shown even though deploying would do nothing.
+	require.NoError(t, d.dryRun(ctx))
+	out := logger.LeveledOutput()
+	assert.Contains(t, out, `Deployment plan for application "my-app"`)
+	assert.Contains(t, out, `"collection": "copy"`)
+	assert.Contains(t, out, `Note: git sha aabbccdd is already deployed, deploying application "my-app" would do nothing.`)
+
+	var plan map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(logger.JSONOutput()), &plan))
+	assert.Equal(t, true, plan["unchanged"])
+}
+
+func TestApplyAppConfig(t *testing.T) {
+	t.Setenv("PROD_API_KEY", "s3cr3t")
+	specStr := `{"connectors":[{"resource":"pg","type":"source"},{"resource":"s3","type":"destination"}],"secrets":{"LOG_LEVEL":"info"}}`
+
+	d := &Deploy{appConfig: &turbine.AppConfig{}, secretStore: fakeSecretStore{}}
+	got, err := d.applyAppConfig(specStr)
+	require.NoError(t, err)
+	assert.Equal(t, specStr, got, "specs must be left as they are without resource names nor secrets")
+
+	d.appConfig = &turbine.AppConfig{
+		ResourceNames: map[string]string{"pg": "pg-prod"},
+		Secrets:       map[string]string{"API_KEY": "${PROD_API_KEY}", "LOG_LEVEL": "warn"},
+	}
+	got, err = d.applyAppConfig(specStr)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"connectors": [{"resource": "pg-prod", "type": "source"}, {"resource": "s3", "type": "destination"}],
+		"secrets": {"API_KEY": "s3cr3t", "LOG_LEVEL": "warn"}
+	}`, got)
+
+	// Stored secrets override the ones of the app, but not the ones of app.json.
+	d.secretStore = fakeSecretStore{"API_KEY": "stored", "TOKEN": "t0k3n"}
+	got, err = d.applyAppConfig(specStr)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"connectors": [{"resource": "pg-prod", "type": "source"}, {"resource": "s3", "type": "destination"}],
+		"secrets": {"API_KEY": "s3cr3t", "LOG_LEVEL": "warn", "TOKEN": "t0k3n"}
+	}`, got)
+
+	d.appConfig.Secrets["OTHER"] = "${MISSING_SECRET}"
+	_, err = d.applyAppConfig(specStr)
+	require.EqualError(t, err, "secrets refer to environment variables which aren't set: MISSING_SECRET")
+}
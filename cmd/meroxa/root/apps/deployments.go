@@ -0,0 +1,91 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/turbine"
+	"github.com/spf13/cobra"
+)
+
+type Deployments struct{}
+
+var (
+	_ builder.CommandWithDocs        = (*Deployments)(nil)
+	_ builder.CommandWithAliases     = (*Deployments)(nil)
+	_ builder.CommandWithSubCommands = (*Deployments)(nil)
+)
+
+func (*Deployments) Aliases() []string {
+	return []string{"deployment"}
+}
+
+func (*Deployments) Usage() string {
+	return "deployments"
+}
+
+func (*Deployments) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Inspect and roll back deployments of a Turbine Data Application",
+		Long: `Each time a Turbine Data Application is deployed, a new deployment is created with the git sha and
+the specification that was used. These commands let you look back at them, compare them and roll back
+to one of them without checking out older code.
+
+The application is the one specified in '--app', or the one in '--path' (or current working directory
+if not specified).`,
+	}
+}
+
+func (*Deployments) SubCommands() []*cobra.Command {
+	return []*cobra.Command{
+		builder.BuildCobraCommand(&DescribeDeployment{}),
+		builder.BuildCobraCommand(&DiffDeployments{}),
+		builder.BuildCobraCommand(&ListDeployments{}),
+		builder.BuildCobraCommand(&RollbackDeployment{}),
+	}
+}
+
+// deploymentsAppName returns the application given with --app, or the one configured in the app.json found in path.
+func deploymentsAppName(app, path string) (string, error) {
+	if app != "" && path != "" {
+		return "", errors.New("supply either --app or --path flag")
+	}
+	if app != "" {
+		return app, nil
+	}
+
+	path, err := turbine.GetPath(path)
+	if err != nil {
+		return "", err
+	}
+	config, err := turbine.ReadConfigFile(path)
+	if err != nil {
+		return "", err
+	}
+	return config.Name, nil
+}
+
+func formatSpec(spec map[string]interface{}) (string, error) {
+	out, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"context"
+	"errors"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var (
+	_ builder.CommandWithDocs    = (*DescribeDeployment)(nil)
+	_ builder.CommandWithArgs    = (*DescribeDeployment)(nil)
+	_ builder.CommandWithFlags   = (*DescribeDeployment)(nil)
+	_ builder.CommandWithClient  = (*DescribeDeployment)(nil)
+	_ builder.CommandWithLogger  = (*DescribeDeployment)(nil)
+	_ builder.CommandWithExecute = (*DescribeDeployment)(nil)
+)
+
+type describeDeploymentClient interface {
+	GetDeployment(ctx context.Context, appName string, depUUID string) (*meroxa.Deployment, error)
+	GetLatestDeployment(ctx context.Context, nameOrUUID string) (*meroxa.Deployment, error)
+}
+
+type DescribeDeployment struct {
+	client describeDeploymentClient
+	logger log.Logger
+
+	args struct {
+		UUID string
+	}
+	flags struct {
+		App  string `long:"app" usage:"application name or UUID"`
+		Path string `long:"path" usage:"Path to the app directory (default is local directory)"`
+	}
+}
+
+func (d *DescribeDeployment) Usage() string {
+	return "describe [UUID] [--app NAME] [--path pwd]"
+}
+
+func (d *DescribeDeployment) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Describe a deployment of a Turbine Data Application, including its specification",
+		Long:  `Describe the deployment with the given UUID, or the latest deployment when no UUID is given.`,
+		Example: `meroxa apps deployments describe # latest deployment of the Application in the current directory
+meroxa apps deployments describe 3a1c2c4e-6f26-4a8f-9a3f-6b2f0c4a9d1e --app my-app`,
+	}
+}
+
+func (d *DescribeDeployment) Flags() []builder.Flag {
+	return builder.BuildFlags(&d.flags)
+}
+
+func (d *DescribeDeployment) Client(client meroxa.Client) {
+	d.client = client
+}
+
+func (d *DescribeDeployment) Logger(logger log.Logger) {
+	d.logger = logger
+}
+
+func (d *DescribeDeployment) ParseArgs(args []string) error {
+	if len(args) > 1 {
+		return errors.New("requires at most one deployment UUID")
+	}
+	if len(args) > 0 {
+		d.args.UUID = args[0]
+	}
+	return nil
+}
+
+func (d *DescribeDeployment) Execute(ctx context.Context) error {
+	appName, err := deploymentsAppName(d.flags.App, d.flags.Path)
+	if err != nil {
+		return err
+	}
+
+	dep, err := getDeploymentOrLatest(ctx, d.client, appName, d.args.UUID)
+	if err != nil {
+		return err
+	}
+
+	maskSpecSecrets(dep.Spec)
+	d.logger.Output(ctx, dep)
+	if len(dep.Spec) > 0 {
+		spec, err := formatSpec(dep.Spec)
+		if err != nil {
+			return err
+		}
+		d.logger.Infof(ctx, "\nSpec:\n%s", spec)
+	}
+	return nil
+}
+
+// getDeploymentOrLatest fetches the deployment with the given UUID, or the latest one when it's empty.
+func getDeploymentOrLatest(ctx context.Context, client describeDeploymentClient, appName, depUUID string) (*meroxa.Deployment, error) {
+	if depUUID == "" {
+		return client.GetLatestDeployment(ctx, appName)
+	}
+	return client.GetDeployment(ctx, appName, depUUID)
+}
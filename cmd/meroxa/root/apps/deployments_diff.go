@@ -0,0 +1,297 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var (
+	_ builder.CommandWithDocs    = (*DiffDeployments)(nil)
+	_ builder.CommandWithArgs    = (*DiffDeployments)(nil)
+	_ builder.CommandWithFlags   = (*DiffDeployments)(nil)
+	_ builder.CommandWithClient  = (*DiffDeployments)(nil)
+	_ builder.CommandWithLogger  = (*DiffDeployments)(nil)
+	_ builder.CommandWithExecute = (*DiffDeployments)(nil)
+)
+
+type DiffDeployments struct {
+	client describeDeploymentClient
+	logger log.Logger
+
+	args struct {
+		From string
+		To   string
+	}
+	flags struct {
+		App  string `long:"app" usage:"application name or UUID"`
+		Path string `long:"path" usage:"Path to the app directory (default is local directory)"`
+	}
+}
+
+// specChange is a single value that differs between two deployment specs. From or To is nil when the value was
+// added or removed.
+type specChange struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+func (d *DiffDeployments) Usage() string {
+	return "diff FROM_UUID [TO_UUID] [--app NAME] [--path pwd]"
+}
+
+func (d *DiffDeployments) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Compare the specifications of two deployments of a Turbine Data Application",
+		Long: `Compare the specification of deployment FROM_UUID with the one of deployment TO_UUID,
+or with the latest deployment when TO_UUID is not given.
+
+Connectors and functions are identified by their resource and collection, or by their name, instead of
+the UUIDs generated on each deployment or their position, so only actual changes are shown.`,
+		Example: `meroxa apps deployments diff 3a1c2c4e-6f26-4a8f-9a3f-6b2f0c4a9d1e
+meroxa apps deployments diff 3a1c2c4e-6f26-4a8f-9a3f-6b2f0c4a9d1e 9b7e0d52-1c3a-4f0e-8e4b-2d6c1a7f3b90 --app my-app`,
+	}
+}
+
+func (d *DiffDeployments) Flags() []builder.Flag {
+	return builder.BuildFlags(&d.flags)
+}
+
+func (d *DiffDeployments) Client(client meroxa.Client) {
+	d.client = client
+}
+
+func (d *DiffDeployments) Logger(logger log.Logger) {
+	d.logger = logger
+}
+
+func (d *DiffDeployments) ParseArgs(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("requires the UUID of the deployment to compare from")
+	case 1:
+		d.args.From = args[0]
+	case 2: //nolint:gomnd
+		d.args.From, d.args.To = args[0], args[1]
+	default:
+		return errors.New("requires at most two deployment UUIDs")
+	}
+	return nil
+}
+
+func (d *DiffDeployments) Execute(ctx context.Context) error {
+	appName, err := deploymentsAppName(d.flags.App, d.flags.Path)
+	if err != nil {
+		return err
+	}
+
+	from, err := d.client.GetDeployment(ctx, appName, d.args.From)
+	if err != nil {
+		return err
+	}
+	to, err := getDeploymentOrLatest(ctx, d.client, appName, d.args.To)
+	if err != nil {
+		return err
+	}
+
+	changes, err := diffSpecs(from.Spec, to.Spec)
+	if err != nil {
+		return err
+	}
+
+	d.logger.JSON(ctx, changes)
+	if len(changes) == 0 {
+		d.logger.Infof(ctx, "Deployments %q and %q have the same spec.", from.UUID, to.UUID)
+		return nil
+	}
+
+	d.logger.Infof(ctx, "--- %s (git sha %s)\n+++ %s (git sha %s)", from.UUID, from.GitSha, to.UUID, to.GitSha)
+	for _, c := range changes {
+		if c.From != nil {
+			d.logger.Infof(ctx, "- %s: %s", c.Path, formatSpecValue(c.From))
+		}
+		if c.To != nil {
+			d.logger.Infof(ctx, "+ %s: %s", c.Path, formatSpecValue(c.To))
+		}
+	}
+	return nil
+}
+
+func diffSpecs(from, to map[string]interface{}) ([]specChange, error) {
+	a, err := labelSpecUUIDs(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := labelSpecUUIDs(to)
+	if err != nil {
+		return nil, err
+	}
+
+	fromValues, toValues := map[string]interface{}{}, map[string]interface{}{}
+	flattenSpec("", a, fromValues)
+	flattenSpec("", b, toValues)
+
+	paths := make([]string, 0, len(fromValues)+len(toValues))
+	for p := range fromValues {
+		paths = append(paths, p)
+	}
+	for p := range toValues {
+		if _, ok := fromValues[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []specChange
+	for _, p := range paths {
+		f, t := fromValues[p], toValues[p]
+		if formatSpecValue(f) == formatSpecValue(t) {
+			continue
+		}
+		// Secrets are compared by value but only shown by name.
+		if strings.HasPrefix(p, "secrets.") {
+			if f != nil {
+				f = maskedSecret
+			}
+			if t != nil {
+				t = maskedSecret
+			}
+		}
+		changes = append(changes, specChange{Path: p, From: f, To: t})
+	}
+	return changes, nil
+}
+
+// labelSpecUUIDs replaces the UUIDs of connectors and functions, which are generated on every deployment,
+// with labels that stay the same across deployments. Stream UUIDs are dropped since streams are identified by the
+// entities they connect.
+func labelSpecUUIDs(spec map[string]interface{}) (map[string]interface{}, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	var pairs []string
+	entities := func(key string) []map[string]interface{} {
+		items, _ := spec[key].([]interface{})
+		out := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				out = append(out, m)
+			}
+		}
+		return out
+	}
+	for _, c := range entities("connectors") {
+		if id, ok := c["uuid"].(string); ok && id != "" {
+			pairs = append(pairs, id, fmt.Sprintf("%v:%v/%v", c["type"], c["resource"], c["collection"]))
+		}
+	}
+	for _, f := range entities("functions") {
+		if id, ok := f["uuid"].(string); ok && id != "" {
+			pairs = append(pairs, id, fmt.Sprintf("function:%v", f["name"]))
+		}
+	}
+
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var labeled map[string]interface{}
+	if err = json.Unmarshal([]byte(strings.NewReplacer(pairs...).Replace(string(b))), &labeled); err != nil {
+		return nil, err
+	}
+
+	if streams, ok := labeled["streams"].([]interface{}); ok {
+		for _, s := range streams {
+			if m, ok := s.(map[string]interface{}); ok {
+				delete(m, "uuid")
+			}
+		}
+	}
+	return labeled, nil
+}
+
+// flattenSpec collects the leaf values of a spec keyed by their path, e.g. "connectors[source:pg/users].collection".
+// Connectors, functions and streams are keyed by what identifies them rather than by their index, so that
+// reordering them isn't shown as changes.
+func flattenSpec(prefix string, v interface{}, out map[string]interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			p := k
+			if prefix != "" {
+				p = prefix + "." + k
+			}
+			flattenSpec(p, e, out)
+		}
+	case []interface{}:
+		seen := map[string]bool{}
+		for i, e := range v {
+			key, ok := specEntryKey(prefix, e)
+			if !ok || seen[key] {
+				key = strconv.Itoa(i)
+			}
+			seen[key] = true
+			flattenSpec(fmt.Sprintf("%s[%s]", prefix, key), e, out)
+		}
+	default:
+		out[prefix] = v
+	}
+}
+
+// specEntryKey returns what identifies an entry of a list of a spec labeled by labelSpecUUIDs: the type, resource
+// and collection of connectors, the name of functions and the entities streams connect.
+func specEntryKey(list string, e interface{}) (string, bool) {
+	m, ok := e.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	switch list {
+	case "connectors":
+		return fmt.Sprintf("%v:%v/%v", m["type"], m["resource"], m["collection"]), true
+	case "functions":
+		name, ok := m["name"].(string)
+		return name, ok && name != ""
+	case "streams":
+		from, _ := m["from_uuid"].(string)
+		to, _ := m["to_uuid"].(string)
+		return from + "->" + to, from != "" || to != ""
+	}
+	return "", false
+}
+
+func formatSpecValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
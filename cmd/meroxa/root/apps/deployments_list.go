@@ -0,0 +1,117 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"context"
+	"sort"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var (
+	_ builder.CommandWithDocs      = (*ListDeployments)(nil)
+	_ builder.CommandWithFlags     = (*ListDeployments)(nil)
+	_ builder.CommandWithClient    = (*ListDeployments)(nil)
+	_ builder.CommandWithLogger    = (*ListDeployments)(nil)
+	_ builder.CommandWithExecute   = (*ListDeployments)(nil)
+	_ builder.CommandWithAliases   = (*ListDeployments)(nil)
+	_ builder.CommandWithNoHeaders = (*ListDeployments)(nil)
+)
+
+type listDeploymentsClient interface {
+	GetApplication(ctx context.Context, nameOrUUID string) (*meroxa.Application, error)
+	GetDeployment(ctx context.Context, appName string, depUUID string) (*meroxa.Deployment, error)
+}
+
+type ListDeployments struct {
+	client      listDeploymentsClient
+	logger      log.Logger
+	hideHeaders bool
+
+	flags struct {
+		App  string `long:"app" usage:"application name or UUID"`
+		Path string `long:"path" usage:"Path to the app directory (default is local directory)"`
+	}
+}
+
+func (l *ListDeployments) Usage() string {
+	return "list [--app NAME] [--path pwd]"
+}
+
+func (l *ListDeployments) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "List the deployments of a Turbine Data Application, most recent first",
+		Example: `meroxa apps deployments list # assumes that the Application is in the current directory
+meroxa apps deployments list --app my-app`,
+	}
+}
+
+func (l *ListDeployments) Aliases() []string {
+	return []string{"ls"}
+}
+
+func (l *ListDeployments) Flags() []builder.Flag {
+	return builder.BuildFlags(&l.flags)
+}
+
+func (l *ListDeployments) Client(client meroxa.Client) {
+	l.client = client
+}
+
+func (l *ListDeployments) Logger(logger log.Logger) {
+	l.logger = logger
+}
+
+func (l *ListDeployments) HideHeaders(hide bool) {
+	l.hideHeaders = hide
+}
+
+func (l *ListDeployments) Execute(ctx context.Context) error {
+	appName, err := deploymentsAppName(l.flags.App, l.flags.Path)
+	if err != nil {
+		return err
+	}
+
+	app, err := l.client.GetApplication(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	deployments := make([]*meroxa.Deployment, 0, len(app.Deployments))
+	for _, id := range app.Deployments {
+		d, err := l.client.GetDeployment(ctx, app.Name, id.UUID)
+		if err != nil {
+			return err
+		}
+		maskSpecSecrets(d.Spec)
+		deployments = append(deployments, d)
+	}
+	sort.SliceStable(deployments, func(i, j int) bool {
+		return deployments[i].CreatedAt.After(deployments[j].CreatedAt)
+	})
+
+	if len(deployments) == 0 {
+		l.logger.Infof(ctx, "Application %q has no deployments yet.", app.Name)
+		l.logger.JSON(ctx, deployments)
+		return nil
+	}
+	l.logger.Output(ctx, deployments)
+	return nil
+}
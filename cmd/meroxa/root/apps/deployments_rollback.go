@@ -0,0 +1,125 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var (
+	_ builder.CommandWithDocs    = (*RollbackDeployment)(nil)
+	_ builder.CommandWithArgs    = (*RollbackDeployment)(nil)
+	_ builder.CommandWithFlags   = (*RollbackDeployment)(nil)
+	_ builder.CommandWithClient  = (*RollbackDeployment)(nil)
+	_ builder.CommandWithLogger  = (*RollbackDeployment)(nil)
+	_ builder.CommandWithExecute = (*RollbackDeployment)(nil)
+)
+
+type rollbackDeploymentClient interface {
+	GetDeployment(ctx context.Context, appName string, depUUID string) (*meroxa.Deployment, error)
+	GetLatestDeployment(ctx context.Context, nameOrUUID string) (*meroxa.Deployment, error)
+	CreateDeployment(ctx context.Context, input *meroxa.CreateDeploymentInput) (*meroxa.Deployment, error)
+}
+
+type RollbackDeployment struct {
+	client rollbackDeploymentClient
+	logger log.Logger
+
+	args struct {
+		UUID string
+	}
+	flags struct {
+		App  string `long:"app" usage:"application name or UUID"`
+		Path string `long:"path" usage:"Path to the app directory (default is local directory)"`
+	}
+}
+
+func (r *RollbackDeployment) Usage() string {
+	return "rollback UUID [--app NAME] [--path pwd]"
+}
+
+func (r *RollbackDeployment) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Roll back a Turbine Data Application to a previous deployment",
+		Long: `Create a new deployment using the git sha and specification of a previous deployment.
+The code of the application doesn't need to be checked out, the stored specification is submitted as is.`,
+		Example: `meroxa apps deployments rollback 3a1c2c4e-6f26-4a8f-9a3f-6b2f0c4a9d1e --app my-app`,
+	}
+}
+
+func (r *RollbackDeployment) Flags() []builder.Flag {
+	return builder.BuildFlags(&r.flags)
+}
+
+func (r *RollbackDeployment) Client(client meroxa.Client) {
+	r.client = client
+}
+
+func (r *RollbackDeployment) Logger(logger log.Logger) {
+	r.logger = logger
+}
+
+func (r *RollbackDeployment) ParseArgs(args []string) error {
+	if len(args) < 1 {
+		return errors.New("requires the UUID of the deployment to roll back to")
+	}
+	r.args.UUID = args[0]
+	return nil
+}
+
+func (r *RollbackDeployment) Execute(ctx context.Context) error {
+	appName, err := deploymentsAppName(r.flags.App, r.flags.Path)
+	if err != nil {
+		return err
+	}
+
+	target, err := r.client.GetDeployment(ctx, appName, r.args.UUID)
+	if err != nil {
+		return err
+	}
+	if len(target.Spec) == 0 {
+		return fmt.Errorf("deployment %q has no spec to roll back to", target.UUID)
+	}
+
+	if latest, err := r.client.GetLatestDeployment(ctx, appName); err == nil && latest.UUID == target.UUID {
+		return fmt.Errorf("deployment %q is already the latest deployment of application %q", target.UUID, appName)
+	}
+
+	r.logger.Infof(ctx, "Rolling back application %q to deployment %q (git sha %s)...", appName, target.UUID, target.GitSha)
+
+	dep, err := r.client.CreateDeployment(ctx, &meroxa.CreateDeploymentInput{
+		Application: meroxa.EntityIdentifier{Name: appName},
+		GitSha:      target.GitSha,
+		Spec:        target.Spec,
+		SpecVersion: target.SpecVersion,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.logger.Infof(ctx, "Deployment %q successfully created!", dep.UUID)
+	r.logger.Infof(ctx, "Run `meroxa apps deployments describe %s --app %s` to follow its progress.", dep.UUID, appName)
+	maskSpecSecrets(dep.Spec)
+	r.logger.JSON(ctx, dep)
+	return nil
+}
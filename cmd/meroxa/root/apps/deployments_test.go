@@ -0,0 +1,219 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/cli/utils/display"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+	"github.com/meroxa/meroxa-go/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeploymentSpec(connectorUUID, collection string) map[string]interface{} {
+	return map[string]interface{}{
+		"connectors": []interface{}{
+			map[string]interface{}{"uuid": connectorUUID, "type": "source", "resource": "pg", "collection": collection},
+		},
+		"streams": []interface{}{
+			map[string]interface{}{"uuid": "s-" + connectorUUID, "from_uuid": connectorUUID, "to_uuid": "fn-" + connectorUUID},
+		},
+		"functions": []interface{}{
+			map[string]interface{}{"uuid": "fn-" + connectorUUID, "name": "anonymize", "image": "image:latest"},
+		},
+		"definition": map[string]interface{}{"git_sha": "sha-" + collection},
+	}
+}
+
+func TestDeploymentsAppName(t *testing.T) {
+	_, err := deploymentsAppName("my-app", "/my/app")
+	require.EqualError(t, err, "supply either --app or --path flag")
+
+	name, err := deploymentsAppName("my-app", "")
+	require.NoError(t, err)
+	assert.Equal(t, "my-app", name)
+}
+
+func TestListDeploymentsExecute(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	older := &meroxa.Deployment{UUID: "older", GitSha: "aaa", CreatedAt: time.Now().Add(-time.Hour)}
+	newer := &meroxa.Deployment{
+		UUID:      "newer",
+		GitSha:    "bbb",
+		CreatedAt: time.Now(),
+		CreatedBy: "user@meroxa.io",
+		Spec:      map[string]interface{}{"secrets": map[string]interface{}{"API_KEY": "hunter2"}},
+	}
+
+	client.EXPECT().GetApplication(ctx, "my-app").Return(&meroxa.Application{
+		Name:        "my-app",
+		Deployments: []meroxa.EntityIdentifier{{UUID: "older"}, {UUID: "newer"}},
+	}, nil)
+	client.EXPECT().GetDeployment(ctx, "my-app", "older").Return(older, nil)
+	client.EXPECT().GetDeployment(ctx, "my-app", "newer").Return(newer, nil)
+
+	l := &ListDeployments{client: client, logger: logger}
+	l.flags.App = "my-app"
+	require.NoError(t, l.Execute(ctx))
+
+	var got []*meroxa.Deployment
+	require.NoError(t, json.Unmarshal([]byte(logger.JSONOutput()), &got))
+	require.Len(t, got, 2)
+	assert.Equal(t, "newer", got[0].UUID)
+	assert.Equal(t, "user@meroxa.io", got[0].CreatedBy)
+	assert.Equal(t, map[string]interface{}{"API_KEY": "***"}, got[0].Spec["secrets"])
+}
+
+func TestDescribeDeploymentExecute(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	spec := testDeploymentSpec("c1", "orders")
+	spec["secrets"] = map[string]interface{}{"API_KEY": "hunter2"}
+	dep := &meroxa.Deployment{UUID: "latest", GitSha: "bbb", Spec: spec}
+	client.EXPECT().GetLatestDeployment(ctx, "my-app").Return(dep, nil)
+
+	d := &DescribeDeployment{client: client, logger: logger}
+	d.flags.App = "my-app"
+	require.NoError(t, d.Execute(ctx))
+
+	require.NoError(t, logger.OutputErr())
+	out := logger.LeveledOutput()
+	assert.Contains(t, out, display.DeploymentTable(dep))
+	assert.Contains(t, out, "Spec:")
+	assert.Contains(t, out, `"collection": "orders"`)
+	assert.Contains(t, out, `"API_KEY": "***"`)
+	assert.NotContains(t, out, "hunter2")
+	assert.NotContains(t, logger.JSONOutput(), "hunter2")
+}
+
+func TestDiffSpecs(t *testing.T) {
+	// Same app deployed twice gets new UUIDs, which must not show up as changes.
+	changes, err := diffSpecs(testDeploymentSpec("c1", "orders"), testDeploymentSpec("c2", "orders"))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = diffSpecs(testDeploymentSpec("c1", "orders"), testDeploymentSpec("c2", "customers"))
+	require.NoError(t, err)
+	paths := make([]string, 0, len(changes))
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+	}
+	assert.Contains(t, paths, "connectors[source:pg/orders].collection")
+	assert.Contains(t, paths, "connectors[source:pg/customers].collection")
+	assert.Contains(t, paths, "definition.git_sha")
+
+	// Entities are compared by what identifies them, not by their position.
+	from := testDeploymentSpec("c1", "orders")
+	from["connectors"] = append(from["connectors"].([]interface{}),
+		map[string]interface{}{"uuid": "d1", "type": "destination", "resource": "warehouse", "collection": "copy"})
+	to := testDeploymentSpec("c2", "orders")
+	to["connectors"] = append([]interface{}{
+		map[string]interface{}{"uuid": "d2", "type": "destination", "resource": "warehouse", "collection": "copy"},
+	}, to["connectors"].([]interface{})...)
+	to["functions"].([]interface{})[0].(map[string]interface{})["image"] = "image:v2"
+	changes, err = diffSpecs(from, to)
+	require.NoError(t, err)
+	assert.Equal(t, []specChange{{Path: "functions[anonymize].image", From: "image:latest", To: "image:v2"}}, changes)
+}
+
+func TestDiffDeploymentsExecute(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	from, to := testDeploymentSpec("c1", "orders"), testDeploymentSpec("c2", "customers")
+	from["secrets"] = map[string]interface{}{"API_KEY": "hunter2"}
+	to["secrets"] = map[string]interface{}{"API_KEY": "correct-horse"}
+	client.EXPECT().GetDeployment(ctx, "my-app", "from").Return(&meroxa.Deployment{UUID: "from", Spec: from}, nil)
+	client.EXPECT().GetLatestDeployment(ctx, "my-app").Return(&meroxa.Deployment{UUID: "to", Spec: to}, nil)
+
+	d := &DiffDeployments{client: client, logger: logger}
+	require.NoError(t, d.ParseArgs([]string{"from"}))
+	d.flags.App = "my-app"
+	require.NoError(t, d.Execute(ctx))
+
+	out := logger.LeveledOutput()
+	assert.Contains(t, out, `- connectors[source:pg/orders].collection: "orders"`)
+	assert.Contains(t, out, `+ connectors[source:pg/customers].collection: "customers"`)
+	assert.Contains(t, out, `- secrets.API_KEY: "***"`)
+	assert.Contains(t, out, `+ secrets.API_KEY: "***"`)
+	for _, secret := range []string{"hunter2", "correct-horse"} {
+		assert.NotContains(t, out, secret)
+		assert.NotContains(t, logger.JSONOutput(), secret)
+	}
+}
+
+func TestRollbackDeploymentExecute(t *testing.T) {
+	ctx := context.Background()
+	spec := testDeploymentSpec("c1", "orders")
+
+	tests := []struct {
+		name   string
+		client func(*mock.MockClient)
+		err    error
+	}{
+		{
+			name: "Successfully roll back",
+			client: func(client *mock.MockClient) {
+				client.EXPECT().GetDeployment(ctx, "my-app", "old").
+					Return(&meroxa.Deployment{UUID: "old", GitSha: "aaa", Spec: spec, SpecVersion: "0.2.0"}, nil)
+				client.EXPECT().GetLatestDeployment(ctx, "my-app").Return(&meroxa.Deployment{UUID: "broken"}, nil)
+				client.EXPECT().CreateDeployment(ctx, &meroxa.CreateDeploymentInput{
+					Application: meroxa.EntityIdentifier{Name: "my-app"},
+					GitSha:      "aaa",
+					Spec:        spec,
+					SpecVersion: "0.2.0",
+				}).Return(&meroxa.Deployment{UUID: "new"}, nil)
+			},
+		},
+		{
+			name: "Fail to roll back to the latest deployment",
+			client: func(client *mock.MockClient) {
+				client.EXPECT().GetDeployment(ctx, "my-app", "old").
+					Return(&meroxa.Deployment{UUID: "old", Spec: spec}, nil)
+				client.EXPECT().GetLatestDeployment(ctx, "my-app").Return(&meroxa.Deployment{UUID: "old"}, nil)
+			},
+			err: errors.New(`deployment "old" is already the latest deployment of application "my-app"`),
+		},
+		{
+			name: "Fail to roll back to a deployment without spec",
+			client: func(client *mock.MockClient) {
+				client.EXPECT().GetDeployment(ctx, "my-app", "old").Return(&meroxa.Deployment{UUID: "old"}, nil)
+			},
+			err: errors.New(`deployment "old" has no spec to roll back to`),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			client := mock.NewMockClient(ctrl)
+			tc.client(client)
+
+			r := &RollbackDeployment{client: client, logger: log.NewTestLogger()}
+			require.NoError(t, r.ParseArgs([]string{"old"}))
+			r.flags.App = "my-app"
+
+			err := r.Execute(ctx)
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -23,7 +23,6 @@ import (
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/cmd/meroxa/turbine"
 	"github.com/meroxa/cli/log"
-	"github.com/meroxa/cli/utils/display"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
 )
 
@@ -34,6 +33,7 @@ var (
 	_ builder.CommandWithClient  = (*Describe)(nil)
 	_ builder.CommandWithLogger  = (*Describe)(nil)
 	_ builder.CommandWithExecute = (*Describe)(nil)
+	_ builder.CommandWithWatch   = (*Describe)(nil)
 )
 
 type describeApplicationClient interface {
@@ -98,7 +98,7 @@ func (d *Describe) Execute(ctx context.Context) error {
 		nameOrUUID = config.Name
 
 		if d.turbineCLI == nil {
-			d.turbineCLI, err = getTurbineCLIFromLanguage(d.logger, config.Language, d.path)
+			d.turbineCLI, err = TurbineCLI(d.logger, config.Language, d.path)
 			if err != nil {
 				return err
 			}
@@ -115,8 +115,7 @@ func (d *Describe) Execute(ctx context.Context) error {
 		return err
 	}
 
-	d.logger.Info(ctx, display.AppTable(app))
-	d.logger.JSON(ctx, app)
+	d.logger.Output(ctx, app)
 
 	dashboardURL := fmt.Sprintf("https://dashboard.meroxa.io/apps/%s/detail", app.Name)
 	d.logger.Info(ctx, fmt.Sprintf("\n ✨ To view your application, visit %s", dashboardURL))
@@ -138,3 +137,7 @@ func (d *Describe) ParseArgs(args []string) error {
 
 	return nil
 }
+
+func (d *Describe) Watchable() bool {
+	return true
+}
@@ -3,23 +3,34 @@ package apps
 import (
 	"fmt"
 
+	"github.com/meroxa/cli/cmd/meroxa/clierrors"
 	"github.com/meroxa/turbine-core/pkg/ir"
 )
 
 const (
-	resourceInvalidError = `⚠️  Run 'meroxa resources list' to verify that the resource names ` +
+	resourceInvalidHint = `⚠️  Run 'meroxa resources list' to verify that the resource names ` +
 		`defined in your Turbine app are identical to the resources you have ` +
 		`created on the Meroxa Platform before deploying again`
 )
 
 func newLangUnsupportedError(lang ir.Lang) error {
-	return fmt.Errorf(
+	return clierrors.Newf(
+		clierrors.CategoryValidation,
+		"language_unsupported",
 		`language %q not supported. `+
 			`supported languages "javascript", "golang", "python", and "ruby (beta)"`,
 		lang,
 	)
 }
 
+func newResourceInvalidError(err error) error {
+	return clierrors.Wrap(err, clierrors.CategoryValidation, "resource_invalid", resourceInvalidHint)
+}
+
+func newConfigInvalidError(problems int) error {
+	return clierrors.Newf(clierrors.CategoryValidation, "app_config_invalid", "invalid Turbine app, errors found: %d", problems)
+}
+
 func wrapErrors(errs []error) error {
 	if len(errs) == 0 {
 		return nil
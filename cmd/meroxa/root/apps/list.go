@@ -26,12 +26,14 @@ import (
 )
 
 var (
-	_ builder.CommandWithDocs      = (*List)(nil)
-	_ builder.CommandWithClient    = (*List)(nil)
-	_ builder.CommandWithLogger    = (*List)(nil)
-	_ builder.CommandWithExecute   = (*List)(nil)
-	_ builder.CommandWithAliases   = (*List)(nil)
-	_ builder.CommandWithNoHeaders = (*List)(nil)
+	_ builder.CommandWithDocs        = (*List)(nil)
+	_ builder.CommandWithClient      = (*List)(nil)
+	_ builder.CommandWithLogger      = (*List)(nil)
+	_ builder.CommandWithExecute     = (*List)(nil)
+	_ builder.CommandWithAliases     = (*List)(nil)
+	_ builder.CommandWithNoHeaders   = (*List)(nil)
+	_ builder.CommandWithListOptions = (*List)(nil)
+	_ builder.CommandWithWatch       = (*List)(nil)
 )
 
 type listApplicationsClient interface {
@@ -42,6 +44,7 @@ type List struct {
 	client      listApplicationsClient
 	logger      log.Logger
 	hideHeaders bool
+	listOptions display.ListOptions
 }
 
 func (l *List) Usage() string {
@@ -65,8 +68,12 @@ func (l *List) Execute(ctx context.Context) error {
 		return err
 	}
 
-	l.logger.JSON(ctx, apps)
-	l.logger.Info(ctx, display.AppsTable(apps, l.hideHeaders))
+	out, err := display.List(apps, l.listOptions)
+	if err != nil {
+		return err
+	}
+
+	l.logger.Output(ctx, out)
 
 	output := " ✨ To view your applications, visit https://dashboard.meroxa.io/apps"
 	l.logger.Info(ctx, output)
@@ -84,3 +91,11 @@ func (l *List) Client(client meroxa.Client) {
 func (l *List) HideHeaders(hide bool) {
 	l.hideHeaders = hide
 }
+
+func (l *List) ListOptions(o display.ListOptions) {
+	l.listOptions = o
+}
+
+func (l *List) Watchable() bool {
+	return true
+}
@@ -18,7 +18,10 @@ package apps
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/cmd/meroxa/turbine"
@@ -37,21 +40,31 @@ var (
 	_ builder.CommandWithExecute = (*Logs)(nil)
 )
 
+const logsPollInterval = 2 * time.Second
+
 type Logs struct {
-	client     applicationLogsClient
-	logger     log.Logger
-	turbineCLI turbine.CLI
-	path       string
+	client       applicationLogsClient
+	logger       log.Logger
+	turbineCLI   turbine.CLI
+	path         string
+	pollInterval time.Duration
 
 	args struct {
 		NameOrUUID string
 	}
 	flags struct {
-		Path string `long:"path" usage:"Path to the app directory (default is local directory)"`
+		Path   string `long:"path" usage:"Path to the app directory (default is local directory)"`
+		Follow bool   `long:"follow" short:"f" usage:"Keep polling and print new logs as they are emitted"`
+		Since  string `long:"since" usage:"Only show logs newer than a duration (e.g. 10m) or a RFC3339 timestamp"`
+		Until  string `long:"until" usage:"Only show logs older than a duration (e.g. 1h) or a RFC3339 timestamp"`
+		Source string `long:"source" usage:"Only show logs emitted by a connector, function or deployment"`
+		Grep   string `long:"grep" usage:"Only show logs matching a regular expression"`
+		Raw    bool   `long:"raw" usage:"Print logs as newline-delimited JSON, one entry per line"`
 	}
 }
 
 type applicationLogsClient interface {
+	GetApplication(ctx context.Context, nameOrUUID string) (*meroxa.Application, error)
 	GetApplicationLogsV2(ctx context.Context, nameOrUUID string) (*meroxa.Logs, error)
 	AddHeader(key, value string)
 }
@@ -76,7 +89,9 @@ func (l *Logs) Docs() builder.Docs {
 or the Application specified by the given name or UUID identifier.`,
 		Example: `meroxa apps logs # assumes that the Application is in the current directory
 meroxa apps logs --path /my/app
-meroxa apps logs my-turbine-application`,
+meroxa apps logs my-turbine-application
+meroxa apps logs my-turbine-application --follow --source function --grep error
+meroxa apps logs my-turbine-application --since 30m --raw | jq .log`,
 	}
 }
 
@@ -87,8 +102,15 @@ func (l *Logs) Execute(ctx context.Context) error {
 		return fmt.Errorf("supply either NameOrUUID argument or --path flag")
 	}
 
+	filter, err := newLogFilter(l.flags.Since, l.flags.Until, l.flags.Source, l.flags.Grep, time.Now())
+	if err != nil {
+		return err
+	}
+	if l.flags.Follow && !filter.until.IsZero() {
+		return fmt.Errorf("--until can't be used with --follow")
+	}
+
 	if nameOrUUID == "" {
-		var err error
 		if l.path, err = turbine.GetPath(l.flags.Path); err != nil {
 			return err
 		}
@@ -100,7 +122,7 @@ func (l *Logs) Execute(ctx context.Context) error {
 		nameOrUUID = config.Name
 
 		if l.turbineCLI == nil {
-			l.turbineCLI, err = getTurbineCLIFromLanguage(l.logger, config.Language, l.path)
+			l.turbineCLI, err = TurbineCLI(l.logger, config.Language, l.path)
 			if err != nil {
 				return err
 			}
@@ -112,10 +134,27 @@ func (l *Logs) Execute(ctx context.Context) error {
 		addTurbineHeaders(l.client, config.Language, turbineLibVersion)
 	}
 
+	if filter.source != "" {
+		app, err := l.client.GetApplication(ctx, nameOrUUID)
+		if err != nil {
+			return err
+		}
+		filter.sourceKinds = logSourceKinds(app)
+	}
+
+	if l.flags.Follow {
+		return l.follow(ctx, nameOrUUID, filter)
+	}
+
 	appLogs, getErr := l.client.GetApplicationLogsV2(ctx, nameOrUUID)
 	if getErr != nil {
 		return getErr
 	}
+	appLogs = filter.apply(appLogs)
+
+	if l.flags.Raw {
+		return l.printRaw(ctx, appLogs.Data)
+	}
 
 	output := display.LogsTable(appLogs)
 
@@ -125,6 +164,59 @@ func (l *Logs) Execute(ctx context.Context) error {
 	return nil
 }
 
+// follow polls the application logs until the context is canceled, printing entries it hasn't printed yet.
+func (l *Logs) follow(ctx context.Context, nameOrUUID string, filter *logFilter) error {
+	interval := l.pollInterval
+	if interval == 0 {
+		interval = logsPollInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	var cursor logCursor
+	for {
+		appLogs, err := l.client.GetApplicationLogsV2(ctx, nameOrUUID)
+		if err != nil {
+			// Following stops when the command is interrupted, which isn't an error.
+			if errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return err
+		}
+
+		var entries []meroxa.LogData
+		entries, cursor = newLogEntries(filter.apply(appLogs).Data, cursor)
+		if l.flags.Raw {
+			if err = l.printRaw(ctx, entries); err != nil {
+				return err
+			}
+		} else {
+			for i := len(entries) - 1; i >= 0; i-- {
+				l.logger.Info(ctx, display.LogLine(&entries[i]))
+				l.logger.JSON(ctx, entries[i])
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// printRaw prints entries as newline-delimited JSON, oldest first.
+func (l *Logs) printRaw(ctx context.Context, entries []meroxa.LogData) error {
+	for i := len(entries) - 1; i >= 0; i-- {
+		b, err := json.Marshal(entries[i])
+		if err != nil {
+			return err
+		}
+		l.logger.Info(ctx, string(b))
+	}
+	return nil
+}
+
 func (l *Logs) Client(client meroxa.Client) {
 	l.client = client
 }
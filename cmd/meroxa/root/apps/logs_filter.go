@@ -0,0 +1,180 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+const (
+	logSourceConnector  = "connector"
+	logSourceFunction   = "function"
+	logSourceDeployment = "deployment"
+)
+
+type logFilter struct {
+	since, until time.Time
+	source       string
+	grep         *regexp.Regexp
+
+	// sourceKinds maps the names and UUIDs of the app entities to the kind of source they are.
+	sourceKinds map[string]string
+}
+
+func newLogFilter(since, until, source, grep string, now time.Time) (*logFilter, error) {
+	var (
+		f   = &logFilter{source: source}
+		err error
+	)
+
+	if f.since, err = parseLogTime("since", since, now); err != nil {
+		return nil, err
+	}
+	if f.until, err = parseLogTime("until", until, now); err != nil {
+		return nil, err
+	}
+
+	switch source {
+	case "", logSourceConnector, logSourceFunction, logSourceDeployment:
+	default:
+		return nil, fmt.Errorf("invalid --source %q, use either %s, %s or %s",
+			source, logSourceConnector, logSourceFunction, logSourceDeployment)
+	}
+
+	if grep != "" {
+		if f.grep, err = regexp.Compile(grep); err != nil {
+			return nil, fmt.Errorf("invalid --grep expression: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+// parseLogTime accepts either a duration relative to now or a RFC3339 timestamp.
+func parseLogTime(flag, v string, now time.Time) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s %q, use a duration (e.g. 10m) or a RFC3339 timestamp", flag, v)
+	}
+	return t, nil
+}
+
+func logSourceKinds(app *meroxa.Application) map[string]string {
+	kinds := make(map[string]string)
+	for _, c := range app.Connectors {
+		kinds[c.Name] = logSourceConnector
+		kinds[c.UUID] = logSourceConnector
+	}
+	for _, r := range app.Resources {
+		kinds[r.Name] = logSourceConnector
+	}
+	for _, f := range app.Functions {
+		kinds[f.Name] = logSourceFunction
+		kinds[f.UUID] = logSourceFunction
+	}
+	for _, d := range app.Deployments {
+		kinds[d.UUID] = logSourceDeployment
+	}
+	delete(kinds, "")
+	return kinds
+}
+
+func (f *logFilter) match(l *meroxa.LogData) bool {
+	switch {
+	case !f.since.IsZero() && l.Timestamp.Before(f.since):
+		return false
+	case !f.until.IsZero() && l.Timestamp.After(f.until):
+		return false
+	case f.grep != nil && !f.grep.MatchString(l.Log):
+		return false
+	case f.source != "":
+		kind, ok := f.sourceKinds[l.Source]
+		if !ok {
+			// Sources that aren't entities of the app are usually prefixed by their kind (e.g. "deployment-<uuid>").
+			return strings.HasPrefix(l.Source, f.source)
+		}
+		return kind == f.source
+	}
+	return true
+}
+
+// apply returns the logs matching the filter, or the logs untouched when there's nothing to filter on.
+func (f *logFilter) apply(ll *meroxa.Logs) *meroxa.Logs {
+	if f.since.IsZero() && f.until.IsZero() && f.source == "" && f.grep == nil {
+		return ll
+	}
+
+	out := &meroxa.Logs{Metadata: ll.Metadata}
+	for i := range ll.Data {
+		if f.match(&ll.Data[i]) {
+			out.Data = append(out.Data, ll.Data[i])
+		}
+	}
+	return out
+}
+
+// logKey identifies a log entry across polls. The message is part of it so that distinct entries emitted by the same
+// source within the same second aren't mistaken for duplicates.
+type logKey struct {
+	timestamp int64
+	source    string
+	log       string
+}
+
+// logCursor is the newest entry printed when following logs: its timestamp, along with the entries sharing it
+// since more can be emitted at the same time.
+type logCursor struct {
+	last   time.Time
+	atLast map[logKey]bool
+}
+
+// newLogEntries returns the entries newer than the cursor, along with the cursor moved to the newest of them.
+// Entries are compared to the newest one printed rather than to the previous poll only, so that entries are
+// never printed twice, e.g. when they're missing from a poll and come back in the next one.
+func newLogEntries(entries []meroxa.LogData, c logCursor) ([]meroxa.LogData, logCursor) {
+	var fresh []meroxa.LogData
+	next := logCursor{last: c.last, atLast: make(map[logKey]bool, len(c.atLast))}
+	for k := range c.atLast {
+		next.atLast[k] = true
+	}
+	for _, e := range entries {
+		k := logKey{timestamp: e.Timestamp.UnixNano(), source: e.Source, log: e.Log}
+		if e.Timestamp.Before(c.last) || c.atLast[k] {
+			continue
+		}
+		fresh = append(fresh, e)
+
+		if e.Timestamp.After(next.last) {
+			next = logCursor{last: e.Timestamp, atLast: map[logKey]bool{}}
+		}
+		if e.Timestamp.Equal(next.last) {
+			next.atLast[k] = true
+		}
+	}
+	return fresh, next
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -215,3 +216,130 @@ func TestApplicationLogsExecutionWithPath(t *testing.T) {
 		t.Fatalf(cmp.Diff(*appLogs, gotAppLogs))
 	}
 }
+
+func TestApplicationLogsFilter(t *testing.T) {
+	now := time.Date(2022, 10, 18, 12, 0, 0, 0, time.UTC)
+	app := &meroxa.Application{
+		Functions:   []meroxa.EntityDetails{{EntityIdentifier: meroxa.EntityIdentifier{Name: "fun-name"}}},
+		Deployments: []meroxa.EntityIdentifier{{UUID: "deployment-uuid"}},
+	}
+	entries := []meroxa.LogData{
+		{Timestamp: now.Add(-time.Minute), Log: "error processing record", Source: "fun-name"},
+		{Timestamp: now.Add(-time.Hour), Log: "deployed", Source: "deployment-uuid"},
+		{Timestamp: now.Add(-time.Minute), Log: "error connecting", Source: "pg"},
+	}
+
+	tests := []struct {
+		name                       string
+		since, until, source, grep string
+		want                       []string
+		err                        string
+	}{
+		{name: "since duration", since: "10m", want: []string{"fun-name", "pg"}},
+		{name: "until timestamp", until: now.Add(-30 * time.Minute).Format(time.RFC3339), want: []string{"deployment-uuid"}},
+		{name: "source", source: "function", want: []string{"fun-name"}},
+		{name: "grep", grep: "^error", want: []string{"fun-name", "pg"}},
+		{name: "invalid source", source: "resource", err: `invalid --source "resource", use either connector, function or deployment`},
+		{name: "invalid since", since: "yesterday", err: `invalid --since "yesterday", use a duration (e.g. 10m) or a RFC3339 timestamp`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newLogFilter(tc.since, tc.until, tc.source, tc.grep, now)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			f.sourceKinds = logSourceKinds(app)
+
+			var got []string
+			for _, l := range f.apply(&meroxa.Logs{Data: entries}).Data {
+				got = append(got, l.Source)
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestApplicationLogsFollow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	appName := "my-app"
+	now := time.Now().UTC()
+	first := meroxa.LogData{Timestamp: now.Add(-time.Second), Log: "first", Source: "fun-name"}
+	second := meroxa.LogData{Timestamp: now, Log: "second", Source: "fun-name"}
+
+	gomock.InOrder(
+		client.EXPECT().GetApplicationLogsV2(ctx, appName).Return(&meroxa.Logs{Data: []meroxa.LogData{first}}, nil),
+		client.EXPECT().GetApplicationLogsV2(ctx, appName).
+			DoAndReturn(func(context.Context, string) (*meroxa.Logs, error) {
+				cancel()
+				return &meroxa.Logs{Data: []meroxa.LogData{second, first}}, nil
+			}),
+	)
+
+	l := &Logs{client: client, logger: logger, pollInterval: time.Millisecond}
+	l.args.NameOrUUID = appName
+	l.flags.Follow = true
+	l.flags.Raw = true
+
+	require.NoError(t, l.Execute(ctx))
+
+	lines := strings.Split(strings.TrimSpace(logger.LeveledOutput()), "\n")
+	require.Len(t, lines, 2)
+	var got meroxa.LogData
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &got))
+	require.Equal(t, "second", got.Log)
+}
+
+func TestApplicationLogsFollowCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	client.EXPECT().GetApplicationLogsV2(ctx, "my-app").
+		DoAndReturn(func(context.Context, string) (*meroxa.Logs, error) {
+			cancel()
+			return nil, fmt.Errorf("Get \"https://api.meroxa.io/v2/apps/my-app/logs\": %w", context.Canceled)
+		})
+
+	l := &Logs{client: client, logger: log.NewTestLogger(), pollInterval: time.Millisecond}
+	l.args.NameOrUUID = "my-app"
+	l.flags.Follow = true
+
+	require.NoError(t, l.Execute(ctx))
+}
+
+func TestNewLogEntries(t *testing.T) {
+	now := time.Now().UTC()
+	older := meroxa.LogData{Timestamp: now.Add(-time.Second), Log: "older", Source: "fun-name"}
+	first := meroxa.LogData{Timestamp: now, Log: "first", Source: "fun-name"}
+	second := meroxa.LogData{Timestamp: now, Log: "second", Source: "fun-name"}
+	newer := meroxa.LogData{Timestamp: now.Add(time.Second), Log: "newer", Source: "fun-name"}
+
+	entries, cursor := newLogEntries([]meroxa.LogData{first, older}, logCursor{})
+	require.Equal(t, []meroxa.LogData{first, older}, entries)
+
+	// Entries missing from a poll aren't printed again when they come back, nor are older ones.
+	entries, cursor = newLogEntries(nil, cursor)
+	require.Empty(t, entries)
+	entries, cursor = newLogEntries([]meroxa.LogData{second, first, older}, cursor)
+	require.Equal(t, []meroxa.LogData{second}, entries)
+	entries, cursor = newLogEntries([]meroxa.LogData{newer, second, first, older}, cursor)
+	require.Equal(t, []meroxa.LogData{newer}, entries)
+	entries, _ = newLogEntries([]meroxa.LogData{newer, first}, cursor)
+	require.Empty(t, entries)
+}
+
+func TestApplicationLogsFollowWithUntil(t *testing.T) {
+	l := &Logs{}
+	l.args.NameOrUUID = "my-app"
+	l.flags.Follow = true
+	l.flags.Until = "1h"
+
+	require.EqualError(t, l.Execute(context.Background()), "--until can't be used with --follow")
+}
@@ -91,7 +91,7 @@ func (r *Remove) Execute(ctx context.Context) error {
 		nameOrUUID = config.Name
 
 		if r.turbineCLI == nil {
-			r.turbineCLI, err = getTurbineCLIFromLanguage(r.logger, config.Language, r.path)
+			r.turbineCLI, err = TurbineCLI(r.logger, config.Language, r.path)
 			if err != nil {
 				return err
 			}
@@ -18,21 +18,40 @@ package apps
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 
 	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/global"
 	"github.com/meroxa/cli/cmd/meroxa/turbine"
 	"github.com/meroxa/cli/log"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
 )
 
+type runSamplesClient interface {
+	IntrospectResource(ctx context.Context, nameOrID string) (*meroxa.ResourceIntrospection, error)
+}
+
+// runOptionsSetter is implemented by the Turbine CLIs able to run against fixtures from a directory.
+type runOptionsSetter interface {
+	SetRunOptions(turbine.RunOptions)
+}
+
 type Run struct {
-	path   string
-	config *turbine.AppConfig
+	path        string
+	config      *turbine.AppConfig
+	secretStore secretStore
 
+	client     runSamplesClient
 	logger     log.Logger
 	turbineCLI turbine.CLI
 
 	flags struct {
-		Path string `long:"path" usage:"path of application to run"`
+		Path     string `long:"path" usage:"path of application to run"`
+		Fixtures string `long:"fixtures" usage:"directory with a fixture file per resource collection, named RESOURCE/COLLECTION.json"`
+		Out      string `long:"out" usage:"directory where the records written to each destination are saved, as RESOURCE/COLLECTION.json"`
+		Record   bool   `long:"record" usage:"save sample records of the resources read by the application in --fixtures before running it"`
 	}
 }
 
@@ -44,15 +63,26 @@ var (
 )
 
 func (*Run) Usage() string {
-	return "run [--path pwd]"
+	return "run [--path pwd] [--fixtures DIR] [--out DIR] [--record]"
 }
 
 func (*Run) Docs() builder.Docs {
 	return builder.Docs{
 		Short: "Execute a Turbine Data Application locally",
-		Long:  "meroxa apps run will build your app locally to then run it locally in --path.",
+		Long: `meroxa apps run will build your app locally to then run it locally in --path.
+
+By default, records are read from the fixtures declared in app.json and the records written to destinations
+are printed. Use --fixtures to read them from a directory instead, with a JSON array of records per resource
+collection in RESOURCE/COLLECTION.json, and --out to save the records written to each destination the same way.
+With --record, sample records of each collection read by the application are fetched from Meroxa and saved
+in --fixtures, so the run can later be replayed without any live resources.
+
+The secrets stored with 'meroxa apps secrets set' and the ones of app.json are set as environment variables,
+unless they're already set.`,
 		Example: `meroxa apps run 			# assumes you run it from the app directory
 meroxa apps run --path ../go-demo 	# it'll use lang defined in your app.json
+meroxa apps run --fixtures fixtures/ --out out/
+meroxa apps run --fixtures fixtures/ --record
 `,
 	}
 }
@@ -66,23 +96,101 @@ func (r *Run) Flags() []builder.Flag {
 }
 
 func (r *Run) Execute(ctx context.Context) error {
-	if r.turbineCLI != nil {
-		return r.turbineCLI.Run(ctx)
+	if r.flags.Record && r.flags.Fixtures == "" {
+		return errors.New("--record requires --fixtures to know where to save the recorded records")
 	}
 
 	var err error
-	if r.config == nil {
-		if r.path, err = turbine.GetPath(r.flags.Path); err != nil {
-			return err
+	if r.turbineCLI == nil {
+		if r.config == nil {
+			if r.path, err = turbine.GetPath(r.flags.Path); err != nil {
+				return err
+			}
+			if r.config, err = turbine.ReadConfigFile(r.path); err != nil {
+				return err
+			}
 		}
-		if r.config, err = turbine.ReadConfigFile(r.path); err != nil {
+
+		if r.turbineCLI, err = TurbineCLI(r.logger, r.config.Language, r.path); err != nil {
 			return err
 		}
 	}
 
-	if r.turbineCLI, err = getTurbineCLIFromLanguage(r.logger, r.config.Language, r.path); err != nil {
+	if err = r.setRunOptions(); err != nil {
+		return err
+	}
+	if err = r.setSecrets(); err != nil {
 		return err
 	}
 
 	return r.turbineCLI.Run(ctx)
 }
+
+// setSecrets sets the secrets of the app as environment variables, which is where the app reads them from when
+// it runs locally. Variables that are already set are kept.
+func (r *Run) setSecrets() error {
+	if r.config == nil {
+		return nil
+	}
+
+	secrets, err := appSecrets(appSecretStore(r.secretStore, r.config.Name), r.config)
+	if err != nil {
+		return err
+	}
+	for name, value := range secrets {
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		if err = os.Setenv(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Run) setRunOptions() error {
+	if r.flags.Fixtures == "" && r.flags.Out == "" {
+		return nil
+	}
+
+	s, ok := r.turbineCLI.(runOptionsSetter)
+	if !ok {
+		// The Turbine CLI can be given without the config of the app.
+		if r.config == nil {
+			return errors.New("--fixtures and --out are not supported for this application")
+		}
+		return fmt.Errorf("--fixtures and --out are not supported for %s applications", r.config.Language)
+	}
+
+	opts := turbine.RunOptions{
+		FixturesDir: r.flags.Fixtures,
+		OutDir:      r.flags.Out,
+		Logger:      r.logger,
+	}
+	if r.flags.Record {
+		if r.client == nil {
+			c, err := global.NewClient()
+			if err != nil {
+				return err
+			}
+			r.client = c
+		}
+		opts.Samples = r.fetchSamples
+	}
+	s.SetRunOptions(opts)
+	return nil
+}
+
+// fetchSamples returns the sample records of a resource collection, as found by introspecting the resource.
+func (r *Run) fetchSamples(ctx context.Context, resource, collection string) ([]string, error) {
+	ri, err := r.client.IntrospectResource(ctx, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	samples, ok := ri.Samples[collection]
+	if !ok {
+		return nil, fmt.Errorf("no sample records found for collection %q of resource %q", collection, resource)
+	}
+	return samples, nil
+}
@@ -3,6 +3,7 @@ package apps
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
@@ -16,6 +17,8 @@ import (
 	mockturbinecli "github.com/meroxa/cli/cmd/meroxa/turbine/mock"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/cli/utils"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+	"github.com/meroxa/meroxa-go/pkg/mock"
 )
 
 func TestRunAppFlags(t *testing.T) {
@@ -26,6 +29,9 @@ func TestRunAppFlags(t *testing.T) {
 		hidden    bool
 	}{
 		{name: "path", required: false},
+		{name: "fixtures", required: false},
+		{name: "out", required: false},
+		{name: "record", required: false},
 	}
 
 	c := builder.BuildCobraCommand(&Run{})
@@ -114,9 +120,10 @@ func TestRunExecute(t *testing.T) {
 		t.Run(tt.desc, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			u := &Run{
-				logger:     log.NewTestLogger(),
-				config:     &tt.config,
-				turbineCLI: tt.cli(ctrl),
+				logger:      log.NewTestLogger(),
+				config:      &tt.config,
+				turbineCLI:  tt.cli(ctrl),
+				secretStore: fakeSecretStore{},
 			}
 
 			if global.Config == nil {
@@ -138,6 +145,103 @@ func TestRunExecute(t *testing.T) {
 	}
 }
 
+func TestRunExecuteWithRecord(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	u := &Run{
+		logger:     log.NewTestLogger(),
+		turbineCLI: mockturbinecli.NewMockCLI(ctrl),
+	}
+	u.flags.Record = true
+
+	err := u.Execute(ctx)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	processError(t, err, fmt.Errorf("--record requires --fixtures to know where to save the recorded records"))
+}
+
+func TestRunExecuteWithFixturesUnsupported(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	u := &Run{
+		logger:     log.NewTestLogger(),
+		turbineCLI: mockturbinecli.NewMockCLI(ctrl),
+	}
+	u.flags.Fixtures = "fixtures"
+
+	err := u.Execute(ctx)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	processError(t, err, fmt.Errorf("--fixtures and --out are not supported for this application"))
+
+	u.config = &turbine.AppConfig{Name: "py-test", Language: ir.Python}
+	err = u.Execute(ctx)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	processError(t, err, fmt.Errorf("--fixtures and --out are not supported for python applications"))
+}
+
+func TestRunSetSecrets(t *testing.T) {
+	t.Setenv("RUN_API_KEY", "from-env")
+	t.Setenv("RUN_CONFIG_SECRET", "")
+	t.Setenv("RUN_STORED_SECRET", "")
+	os.Unsetenv("RUN_CONFIG_SECRET")
+	os.Unsetenv("RUN_STORED_SECRET")
+
+	u := &Run{
+		config: &turbine.AppConfig{
+			Name:     "my-app",
+			Language: ir.GoLang,
+			Secrets:  map[string]string{"RUN_CONFIG_SECRET": "from-app-json"},
+		},
+		secretStore: fakeSecretStore{"RUN_API_KEY": "stored", "RUN_STORED_SECRET": "stored"},
+	}
+	if err := u.setSecrets(); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	for name, want := range map[string]string{
+		"RUN_API_KEY":       "from-env",
+		"RUN_CONFIG_SECRET": "from-app-json",
+		"RUN_STORED_SECRET": "stored",
+	} {
+		if got := os.Getenv(name); got != want {
+			t.Errorf("expected %s to be %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestRunFetchSamples(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	client.EXPECT().IntrospectResource(ctx, "pg").Return(&meroxa.ResourceIntrospection{
+		Samples: map[string][]string{"orders": {`{"id":1}`}},
+	}, nil).Times(2)
+
+	u := &Run{client: client}
+
+	samples, err := u.fetchSamples(ctx, "pg", "orders")
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if len(samples) != 1 || samples[0] != `{"id":1}` {
+		t.Fatalf("unexpected samples %v", samples)
+	}
+
+	_, err = u.fetchSamples(ctx, "pg", "customers")
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	processError(t, err, fmt.Errorf(`no sample records found for collection "customers" of resource "pg"`))
+}
+
 // setFeatures sets features from a map which designates enabled/disabled features.
 func setFeatures(features map[string]bool) {
 	currentFlags := getFeatures()
@@ -0,0 +1,157 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/cmd/meroxa/turbine"
+	"github.com/spf13/cobra"
+)
+
+type Secrets struct{}
+
+var (
+	_ builder.CommandWithDocs        = (*Secrets)(nil)
+	_ builder.CommandWithAliases     = (*Secrets)(nil)
+	_ builder.CommandWithSubCommands = (*Secrets)(nil)
+)
+
+func (*Secrets) Aliases() []string {
+	return []string{"secret"}
+}
+
+func (*Secrets) Usage() string {
+	return "secrets"
+}
+
+func (*Secrets) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Manage the secrets of a Turbine Data Application",
+		Long: `Secrets are stored encrypted on this machine, in the Meroxa configuration directory, with the passphrase
+of the encrypted credentials (read from MEROXA_CREDENTIALS_PASSPHRASE, or asked for in a terminal).
+
+They are added to the secrets of the deployment by 'meroxa apps deploy', and set as environment variables
+by 'meroxa apps run'. Secrets in app.json take precedence over the stored ones, which take precedence over
+the ones registered by the application.
+
+The application is the one in '--path' (or current working directory if not specified).`,
+	}
+}
+
+func (*Secrets) SubCommands() []*cobra.Command {
+	return []*cobra.Command{
+		builder.BuildCobraCommand(&ListSecrets{}),
+		builder.BuildCobraCommand(&SetSecrets{}),
+		builder.BuildCobraCommand(&UnsetSecrets{}),
+	}
+}
+
+// secretStore keeps the secrets of an app.
+type secretStore interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+	Keys() ([]string, error)
+}
+
+// secretNameRegex matches the names of environment variables, which is what secrets are to the app.
+var secretNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func validateSecretName(name string) error {
+	if !secretNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q, it can only contain letters, digits and underscores, "+
+			"and can't start with a digit", name)
+	}
+	return nil
+}
+
+// secretsAppConfig returns the app.json found in path, which the secrets belong to.
+func secretsAppConfig(path string) (*turbine.AppConfig, error) {
+	path, err := turbine.GetPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return turbine.ReadConfigFile(path)
+}
+
+// appSecretStore returns store, or the one of the app named appName when it's nil.
+func appSecretStore(store secretStore, appName string) secretStore {
+	if store != nil {
+		return store
+	}
+	return global.AppSecrets(appName)
+}
+
+// appSecrets returns the stored secrets of an app merged with the ones of its app.json, which take precedence.
+// The passphrase is only needed when secrets are stored.
+func appSecrets(store secretStore, appConfig *turbine.AppConfig) (map[string]string, error) {
+	keys, err := store.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]string, len(keys)+len(appConfig.Secrets))
+	for _, k := range keys {
+		if secrets[k], err = store.Get(k); err != nil {
+			return nil, fmt.Errorf("could not read secret %q: %w", k, err)
+		}
+	}
+
+	configSecrets, err := appConfig.ExpandSecrets()
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range configSecrets {
+		secrets[k] = v
+	}
+	return secrets, nil
+}
+
+// parseEnvFile reads the KEY=VALUE lines of an env file. Empty lines and comments are skipped, and values can be
+// quoted.
+func parseEnvFile(r io.Reader) (map[string]string, error) {
+	secrets := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if err := validateSecretName(name); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		secrets[name] = value
+	}
+	return secrets, scanner.Err()
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"context"
+	"sort"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/log"
+)
+
+var (
+	_ builder.CommandWithDocs    = (*ListSecrets)(nil)
+	_ builder.CommandWithFlags   = (*ListSecrets)(nil)
+	_ builder.CommandWithLogger  = (*ListSecrets)(nil)
+	_ builder.CommandWithExecute = (*ListSecrets)(nil)
+	_ builder.CommandWithAliases = (*ListSecrets)(nil)
+)
+
+// Sources of the secrets of an app.
+const (
+	secretSourceStore   = "store"
+	secretSourceAppJSON = "app.json"
+)
+
+type appSecret struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+type ListSecrets struct {
+	logger log.Logger
+	store  secretStore
+
+	flags struct {
+		Path string `long:"path" usage:"Path to the app directory (default is local directory)"`
+	}
+}
+
+func (l *ListSecrets) Usage() string {
+	return "list [--path pwd]"
+}
+
+func (l *ListSecrets) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "List the names of the secrets of a Turbine Data Application",
+		Long: `Lists the secrets stored with 'meroxa apps secrets set' and the ones of app.json. Their values are
+never shown, so no passphrase is needed.`,
+		Example: `meroxa apps secrets list
+meroxa apps secrets list --path ../my-app --json`,
+	}
+}
+
+func (l *ListSecrets) Aliases() []string {
+	return []string{"ls"}
+}
+
+func (l *ListSecrets) Logger(logger log.Logger) {
+	l.logger = logger
+}
+
+func (l *ListSecrets) Flags() []builder.Flag {
+	return builder.BuildFlags(&l.flags)
+}
+
+func (l *ListSecrets) Execute(ctx context.Context) error {
+	appConfig, err := secretsAppConfig(l.flags.Path)
+	if err != nil {
+		return err
+	}
+
+	keys, err := appSecretStore(l.store, appConfig.Name).Keys()
+	if err != nil {
+		return err
+	}
+
+	sources := make(map[string]string, len(keys)+len(appConfig.Secrets))
+	for _, k := range keys {
+		sources[k] = secretSourceStore
+	}
+	// Secrets of app.json take precedence over the stored ones.
+	for k := range appConfig.Secrets {
+		sources[k] = secretSourceAppJSON
+	}
+
+	secrets := make([]appSecret, 0, len(sources))
+	for name, source := range sources {
+		secrets = append(secrets, appSecret{Name: name, Source: source})
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+
+	if len(secrets) == 0 {
+		l.logger.Infof(ctx, "Application %q has no secrets.", appConfig.Name)
+	}
+	for _, s := range secrets {
+		l.logger.Infof(ctx, "%s\t(%s)", s.Name, s.Source)
+	}
+	l.logger.JSON(ctx, secrets)
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/log"
+	"golang.org/x/term"
+)
+
+var (
+	_ builder.CommandWithDocs    = (*SetSecrets)(nil)
+	_ builder.CommandWithArgs    = (*SetSecrets)(nil)
+	_ builder.CommandWithFlags   = (*SetSecrets)(nil)
+	_ builder.CommandWithLogger  = (*SetSecrets)(nil)
+	_ builder.CommandWithExecute = (*SetSecrets)(nil)
+)
+
+type SetSecrets struct {
+	logger log.Logger
+	store  secretStore
+	stdin  io.Reader
+	reader *bufio.Reader
+
+	args struct {
+		Secrets []string
+	}
+
+	flags struct {
+		Path    string `long:"path" usage:"Path to the app directory (default is local directory)"`
+		EnvFile string `long:"env-file" usage:"file with a KEY=VALUE secret per line to import (e.g. .env)"`
+	}
+}
+
+func (s *SetSecrets) Usage() string {
+	return "set [KEY=VALUE | KEY]... [--env-file FILE] [--path pwd]"
+}
+
+func (s *SetSecrets) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Store secrets of a Turbine Data Application",
+		Long: `Stores the given secrets encrypted, replacing any existing secret with the same name.
+
+When only a KEY is given, its value is read from stdin, so that it doesn't end up in your shell history.`,
+		Example: `meroxa apps secrets set API_KEY=abc123 REGION=us-east-1
+meroxa apps secrets set API_KEY < api_key.txt
+meroxa apps secrets set --env-file .env --path ../my-app`,
+	}
+}
+
+func (s *SetSecrets) Logger(logger log.Logger) {
+	s.logger = logger
+}
+
+func (s *SetSecrets) Flags() []builder.Flag {
+	return builder.BuildFlags(&s.flags)
+}
+
+func (s *SetSecrets) ParseArgs(args []string) error {
+	s.args.Secrets = args
+	return nil
+}
+
+func (s *SetSecrets) Execute(ctx context.Context) error {
+	if len(s.args.Secrets) == 0 && s.flags.EnvFile == "" {
+		return errors.New("requires at least one secret or --env-file")
+	}
+
+	config, err := secretsAppConfig(s.flags.Path)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := s.readSecrets()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	store := appSecretStore(s.store, config.Name)
+	for _, name := range names {
+		if err = store.Set(name, secrets[name]); err != nil {
+			return fmt.Errorf("could not store secret %q: %w", name, err)
+		}
+		s.logger.Infof(ctx, "\t%s Secret %q set for application %q", s.logger.SuccessfulCheck(), name, config.Name)
+	}
+	s.logger.JSON(ctx, names)
+	return nil
+}
+
+// readSecrets returns the secrets of --env-file, overridden by the ones given as arguments.
+func (s *SetSecrets) readSecrets() (map[string]string, error) {
+	secrets := make(map[string]string)
+	if s.flags.EnvFile != "" {
+		f, err := os.Open(s.flags.EnvFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		if secrets, err = parseEnvFile(f); err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", s.flags.EnvFile, err)
+		}
+	}
+
+	for _, arg := range s.args.Secrets {
+		name, value, ok := strings.Cut(arg, "=")
+		if err := validateSecretName(name); err != nil {
+			return nil, err
+		}
+		if !ok {
+			var err error
+			if value, err = s.readValue(name); err != nil {
+				return nil, err
+			}
+		}
+		secrets[name] = value
+	}
+	return secrets, nil
+}
+
+// readValue reads the value of the secret name from stdin, without echoing it when it's a terminal.
+func (s *SetSecrets) readValue(name string) (string, error) {
+	if s.stdin == nil && term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintf(os.Stderr, "Value of %s: ", name)
+		b, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	if s.reader == nil {
+		stdin := s.stdin
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+		// A single reader is kept so that the values of several secrets can be read one per line.
+		s.reader = bufio.NewReader(stdin)
+	}
+	line, err := s.reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("could not read secret %q from stdin: %w", name, err)
+	}
+	value := strings.TrimRight(line, "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("no value found on stdin for secret %q", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/config"
+	"github.com/meroxa/cli/log"
+)
+
+var (
+	_ builder.CommandWithDocs    = (*UnsetSecrets)(nil)
+	_ builder.CommandWithArgs    = (*UnsetSecrets)(nil)
+	_ builder.CommandWithFlags   = (*UnsetSecrets)(nil)
+	_ builder.CommandWithLogger  = (*UnsetSecrets)(nil)
+	_ builder.CommandWithExecute = (*UnsetSecrets)(nil)
+)
+
+type UnsetSecrets struct {
+	logger log.Logger
+	store  secretStore
+
+	args struct {
+		Names []string
+	}
+
+	flags struct {
+		Path string `long:"path" usage:"Path to the app directory (default is local directory)"`
+	}
+}
+
+func (u *UnsetSecrets) Usage() string {
+	return "unset KEY... [--path pwd]"
+}
+
+func (u *UnsetSecrets) Docs() builder.Docs {
+	return builder.Docs{
+		Short:   "Remove stored secrets of a Turbine Data Application",
+		Example: `meroxa apps secrets unset API_KEY REGION`,
+	}
+}
+
+func (u *UnsetSecrets) Logger(logger log.Logger) {
+	u.logger = logger
+}
+
+func (u *UnsetSecrets) Flags() []builder.Flag {
+	return builder.BuildFlags(&u.flags)
+}
+
+func (u *UnsetSecrets) ParseArgs(args []string) error {
+	if len(args) == 0 {
+		return errors.New("requires at least one secret name")
+	}
+	u.args.Names = args
+	return nil
+}
+
+func (u *UnsetSecrets) Execute(ctx context.Context) error {
+	appConfig, err := secretsAppConfig(u.flags.Path)
+	if err != nil {
+		return err
+	}
+
+	store := appSecretStore(u.store, appConfig.Name)
+	for _, name := range u.args.Names {
+		err = store.Delete(name)
+		if errors.Is(err, config.ErrCredentialNotFound) {
+			return fmt.Errorf("secret %q not found for application %q", name, appConfig.Name)
+		}
+		if err != nil {
+			return err
+		}
+		u.logger.Infof(ctx, "\t%s Secret %q unset for application %q", u.logger.SuccessfulCheck(), name, appConfig.Name)
+	}
+	u.logger.JSON(ctx, u.args.Names)
+	return nil
+}
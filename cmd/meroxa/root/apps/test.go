@@ -0,0 +1,271 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/turbine"
+	"github.com/meroxa/cli/log"
+)
+
+type Test struct {
+	path   string
+	config *turbine.AppConfig
+
+	logger     log.Logger
+	turbineCLI turbine.CLI
+
+	flags struct {
+		Path     string `long:"path" usage:"path of application to test"`
+		Fixtures string `long:"fixtures" usage:"directory with a fixture file per resource collection (default is the fixtures declared in app.json)"`
+		Golden   string `long:"golden" usage:"directory with the expected records of each destination (default is golden in the app directory)"`
+		Update   bool   `long:"update" usage:"rewrite the golden files with the records written by the application"`
+	}
+}
+
+var (
+	_ builder.CommandWithDocs    = (*Test)(nil)
+	_ builder.CommandWithFlags   = (*Test)(nil)
+	_ builder.CommandWithExecute = (*Test)(nil)
+	_ builder.CommandWithLogger  = (*Test)(nil)
+)
+
+// goldenRecord is a record written to a destination, as compared with golden files. Timestamps are left out since
+// they aren't stable across runs.
+type goldenRecord struct {
+	Key   interface{} `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// goldenDiff is a record which differs from its golden file. Expected or Actual is nil when the record is missing
+// from the golden file or wasn't written by the application.
+type goldenDiff struct {
+	Destination string        `json:"destination"`
+	Index       int           `json:"index"`
+	Expected    *goldenRecord `json:"expected,omitempty"`
+	Actual      *goldenRecord `json:"actual,omitempty"`
+}
+
+func (*Test) Usage() string {
+	return "test [--path pwd] [--fixtures DIR] [--golden DIR] [--update]"
+}
+
+func (*Test) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Test a Turbine Data Application against golden files",
+		Long: `meroxa apps test runs your app locally, like meroxa apps run, and compares the records written to
+each destination with the golden files checked in with your app.
+
+Golden files are named RESOURCE/COLLECTION.json and contain the JSON array of records written to that
+destination collection. Any difference is reported and makes the command fail. Use --update to rewrite
+the golden files with the records written by the app once the changes are expected.`,
+		Example: `meroxa apps test
+meroxa apps test --fixtures fixtures/ --golden golden/
+meroxa apps test --update`,
+	}
+}
+
+func (t *Test) Logger(logger log.Logger) {
+	t.logger = logger
+}
+
+func (t *Test) Flags() []builder.Flag {
+	return builder.BuildFlags(&t.flags)
+}
+
+func (t *Test) Execute(ctx context.Context) error {
+	var err error
+	if t.config == nil {
+		if t.path, err = turbine.GetPath(t.flags.Path); err != nil {
+			return err
+		}
+		if t.config, err = turbine.ReadConfigFile(t.path); err != nil {
+			return err
+		}
+	}
+
+	golden := t.flags.Golden
+	if golden == "" {
+		golden = filepath.Join(t.path, "golden")
+	}
+
+	out, err := os.MkdirTemp("", "meroxa-apps-test")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(out)
+
+	run := &Run{
+		path:       t.path,
+		config:     t.config,
+		logger:     t.logger,
+		turbineCLI: t.turbineCLI,
+	}
+	run.flags.Path = t.flags.Path
+	run.flags.Fixtures = t.flags.Fixtures
+	run.flags.Out = out
+	if err = run.Execute(ctx); err != nil {
+		return err
+	}
+
+	if t.flags.Update {
+		return t.updateGolden(ctx, out, golden)
+	}
+
+	diffs, err := diffGolden(golden, out)
+	if err != nil {
+		return err
+	}
+
+	t.logger.JSON(ctx, diffs)
+	if len(diffs) == 0 {
+		t.logger.Infof(ctx, "\t%s All destinations match the golden files in %s", t.logger.SuccessfulCheck(), golden)
+		return nil
+	}
+
+	for _, d := range diffs {
+		t.logger.Infof(ctx, "%s record %d:", d.Destination, d.Index)
+		if d.Expected != nil {
+			t.logger.Infof(ctx, "- %s", formatSpecValue(d.Expected))
+		}
+		if d.Actual != nil {
+			t.logger.Infof(ctx, "+ %s", formatSpecValue(d.Actual))
+		}
+	}
+	return fmt.Errorf("%d records differ from the golden files in %s, run with --update to accept them", len(diffs), golden)
+}
+
+// updateGolden replaces the golden files with the records written during the run.
+func (t *Test) updateGolden(ctx context.Context, out, golden string) error {
+	written, err := goldenFiles(out)
+	if err != nil {
+		return err
+	}
+	existing, err := goldenFiles(golden)
+	if err != nil {
+		return err
+	}
+
+	for name := range existing {
+		if _, ok := written[name]; !ok {
+			if err = os.Remove(filepath.Join(golden, name)); err != nil {
+				return err
+			}
+		}
+	}
+	for name := range written {
+		b, err := os.ReadFile(filepath.Join(out, name))
+		if err != nil {
+			return err
+		}
+		file := filepath.Join(golden, name)
+		if err = os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		if err = os.WriteFile(file, b, 0o644); err != nil { //nolint:gosec
+			return err
+		}
+	}
+
+	t.logger.Infof(ctx, "\t%s Updated %d golden files in %s", t.logger.SuccessfulCheck(), len(written), golden)
+	return nil
+}
+
+// diffGolden compares the records of each destination written in out with the golden files.
+func diffGolden(golden, out string) ([]goldenDiff, error) {
+	expected, err := goldenFiles(golden)
+	if err != nil {
+		return nil, err
+	}
+	actual, err := goldenFiles(out)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(expected)+len(actual))
+	for name := range expected {
+		names = append(names, name)
+	}
+	for name := range actual {
+		if _, ok := expected[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	diffs := []goldenDiff{}
+	for _, name := range names {
+		want, got := expected[name], actual[name]
+		destination := filepath.ToSlash(strings.TrimSuffix(name, ".json"))
+		for i := 0; i < len(want) || i < len(got); i++ {
+			d := goldenDiff{Destination: destination, Index: i}
+			if i < len(want) {
+				d.Expected = &want[i]
+			}
+			if i < len(got) {
+				d.Actual = &got[i]
+			}
+			if d.Expected == nil || d.Actual == nil || !reflect.DeepEqual(*d.Expected, *d.Actual) {
+				diffs = append(diffs, d)
+			}
+		}
+	}
+	return diffs, nil
+}
+
+// goldenFiles reads the records of every JSON file in dir, keyed by their path relative to dir.
+// A missing dir has no files.
+func goldenFiles(dir string) (map[string][]goldenRecord, error) {
+	files := map[string][]goldenRecord{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var records []goldenRecord
+		if err = json.Unmarshal(b, &records); err != nil {
+			return fmt.Errorf("invalid golden file %s: %w", path, err)
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[name] = records
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,139 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/meroxa/cli/cmd/meroxa/turbine"
+	mockturbinecli "github.com/meroxa/cli/cmd/meroxa/turbine/mock"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/turbine-core/pkg/ir"
+)
+
+// fixtureCLI writes records to the output directory it's given, like a Turbine app run against fixtures would.
+type fixtureCLI struct {
+	*mockturbinecli.MockCLI
+	opts    turbine.RunOptions
+	records map[string]string
+}
+
+func (f *fixtureCLI) SetRunOptions(opts turbine.RunOptions) {
+	f.opts = opts
+}
+
+func (f *fixtureCLI) Run(context.Context) error {
+	for name, records := range f.records {
+		file := filepath.Join(f.opts.OutDir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, []byte(records), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeGolden(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, records := range files {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(records), 0o644))
+	}
+}
+
+func TestTestExecute(t *testing.T) {
+	ctx := context.Background()
+	written := map[string]string{
+		"s3/orders.json": `[{"key":"1","value":{"id":1,"email":"***"},"timestamp":"2022-10-18T12:00:00Z"}]`,
+	}
+
+	tests := []struct {
+		name   string
+		golden map[string]string
+		update bool
+		diffs  []goldenDiff
+		err    string
+	}{
+		{
+			name:   "Records match the golden files",
+			golden: map[string]string{"s3/orders.json": `[{"key":"1","value":{"email":"***","id":1}}]`},
+			diffs:  []goldenDiff{},
+		},
+		{
+			name: "Records differ from the golden files",
+			golden: map[string]string{
+				"s3/orders.json": `[{"key":"1","value":{"id":1,"email":"user@example.com"}}]`,
+				"pg/audit.json":  `[{"key":"1","value":{}}]`,
+			},
+			diffs: []goldenDiff{
+				{
+					Destination: "pg/audit",
+					Expected:    &goldenRecord{Key: "1", Value: map[string]interface{}{}},
+				},
+				{
+					Destination: "s3/orders",
+					Expected:    &goldenRecord{Key: "1", Value: map[string]interface{}{"id": float64(1), "email": "user@example.com"}},
+					Actual:      &goldenRecord{Key: "1", Value: map[string]interface{}{"id": float64(1), "email": "***"}},
+				},
+			},
+			err: "2 records differ from the golden files in %s, run with --update to accept them",
+		},
+		{
+			name:   "Update the golden files",
+			golden: map[string]string{"pg/audit.json": `[]`},
+			update: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			golden := t.TempDir()
+			writeGolden(t, golden, tc.golden)
+
+			logger := log.NewTestLogger()
+			cmd := &Test{
+				logger:     logger,
+				config:     &turbine.AppConfig{Name: "my-app", Language: ir.GoLang},
+				turbineCLI: &fixtureCLI{MockCLI: mockturbinecli.NewMockCLI(gomock.NewController(t)), records: written},
+			}
+			cmd.flags.Golden = golden
+			cmd.flags.Update = tc.update
+
+			err := cmd.Execute(ctx)
+			if tc.err != "" {
+				require.EqualError(t, err, fmt.Sprintf(tc.err, golden))
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tc.update {
+				files, err := goldenFiles(golden)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"s3/orders.json"}, goldenNames(files))
+				return
+			}
+
+			var diffs []goldenDiff
+			require.NoError(t, json.Unmarshal([]byte(logger.JSONOutput()), &diffs))
+			assert.Equal(t, tc.diffs, diffs)
+		})
+	}
+}
+
+func goldenNames(m map[string][]goldenRecord) []string {
+	var out []string
+	for k := range m {
+		out = append(out, filepath.ToSlash(k))
+	}
+	return out
+}
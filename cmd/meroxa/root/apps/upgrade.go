@@ -117,14 +117,11 @@ func (u *Upgrade) Execute(ctx context.Context) error {
 	if u.run == nil {
 		spinner := log.NewSpinnerLogger(buf)
 		leveled := log.NewLeveledLogger(buf, log.Error)
-		u.run = &Run{
-			logger: log.New(leveled, nil, spinner),
-			flags: struct {
-				Path string `long:"path" usage:"path of application to run"`
-			}{
-				Path: u.path,
-			},
+		run := &Run{
+			logger: log.New(leveled, nil, spinner, nil),
 		}
+		run.flags.Path = u.path
+		u.run = run
 	}
 	if err = u.run.Execute(ctx); err != nil {
 		u.logger.Error(ctx, buf.String())
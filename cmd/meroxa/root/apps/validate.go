@@ -0,0 +1,146 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/turbine"
+	"github.com/meroxa/cli/log"
+)
+
+type Validate struct {
+	path       string
+	logger     log.Logger
+	turbineCLI turbine.CLI
+
+	flags struct {
+		Path       string `long:"path" usage:"path of application to validate"`
+		SchemaOnly bool   `long:"schema-only" usage:"only check app.json, without running the application to read its resources"`
+	}
+}
+
+var (
+	_ builder.CommandWithDocs    = (*Validate)(nil)
+	_ builder.CommandWithFlags   = (*Validate)(nil)
+	_ builder.CommandWithExecute = (*Validate)(nil)
+	_ builder.CommandWithLogger  = (*Validate)(nil)
+)
+
+func (*Validate) Usage() string {
+	return "validate [--path pwd] [--schema-only]"
+}
+
+func (*Validate) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Validate the app.json of a Turbine Data Application",
+		Long: `meroxa apps validate checks the app.json of your app against its JSON Schema, published at
+` + turbine.AppConfigSchemaURL + `,
+without the Meroxa Platform.
+
+Unless --schema-only is set, your app is also run locally to read the resources it uses, which are
+checked against the fixtures declared in app.json, and its collections are checked for loops.
+All the problems found are reported at once, with their position in app.json.`,
+		Example: `meroxa apps validate
+meroxa apps validate --path /my/app --schema-only`,
+	}
+}
+
+func (v *Validate) Logger(logger log.Logger) {
+	v.logger = logger
+}
+
+func (v *Validate) Flags() []builder.Flag {
+	return builder.BuildFlags(&v.flags)
+}
+
+func (v *Validate) Execute(ctx context.Context) error {
+	var err error
+	if v.path, err = turbine.GetPath(v.flags.Path); err != nil {
+		return err
+	}
+
+	lint, err := turbine.LintConfigFile(v.path)
+	if err != nil {
+		return err
+	}
+	if lint.Config != nil && !v.flags.SchemaOnly {
+		if err = v.lintResources(ctx, lint); err != nil {
+			return err
+		}
+	}
+
+	v.logger.JSON(ctx, lint.Problems)
+	for _, p := range lint.Problems {
+		v.logger.Info(ctx, p.String())
+	}
+	if n := lint.Errors(); n > 0 {
+		return newConfigInvalidError(n)
+	}
+	v.logger.Infof(ctx, "\t%s App %q is valid", v.logger.SuccessfulCheck(), lint.Config.Name)
+	return nil
+}
+
+// lintResources cross-checks the resources the app uses with the fixtures declared in its app.json.
+func (v *Validate) lintResources(ctx context.Context, lint *turbine.ConfigLint) error {
+	var err error
+	if v.turbineCLI == nil {
+		if v.turbineCLI, err = TurbineCLI(v.logger, lint.Config.Language, v.path); err != nil {
+			return err
+		}
+	}
+
+	// Nothing is deployed, the git sha only identifies the app when it's run and can't be found outside of a repository.
+	gitSha, _ := v.turbineCLI.GetGitSha(ctx, v.path)
+	gracefulStop, err := v.turbineCLI.StartGrpcServer(ctx, gitSha)
+	if err != nil {
+		return err
+	}
+	defer gracefulStop()
+
+	resources, err := v.turbineCLI.GetResources(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to read resource definition from app: %s", err.Error())
+	}
+	if len(resources) == 0 {
+		lint.Add("", turbine.SeverityError, "no resources defined in your Turbine app")
+		return nil
+	}
+
+	used := map[string]bool{}
+	for _, r := range resources {
+		used[r.Name] = true
+		if _, ok := lint.Config.Resources[r.Name]; r.Source && !ok {
+			lint.Add("/resources", turbine.SeverityWarning,
+				"source resource %q has no fixture, meroxa apps run will fail without --fixtures", r.Name)
+		}
+	}
+	for name := range lint.Config.Resources {
+		if !used[name] {
+			lint.Add(turbine.ResourcePointer(name), turbine.SeverityWarning, "resource %q isn't used by the application", name)
+		}
+	}
+
+	sources, destinations, problems := splitCollections(resources)
+	problems = append(problems, validateNoCollectionLoops(sources, destinations)...)
+	for _, p := range problems {
+		lint.Add("", turbine.SeverityError, "%s", p)
+	}
+	return nil
+}
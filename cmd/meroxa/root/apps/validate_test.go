@@ -0,0 +1,129 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/meroxa/cli/cmd/meroxa/turbine"
+	mockturbinecli "github.com/meroxa/cli/cmd/meroxa/turbine/mock"
+	"github.com/meroxa/cli/log"
+)
+
+func TestValidateExecute(t *testing.T) {
+	ctx := context.Background()
+	appJSON := `{
+  "name": "my-app",
+  "language": "golang",
+  "resources": {
+    "pg": "fixtures/pg.json",
+    "unused": "fixtures/pg.json"
+  }
+}`
+
+	tests := []struct {
+		name       string
+		appJSON    string
+		schemaOnly bool
+		resources  []turbine.ApplicationResource
+		problems   []string
+		err        string
+	}{
+		{
+			name:    "Valid app",
+			appJSON: `{"name": "my-app", "language": "golang", "resources": {"pg": "fixtures/pg.json"}}`,
+			resources: []turbine.ApplicationResource{
+				{Name: "pg", Source: true, Collection: "orders"},
+				{Name: "s3", Destination: true, Collection: "orders"},
+			},
+			problems: []string{},
+		},
+		{
+			name:    "Resources not matching app.json",
+			appJSON: appJSON,
+			resources: []turbine.ApplicationResource{
+				{Name: "pg", Source: true, Collection: "orders"},
+				{Name: "mysql", Source: true, Collection: "users"},
+				{Name: "pg", Destination: true, Collection: "orders"},
+			},
+			problems: []string{
+				`1:1: error: Application resource "pg" with collection "orders" cannot be used as a destination. It is also the source.`,
+				`4:3: warning: source resource "mysql" has no fixture, meroxa apps run will fail without --fixtures`,
+				`6:5: warning: resource "unused" isn't used by the application`,
+			},
+			err: "invalid Turbine app, errors found: 1",
+		},
+		{
+			name:     "No resources",
+			appJSON:  appJSON,
+			problems: []string{"1:1: error: no resources defined in your Turbine app"},
+			err:      "invalid Turbine app, errors found: 1",
+		},
+		{
+			name:       "Only app.json",
+			appJSON:    `{"name": "my-app", "language": "golang", "vendor": false}`,
+			schemaOnly: true,
+			problems:   []string{`1:42: error: vendor: value must be one of "true", "false"`},
+			err:        "invalid Turbine app, errors found: 1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "fixtures"), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "fixtures", "pg.json"), []byte("{}"), 0o644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "app.json"), []byte(tc.appJSON), 0o644))
+
+			mockCLI := mockturbinecli.NewMockCLI(gomock.NewController(t))
+			if !tc.schemaOnly {
+				mockCLI.EXPECT().GetGitSha(ctx, dir).Return("", errors.New("not a git repository"))
+				mockCLI.EXPECT().StartGrpcServer(ctx, "").Return(func() {}, nil)
+				mockCLI.EXPECT().GetResources(ctx).Return(tc.resources, nil)
+			}
+
+			logger := log.NewTestLogger()
+			v := &Validate{logger: logger, turbineCLI: mockCLI}
+			v.flags.Path = dir
+			v.flags.SchemaOnly = tc.schemaOnly
+
+			err := v.Execute(ctx)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var problems []turbine.ConfigProblem
+			require.NoError(t, json.Unmarshal([]byte(logger.JSONOutput()), &problems))
+			got := make([]string, 0, len(problems))
+			for _, p := range problems {
+				got = append(got, fmt.Sprintf("%d:%d: %s: %s", p.Line, p.Column, p.Severity, p.Message))
+			}
+			assert.Equal(t, tc.problems, got)
+		})
+	}
+}
+
+func TestValidateExecuteResourcesError(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.json"), []byte(`{"name": "my-app", "language": "golang"}`), 0o644))
+
+	mockCLI := mockturbinecli.NewMockCLI(gomock.NewController(t))
+	mockCLI.EXPECT().GetGitSha(ctx, dir).Return("abc", nil)
+	mockCLI.EXPECT().StartGrpcServer(ctx, "abc").Return(func() {}, nil)
+	mockCLI.EXPECT().GetResources(ctx).Return(nil, errors.New("app failed"))
+
+	v := &Validate{logger: log.NewTestLogger(), turbineCLI: mockCLI}
+	v.flags.Path = dir
+	require.EqualError(t, v.Execute(ctx), "unable to read resource definition from app: app failed")
+}
@@ -19,6 +19,7 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -40,6 +41,7 @@ import (
 
 var (
 	_ builder.CommandWithDocs    = (*Login)(nil)
+	_ builder.CommandWithFlags   = (*Login)(nil)
 	_ builder.CommandWithLogger  = (*Login)(nil)
 	_ builder.CommandWithExecute = (*Login)(nil)
 	_ builder.CommandWithConfig  = (*Login)(nil)
@@ -48,6 +50,18 @@ var (
 type Login struct {
 	logger log.Logger
 	config config.Config
+
+	// authURL is the base URL of the authorization server, defaults to https://MEROXA_AUTH_DOMAIN.
+	authURL string
+	// pollInterval overrides the interval asked by the authorization server while waiting for a device to be authorized.
+	pollInterval time.Duration
+	stdin        io.Reader
+	newClient    func() (getUserClient, error)
+
+	flags struct {
+		Device    bool `long:"device" usage:"login from another device by entering a code, for when no browser is available (e.g. over SSH)"`
+		WithToken bool `long:"with-token" usage:"read an access or refresh token from stdin"`
+	}
 }
 
 func (l *Login) Usage() string {
@@ -57,10 +71,35 @@ func (l *Login) Usage() string {
 func (l *Login) Docs() builder.Docs {
 	return builder.Docs{
 		Short: "Login or Sign up to the Meroxa Platform",
+		Long: `Login or Sign up to the Meroxa Platform.
+
+By default, your browser is opened to authenticate and redirects back to the CLI on a local port.
+When no browser is available, e.g. over SSH or in a container, use --device to get a code to enter
+on any other device, or --with-token to pass a token read from stdin.`,
+		Example: `meroxa login
+meroxa login --device
+echo "$MEROXA_REFRESH_TOKEN" | meroxa login --with-token`,
 	}
 }
 
+func (l *Login) Flags() []builder.Flag {
+	return builder.BuildFlags(&l.flags)
+}
+
 func (l *Login) Execute(ctx context.Context) error {
+	if global.UsingServiceAccount() {
+		l.logger.Warnf(ctx, "Warning: %s is set, commands will keep authenticating with it instead of this login.", global.APIKeyEnv)
+	}
+
+	switch {
+	case l.flags.Device && l.flags.WithToken:
+		return errors.New("--device and --with-token can't be used together")
+	case l.flags.WithToken:
+		return l.loginWithToken(ctx)
+	case l.flags.Device:
+		return l.loginWithDevice(ctx)
+	}
+
 	// initialize the code verifier
 	err := l.login(ctx)
 	if err != nil {
@@ -0,0 +1,229 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/meroxa/cli/cmd/meroxa/global"
+)
+
+const (
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// defaultDeviceInterval is the polling interval to use when the authorization server doesn't ask for one.
+	defaultDeviceInterval = 5 * time.Second
+)
+
+// deviceCode is the response of the device authorization endpoint, see RFC 8628.
+type deviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// oauthError is the error returned by the authorization server, e.g. authorization_pending.
+type oauthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *oauthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Description)
+	}
+	return e.Code
+}
+
+func (l *Login) authEndpoint(path string) string {
+	base := l.authURL
+	if base == "" {
+		base = fmt.Sprintf("https://%s", global.GetMeroxaAuthDomain())
+	}
+	return strings.TrimSuffix(base, "/") + path
+}
+
+// loginWithDevice implements the OAuth2 device authorization grant: the user enters a code on another device
+// while the CLI polls the token endpoint until the code is approved.
+func (l *Login) loginWithDevice(ctx context.Context) error {
+	var code deviceCode
+	err := l.postForm(ctx, l.authEndpoint("/oauth/device/code"), url.Values{
+		"client_id": {global.GetMeroxaAuthClientID()},
+		"audience":  {global.GetMeroxaAuthAudience()},
+		"scope":     {"openid email offline_access user"},
+	}, &code)
+	if err != nil {
+		return fmt.Errorf("could not start device login: %w", err)
+	}
+
+	l.logger.Infof(ctx, "To login, open %s on any device and enter the code:", color.CyanString(code.VerificationURI))
+	l.logger.Infof(ctx, "\n\t%s\n", color.New(color.Bold).Sprint(code.UserCode))
+	if code.VerificationURIComplete != "" {
+		l.logger.Infof(ctx, "Or open %s to have the code filled in.", color.CyanString(code.VerificationURIComplete))
+	}
+	l.logger.Info(ctx, "Waiting for the code to be approved...")
+
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultDeviceInterval
+	}
+	if l.pollInterval != 0 {
+		interval = l.pollInterval
+	}
+	if code.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(code.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errors.New("the device code expired before it was approved, run `meroxa login --device` again")
+			}
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+
+		var token tokenResponse
+		err = l.postForm(ctx, l.authEndpoint("/oauth/token"), url.Values{
+			"grant_type":  {deviceCodeGrantType},
+			"device_code": {code.DeviceCode},
+			"client_id":   {global.GetMeroxaAuthClientID()},
+		}, &token)
+
+		var oauthErr *oauthError
+		switch {
+		case err == nil:
+			l.saveTokens(token.AccessToken, token.RefreshToken)
+			l.logger.Infof(ctx, "Successfully logged in.")
+			return nil
+		case errors.As(err, &oauthErr) && oauthErr.Code == "authorization_pending":
+		case errors.As(err, &oauthErr) && oauthErr.Code == "slow_down":
+			interval += defaultDeviceInterval
+		case errors.As(err, &oauthErr) && oauthErr.Code == "expired_token":
+			return errors.New("the device code expired before it was approved, run `meroxa login --device` again")
+		case errors.As(err, &oauthErr) && oauthErr.Code == "access_denied":
+			return errors.New("the login was denied")
+		default:
+			return fmt.Errorf("could not get access token: %w", err)
+		}
+	}
+}
+
+// loginWithToken reads an access or refresh token from stdin and checks it's valid by fetching the user it belongs to.
+func (l *Login) loginWithToken(ctx context.Context) error {
+	stdin := l.stdin
+	if stdin == nil {
+		stdin = os.Stdin
+	}
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("could not read token from stdin: %w", err)
+	}
+	token := strings.TrimSpace(line)
+	if token == "" {
+		return errors.New("no token found on stdin")
+	}
+
+	// Access tokens are JWTs, refresh tokens are opaque and exchanged for an access token by the client.
+	if isJWT(token) {
+		l.saveTokens(token, "")
+	} else {
+		l.saveTokens("", token)
+	}
+
+	newClient := l.newClient
+	if newClient == nil {
+		newClient = func() (getUserClient, error) {
+			return global.NewClient()
+		}
+	}
+	client, err := newClient()
+	if err != nil {
+		l.saveTokens("", "")
+		return err
+	}
+	user, err := client.GetUser(ctx)
+	if err != nil {
+		l.saveTokens("", "")
+		return fmt.Errorf("invalid token: %w", err)
+	}
+
+	l.logger.Infof(ctx, "Successfully logged in as %s.", user.Email)
+	l.logger.JSON(ctx, user)
+	return nil
+}
+
+func (l *Login) saveTokens(accessToken, refreshToken string) {
+	l.config.Set(global.AccessTokenEnv, accessToken)
+	l.config.Set(global.RefreshTokenEnv, refreshToken)
+	l.config.Set(global.UserAccountUUID, "")
+}
+
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// postForm posts form-encoded values to an authorization server endpoint and decodes its JSON response into out.
+// Errors returned by the server are returned as *oauthError.
+func (l *Login) postForm(ctx context.Context, endpoint string, values url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("content-type", "application/x-www-form-urlencoded")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		oauthErr := &oauthError{}
+		if err = json.Unmarshal(body, oauthErr); err != nil || oauthErr.Code == "" {
+			return fmt.Errorf("unexpected response %s: %s", res.Status, strings.TrimSpace(string(body)))
+		}
+		return oauthErr
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/config"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+	"github.com/meroxa/meroxa-go/pkg/mock"
+)
+
+func TestLoginWithDevice(t *testing.T) {
+	ctx := context.Background()
+	polls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("content-type", "application/json")
+
+		switch r.URL.Path {
+		case "/oauth/device/code":
+			fmt.Fprint(w, `{"device_code":"dev-123","user_code":"ABCD-EFGH","verification_uri":"https://auth.meroxa.io/activate",`+
+				`"expires_in":900,"interval":5}`)
+		case "/oauth/token":
+			if got := r.Form.Get("grant_type"); got != deviceCodeGrantType {
+				t.Fatalf("expected grant type %q, got %q", deviceCodeGrantType, got)
+			}
+			if got := r.Form.Get("device_code"); got != "dev-123" {
+				t.Fatalf("expected device code %q, got %q", "dev-123", got)
+			}
+			polls++
+			if polls < 3 {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"error":"authorization_pending"}`)
+				return
+			}
+			fmt.Fprint(w, `{"access_token":"access","refresh_token":"refresh"}`)
+		default:
+			t.Fatalf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	logger := log.NewTestLogger()
+	cfg := config.NewInMemoryConfig()
+	l := &Login{
+		logger:       logger,
+		config:       cfg,
+		authURL:      server.URL,
+		pollInterval: time.Millisecond,
+	}
+	l.flags.Device = true
+
+	if err := l.Execute(ctx); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if polls != 3 {
+		t.Fatalf("expected 3 polls of the token endpoint, got %d", polls)
+	}
+	if cfg.GetString(global.AccessTokenEnv) != "access" || cfg.GetString(global.RefreshTokenEnv) != "refresh" {
+		t.Fatal("expected tokens to be saved")
+	}
+	if out := logger.LeveledOutput(); !strings.Contains(out, "ABCD-EFGH") || !strings.Contains(out, "https://auth.meroxa.io/activate") {
+		t.Fatalf("expected the user code and verification URL to be shown, got %q", out)
+	}
+}
+
+func TestLoginWithDeviceDenied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/device/code" {
+			fmt.Fprint(w, `{"device_code":"dev-123","user_code":"ABCD-EFGH","verification_uri":"https://auth.meroxa.io/activate"}`)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error":"access_denied","error_description":"User cancelled the confirmation prompt"}`)
+	}))
+	defer server.Close()
+
+	l := &Login{
+		logger:       log.NewTestLogger(),
+		config:       config.NewInMemoryConfig(),
+		authURL:      server.URL,
+		pollInterval: time.Millisecond,
+	}
+	l.flags.Device = true
+
+	err := l.Execute(context.Background())
+	if err == nil || err.Error() != "the login was denied" {
+		t.Fatalf("expected login to be denied, got %v", err)
+	}
+}
+
+func TestLoginWithToken(t *testing.T) {
+	ctx := context.Background()
+	user := &meroxa.User{UUID: "1234", Email: "user@meroxa.io"}
+
+	tests := []struct {
+		desc    string
+		stdin   string
+		err     error
+		access  string
+		refresh string
+	}{
+		{
+			desc:    "Login with a refresh token",
+			stdin:   "v1.refresh-token\n",
+			refresh: "v1.refresh-token",
+		},
+		{
+			desc:   "Login with an access token",
+			stdin:  "header.payload.signature",
+			access: "header.payload.signature",
+		},
+		{
+			desc:  "Login with an invalid token",
+			stdin: "expired\n",
+			err:   errors.New("invalid token: unauthorized"),
+		},
+		{
+			desc:  "Login without a token",
+			stdin: "\n",
+			err:   errors.New("no token found on stdin"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			client := mock.NewMockClient(ctrl)
+			cfg := config.NewInMemoryConfig()
+
+			if tt.stdin != "\n" {
+				if tt.err != nil {
+					client.EXPECT().GetUser(ctx).Return(nil, errors.New("unauthorized"))
+				} else {
+					client.EXPECT().GetUser(ctx).Return(user, nil)
+				}
+			}
+
+			l := &Login{
+				logger: log.NewTestLogger(),
+				config: cfg,
+				stdin:  strings.NewReader(tt.stdin),
+				newClient: func() (getUserClient, error) {
+					return client, nil
+				},
+			}
+			l.flags.WithToken = true
+
+			err := l.Execute(ctx)
+			if tt.err != nil {
+				if err == nil || err.Error() != tt.err.Error() {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("not expected error, got %q", err.Error())
+			}
+			if got := cfg.GetString(global.AccessTokenEnv); got != tt.access {
+				t.Fatalf("expected access token %q, got %q", tt.access, got)
+			}
+			if got := cfg.GetString(global.RefreshTokenEnv); got != tt.refresh {
+				t.Fatalf("expected refresh token %q, got %q", tt.refresh, got)
+			}
+		})
+	}
+}
+
+func TestLoginWithDeviceAndToken(t *testing.T) {
+	l := &Login{}
+	l.flags.Device = true
+	l.flags.WithToken = true
+
+	err := l.Execute(context.Background())
+	if err == nil || err.Error() != "--device and --with-token can't be used together" {
+		t.Fatalf("expected an error, got %v", err)
+	}
+}
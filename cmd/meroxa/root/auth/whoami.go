@@ -52,7 +52,9 @@ func (w *WhoAmI) Usage() string {
 
 func (w *WhoAmI) Docs() builder.Docs {
 	return builder.Docs{
-		Short:   "Display the current logged in user\n",
+		Short: "Display the current logged in user\n",
+		Long: "Display the current logged in user, or the identity of the service account " +
+			"when authenticating with an API key set in MEROXA_API_KEY.",
 		Example: "meroxa whoami",
 	}
 }
@@ -75,6 +77,16 @@ func (w *WhoAmI) Execute(ctx context.Context) error {
 		return err
 	}
 
+	// The identity behind an API key isn't a user, whose information is kept in the config file.
+	if global.UsingServiceAccount() {
+		w.logger.Infof(ctx, "%s (service account, authenticated with %s)", user.Email, global.APIKeyEnv)
+		w.logger.JSON(ctx, struct {
+			*meroxa.User
+			ServiceAccount bool `json:"service_account"`
+		}{User: user, ServiceAccount: true})
+		return nil
+	}
+
 	w.logger.Infof(ctx, "%s", user.Email)
 	w.logger.JSON(ctx, user)
 
@@ -78,3 +78,41 @@ func TestWhoAmIExecution(t *testing.T) {
 		t.Fatalf("expected %q key to be %q", global.ActorUUIDEnv, u.UUID)
 	}
 }
+
+func TestWhoAmIWithServiceAccount(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+	t.Setenv(global.APIKeyEnv, "my-api-key")
+
+	cfg := config.NewInMemoryConfig()
+	w := WhoAmI{
+		logger: logger,
+		client: client,
+		config: cfg,
+	}
+
+	u := meroxa.User{UUID: "1234", Email: "ci@meroxa.io"}
+	client.EXPECT().GetUser(ctx).Return(&u, nil)
+
+	if err := w.Execute(ctx); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	want := "ci@meroxa.io (service account, authenticated with MEROXA_API_KEY)"
+	if got := logger.LeveledOutput(); !strings.Contains(got, want) {
+		t.Fatalf("expected output:\n%s\ngot:\n%s", want, got)
+	}
+
+	var got struct {
+		Email          string `json:"email"`
+		ServiceAccount bool   `json:"service_account"`
+	}
+	if err := json.Unmarshal([]byte(logger.JSONOutput()), &got); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if got.Email != u.Email || !got.ServiceAccount {
+		t.Fatalf("expected service account %q, got %+v", u.Email, got)
+	}
+}
@@ -20,8 +20,6 @@ import (
 	"context"
 	"errors"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
@@ -33,6 +31,7 @@ var (
 	_ builder.CommandWithClient  = (*Describe)(nil)
 	_ builder.CommandWithLogger  = (*Describe)(nil)
 	_ builder.CommandWithExecute = (*Describe)(nil)
+	_ builder.CommandWithWatch   = (*Describe)(nil)
 )
 
 type describeBuildClient interface {
@@ -64,8 +63,7 @@ func (d *Describe) Execute(ctx context.Context) error {
 		return err
 	}
 
-	d.logger.Info(ctx, display.BuildTable(build))
-	d.logger.JSON(ctx, build)
+	d.logger.Output(ctx, build)
 
 	return nil
 }
@@ -86,3 +84,7 @@ func (d *Describe) ParseArgs(args []string) error {
 	d.args.UUID = args[0]
 	return nil
 }
+
+func (d *Describe) Watchable() bool {
+	return true
+}
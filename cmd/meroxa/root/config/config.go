@@ -49,5 +49,6 @@ func (*Config) SubCommands() []*cobra.Command {
 	return []*cobra.Command{
 		builder.BuildCobraCommand(&Describe{}),
 		builder.BuildCobraCommand(&Set{}),
+		builder.BuildCobraCommand(&MigrateCredentials{}),
 	}
 }
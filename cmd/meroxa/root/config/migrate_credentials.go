@@ -0,0 +1,83 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/log"
+)
+
+var (
+	_ builder.CommandWithDocs    = (*MigrateCredentials)(nil)
+	_ builder.CommandWithFlags   = (*MigrateCredentials)(nil)
+	_ builder.CommandWithLogger  = (*MigrateCredentials)(nil)
+	_ builder.CommandWithExecute = (*MigrateCredentials)(nil)
+)
+
+type MigrateCredentials struct {
+	logger log.Logger
+
+	flags struct {
+		To string `long:"to" usage:"credentials store to move your credentials to" required:"true"`
+	}
+}
+
+func (m *MigrateCredentials) Usage() string {
+	return "migrate-credentials"
+}
+
+func (m *MigrateCredentials) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Move your Meroxa credentials to another credentials store",
+		Long: "By default, your access and refresh tokens are kept in plaintext in your configuration file. " +
+			"This command moves the tokens of the current context to another store and removes them from the previous one:\n\n" +
+			"  config          the configuration file (default)\n" +
+			"  secret-service  the Secret Service of your desktop, e.g. GNOME Keyring or KWallet, through `secret-tool`\n" +
+			"  encrypted-file  a file encrypted with a passphrase, read from MEROXA_CREDENTIALS_PASSPHRASE or prompted for\n" +
+			"  file            a separate file only readable by you\n\n" +
+			"The store is saved as MEROXA_CREDENTIALS_STORE in your configuration, " +
+			"and can be overridden with the MEROXA_CREDENTIALS_STORE environment variable.",
+		Example: "meroxa config migrate-credentials --to secret-service\n" +
+			"MEROXA_CREDENTIALS_PASSPHRASE=... meroxa config migrate-credentials --to encrypted-file",
+	}
+}
+
+func (m *MigrateCredentials) Flags() []builder.Flag {
+	return builder.BuildFlags(&m.flags)
+}
+
+func (m *MigrateCredentials) Logger(logger log.Logger) {
+	m.logger = logger
+}
+
+func (m *MigrateCredentials) Execute(ctx context.Context) error {
+	from, err := global.MigrateCredentials(m.flags.To)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Infof(ctx, "Credentials of context %q moved from the %s store to the %s store.", global.CurrentContext(), from, m.flags.To)
+	m.logger.JSON(ctx, map[string]string{
+		"context": global.CurrentContext(),
+		"from":    from,
+		"to":      m.flags.To,
+	})
+	return nil
+}
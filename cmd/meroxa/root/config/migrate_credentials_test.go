@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/log"
+)
+
+func TestMigrateCredentialsExecute(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(global.ContextEnv, "")
+	t.Setenv(global.CredentialsStoreEnv, "")
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.env")
+	if err := os.WriteFile(file, []byte("ACCESS_TOKEN=access\nREFRESH_TOKEN=refresh\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	global.RegisterGlobalFlags(cmd)
+	if err := cmd.PersistentFlags().Set("cli-config-file", file); err != nil {
+		t.Fatal(err)
+	}
+	if err := global.PersistentPreRunE(cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := log.NewTestLogger()
+	m := &MigrateCredentials{logger: logger}
+	m.flags.To = global.CredentialsStoreFile
+
+	if err := m.Execute(ctx); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "credentials.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored map[string]string
+	if err = json.Unmarshal(b, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored["default/ACCESS_TOKEN"] != "access" || stored["default/REFRESH_TOKEN"] != "refresh" {
+		t.Fatalf("expected tokens to be moved to the file store, got %v", stored)
+	}
+
+	b, err = os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg := string(b); strings.Contains(cfg, "access") || strings.Contains(cfg, "refresh") ||
+		!strings.Contains(cfg, "MEROXA_CREDENTIALS_STORE=file") {
+		t.Fatalf("expected tokens to be removed from the config file, got %q", cfg)
+	}
+
+	// Tokens are now read from the new store.
+	if err = global.PersistentPreRunE(cmd); err != nil {
+		t.Fatal(err)
+	}
+	access, refresh, err := global.GetUserToken()
+	if err != nil || access != "access" || refresh != "refresh" {
+		t.Fatalf("expected tokens to be read from the file store, got %q, %q (%v)", access, refresh, err)
+	}
+
+	if err = m.Execute(ctx); err == nil || err.Error() != "credentials are already kept in the file store" {
+		t.Fatalf("expected an error, got %v", err)
+	}
+
+	m.flags.To = "keychain"
+	if err = m.Execute(ctx); err == nil || !strings.HasPrefix(err.Error(), `unknown credentials store "keychain"`) {
+		t.Fatalf("expected an error, got %v", err)
+	}
+}
@@ -20,8 +20,6 @@ import (
 	"context"
 	"errors"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
@@ -34,6 +32,7 @@ var (
 	_ builder.CommandWithLogger     = (*Describe)(nil)
 	_ builder.CommandWithExecute    = (*Describe)(nil)
 	_ builder.CommandWithDeprecated = (*Describe)(nil)
+	_ builder.CommandWithWatch      = (*Describe)(nil)
 )
 
 type describeConnectorClient interface {
@@ -65,8 +64,7 @@ func (d *Describe) Execute(ctx context.Context) error {
 		return err
 	}
 
-	d.logger.Info(ctx, display.ConnectorTable(connector))
-	d.logger.JSON(ctx, connector)
+	d.logger.Output(ctx, connector)
 
 	return nil
 }
@@ -91,3 +89,7 @@ func (d *Describe) ParseArgs(args []string) error {
 func (*Describe) Deprecated() string {
 	return "we encourage you to describe your application via `apps describe` instead."
 }
+
+func (d *Describe) Watchable() bool {
+	return true
+}
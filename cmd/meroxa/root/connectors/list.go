@@ -19,22 +19,23 @@ package connectors
 import (
 	"context"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
+	"github.com/meroxa/cli/utils/display"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
 )
 
 var (
-	_ builder.CommandWithDocs       = (*List)(nil)
-	_ builder.CommandWithClient     = (*List)(nil)
-	_ builder.CommandWithLogger     = (*List)(nil)
-	_ builder.CommandWithExecute    = (*List)(nil)
-	_ builder.CommandWithFlags      = (*List)(nil)
-	_ builder.CommandWithAliases    = (*List)(nil)
-	_ builder.CommandWithNoHeaders  = (*List)(nil)
-	_ builder.CommandWithDeprecated = (*List)(nil)
+	_ builder.CommandWithDocs        = (*List)(nil)
+	_ builder.CommandWithClient      = (*List)(nil)
+	_ builder.CommandWithLogger      = (*List)(nil)
+	_ builder.CommandWithExecute     = (*List)(nil)
+	_ builder.CommandWithFlags       = (*List)(nil)
+	_ builder.CommandWithAliases     = (*List)(nil)
+	_ builder.CommandWithNoHeaders   = (*List)(nil)
+	_ builder.CommandWithDeprecated  = (*List)(nil)
+	_ builder.CommandWithListOptions = (*List)(nil)
+	_ builder.CommandWithWatch       = (*List)(nil)
 )
 
 type listConnectorsClient interface {
@@ -47,6 +48,7 @@ type List struct {
 	client      listConnectorsClient
 	logger      log.Logger
 	hideHeaders bool
+	listOptions display.ListOptions
 
 	flags struct {
 		Pipeline string `long:"pipeline" short:""  usage:"filter connectors by pipeline name"`
@@ -60,6 +62,9 @@ func (l *List) Usage() string {
 func (l *List) Docs() builder.Docs {
 	return builder.Docs{
 		Short: "List connectors",
+		Example: `meroxa connectors list --filter state=failed
+meroxa connectors list --filter environment=prod --sort-by created_at --limit 10
+meroxa connectors list --columns name,state,pipeline`,
 	}
 }
 
@@ -86,8 +91,12 @@ func (l *List) Execute(ctx context.Context) error {
 		}
 	}
 
-	l.logger.JSON(ctx, connectors)
-	l.logger.Info(ctx, display.ConnectorsTable(connectors, l.hideHeaders))
+	out, err := display.List(connectors, l.listOptions)
+	if err != nil {
+		return err
+	}
+
+	l.logger.Output(ctx, out)
 
 	return nil
 }
@@ -111,3 +120,11 @@ func (l *List) HideHeaders(hide bool) {
 func (*List) Deprecated() string {
 	return "we encourage you to list your applications via `apps list` instead."
 }
+
+func (l *List) ListOptions(o display.ListOptions) {
+	l.listOptions = o
+}
+
+func (l *List) Watchable() bool {
+	return true
+}
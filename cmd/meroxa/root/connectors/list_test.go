@@ -23,14 +23,13 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/log"
 
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 
 	"github.com/golang/mock/gomock"
 	"github.com/meroxa/cli/utils"
+	"github.com/meroxa/cli/utils/display"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
 	"github.com/meroxa/meroxa-go/pkg/mock"
 )
@@ -56,6 +55,10 @@ func TestListConnectorsFlags(t *testing.T) {
 		shorthand string
 	}{
 		{name: "pipeline", required: false, shorthand: ""},
+		{name: "filter", required: false, shorthand: ""},
+		{name: "sort-by", required: false, shorthand: ""},
+		{name: "columns", required: false, shorthand: ""},
+		{name: "limit", required: false, shorthand: ""},
 	}
 
 	c := builder.BuildCobraCommand(&List{})
@@ -125,3 +128,59 @@ func TestListConnectorsExecution(t *testing.T) {
 		t.Fatalf("expected \"%v\", got \"%v\"", connectors, gotConnectors)
 	}
 }
+
+func TestListConnectorsExecutionWithListOptions(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	var connectors []*meroxa.Connector
+	for _, c := range []struct {
+		name  string
+		state meroxa.ConnectorState
+	}{
+		{name: "c", state: meroxa.ConnectorStateFailed},
+		{name: "b", state: meroxa.ConnectorStateRunning},
+		{name: "a", state: meroxa.ConnectorStateFailed},
+		{name: "d", state: meroxa.ConnectorStateFailed},
+	} {
+		connector := utils.GenerateConnector("", c.name)
+		connector.State = c.state
+		connectors = append(connectors, &connector)
+	}
+
+	client.
+		EXPECT().
+		ListConnectors(ctx).
+		Return(connectors, nil)
+
+	listOptions, err := display.ParseListOptions([]string{"state=failed"}, "name", []string{"name", "state"}, 2)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	lc := &List{
+		client:      client,
+		logger:      logger,
+		listOptions: listOptions,
+	}
+
+	err = lc.Execute(ctx)
+	if err != nil {
+		t.Fatalf("not expected error, got \"%s\"", err.Error())
+	}
+
+	var got []map[string]string
+	if err = json.Unmarshal([]byte(logger.JSONOutput()), &got); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	want := []map[string]string{
+		{"name": "a", "state": "failed"},
+		{"name": "c", "state": "failed"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected \"%v\", got \"%v\"", want, got)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contexts
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+)
+
+type Contexts struct{}
+
+var (
+	_ builder.CommandWithAliases     = (*Contexts)(nil)
+	_ builder.CommandWithDocs        = (*Contexts)(nil)
+	_ builder.CommandWithSubCommands = (*Contexts)(nil)
+)
+
+func (*Contexts) Usage() string {
+	return "contexts"
+}
+
+func (*Contexts) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Manage the contexts of your Meroxa CLI, each with its own credentials and settings",
+		Long: `A context holds its own credentials, API URL, default environment and account, so you can switch between
+Meroxa accounts (e.g. staging and production) without logging in again.
+
+The context used by a command is, in order of precedence, the one given with --context, the one set in the
+MEROXA_CONTEXT environment variable or the current context set with ` + "`meroxa contexts use`" + `.
+The "default" context uses your main configuration file.`,
+		Example: `meroxa contexts create staging --url https://api.staging.meroxa.io
+meroxa login --context staging
+meroxa contexts use staging
+MEROXA_CONTEXT=production meroxa apps list`,
+	}
+}
+
+func (*Contexts) Aliases() []string {
+	return []string{"context", "ctx"}
+}
+
+func (*Contexts) SubCommands() []*cobra.Command {
+	return []*cobra.Command{
+		builder.BuildCobraCommand(&Create{}),
+		builder.BuildCobraCommand(&List{}),
+		builder.BuildCobraCommand(&Remove{}),
+		builder.BuildCobraCommand(&Use{}),
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contexts
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/log"
+)
+
+// setupConfig points the CLI configuration to a temporary file.
+func setupConfig(t *testing.T) {
+	t.Helper()
+	t.Setenv(global.ContextEnv, "")
+
+	file := filepath.Join(t.TempDir(), "config.env")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	global.RegisterGlobalFlags(cmd)
+	if err := cmd.PersistentFlags().Set("cli-config-file", file); err != nil {
+		t.Fatal(err)
+	}
+	if err := global.PersistentPreRunE(cmd); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContextsExecute(t *testing.T) {
+	ctx := context.Background()
+	setupConfig(t)
+
+	c := &Create{logger: log.NewTestLogger()}
+	if err := c.ParseArgs([]string{"staging"}); err != nil {
+		t.Fatal(err)
+	}
+	c.flags.URL = "https://api.staging.meroxa.io"
+	c.flags.Use = true
+	if err := c.Execute(ctx); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	logger := log.NewTestLogger()
+	l := &List{logger: logger}
+	if err := l.Execute(ctx); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	var contexts []global.Context
+	if err := json.Unmarshal([]byte(logger.JSONOutput()), &contexts); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if len(contexts) != 2 || contexts[1].Name != "staging" || contexts[1].APIURL != "https://api.staging.meroxa.io" {
+		t.Fatalf("unexpected contexts %+v", contexts)
+	}
+	for _, want := range []string{"CURRENT", "staging", "https://api.staging.meroxa.io", "not logged in"} {
+		if !strings.Contains(logger.LeveledOutput(), want) {
+			t.Fatalf("expected %q in output:\n%s", want, logger.LeveledOutput())
+		}
+	}
+
+	u := &Use{logger: log.NewTestLogger()}
+	if err := u.ParseArgs([]string{"production"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Execute(ctx); err == nil {
+		t.Fatal("expected an error when using a context which doesn't exist")
+	}
+
+	r := &Remove{logger: log.NewTestLogger()}
+	if err := r.ParseArgs([]string{"staging"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Execute(ctx); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	contexts, err := global.ListContexts()
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if len(contexts) != 1 {
+		t.Fatalf("expected only the default context, got %+v", contexts)
+	}
+}
+
+func TestContextsArgs(t *testing.T) {
+	for _, cmd := range []interface{ ParseArgs([]string) error }{&Create{}, &Use{}, &Remove{}} {
+		if err := cmd.ParseArgs(nil); err == nil || err.Error() != "requires context name" {
+			t.Fatalf("expected \"requires context name\" got %v", err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contexts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/log"
+)
+
+var (
+	_ builder.CommandWithDocs    = (*Create)(nil)
+	_ builder.CommandWithArgs    = (*Create)(nil)
+	_ builder.CommandWithFlags   = (*Create)(nil)
+	_ builder.CommandWithLogger  = (*Create)(nil)
+	_ builder.CommandWithExecute = (*Create)(nil)
+)
+
+type Create struct {
+	logger log.Logger
+
+	args struct {
+		Name string
+	}
+	flags struct {
+		URL         string `long:"url" usage:"Meroxa API URL of the context (default is https://api.meroxa.io)"`
+		Environment string `long:"env" usage:"default environment (name or UUID) of the context"`
+		Account     string `long:"account" usage:"account UUID of the context (default is your first account)"`
+		Use         bool   `long:"use" usage:"switch to the context once created"`
+	}
+}
+
+func (c *Create) Usage() string {
+	return "create NAME"
+}
+
+func (c *Create) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Create a context",
+		Long: `Create a context with its own settings. Credentials are added to it by logging in while using it,
+e.g. with ` + "`meroxa login --context NAME`" + `.`,
+		Example: `meroxa contexts create staging --url https://api.staging.meroxa.io --env my-env
+meroxa contexts create production --use`,
+	}
+}
+
+func (c *Create) Flags() []builder.Flag {
+	return builder.BuildFlags(&c.flags)
+}
+
+func (c *Create) Logger(logger log.Logger) {
+	c.logger = logger
+}
+
+func (c *Create) ParseArgs(args []string) error {
+	if len(args) < 1 {
+		return errors.New("requires context name")
+	}
+
+	c.args.Name = args[0]
+	return nil
+}
+
+func (c *Create) Execute(ctx context.Context) error {
+	err := global.CreateContext(c.args.Name, map[string]string{
+		global.APIURLEnv:             c.flags.URL,
+		global.DefaultEnvironmentEnv: c.flags.Environment,
+		global.DefaultAccountEnv:     c.flags.Account,
+	})
+	if err != nil {
+		return err
+	}
+	c.logger.Infof(ctx, "Context %q successfully created!", c.args.Name)
+
+	if c.flags.Use {
+		if err = global.UseContext(c.args.Name); err != nil {
+			return err
+		}
+		c.logger.Infof(ctx, "Switched to context %q.", c.args.Name)
+	}
+
+	c.logger.Infof(ctx, "Run `meroxa login --context %s` to log in with this context.", c.args.Name)
+	return nil
+}
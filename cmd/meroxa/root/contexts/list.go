@@ -0,0 +1,115 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contexts
+
+import (
+	"context"
+
+	"github.com/alexeyco/simpletable"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/log"
+)
+
+var (
+	_ builder.CommandWithDocs      = (*List)(nil)
+	_ builder.CommandWithLogger    = (*List)(nil)
+	_ builder.CommandWithExecute   = (*List)(nil)
+	_ builder.CommandWithAliases   = (*List)(nil)
+	_ builder.CommandWithNoHeaders = (*List)(nil)
+)
+
+type List struct {
+	logger      log.Logger
+	hideHeaders bool
+}
+
+func (l *List) Usage() string {
+	return "list"
+}
+
+func (l *List) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "List contexts, the current one is marked with *",
+	}
+}
+
+func (l *List) Aliases() []string {
+	return []string{"ls"}
+}
+
+func (l *List) Logger(logger log.Logger) {
+	l.logger = logger
+}
+
+func (l *List) HideHeaders(hide bool) {
+	l.hideHeaders = hide
+}
+
+func (l *List) Execute(ctx context.Context) error {
+	contexts, err := global.ListContexts()
+	if err != nil {
+		return err
+	}
+
+	l.logger.JSON(ctx, contexts)
+	l.logger.Info(ctx, contextsTable(contexts, l.hideHeaders))
+	return nil
+}
+
+func contextsTable(contexts []global.Context, hideHeaders bool) string {
+	table := simpletable.New()
+
+	if !hideHeaders {
+		table.Header = &simpletable.Header{
+			Cells: []*simpletable.Cell{
+				{Align: simpletable.AlignCenter, Text: "CURRENT"},
+				{Align: simpletable.AlignCenter, Text: "NAME"},
+				{Align: simpletable.AlignCenter, Text: "API URL"},
+				{Align: simpletable.AlignCenter, Text: "ENVIRONMENT"},
+				{Align: simpletable.AlignCenter, Text: "ACCOUNT"},
+				{Align: simpletable.AlignCenter, Text: "USER"},
+			},
+		}
+	}
+
+	for _, c := range contexts {
+		current := ""
+		if c.Current {
+			current = "*"
+		}
+		user := c.Actor
+		switch {
+		case !c.LoggedIn:
+			user = "not logged in"
+		case user == "":
+			user = "logged in"
+		}
+
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: current},
+			{Text: c.Name},
+			{Text: c.APIURL},
+			{Text: c.Environment},
+			{Text: c.AccountUUID},
+			{Text: user},
+		})
+	}
+	table.SetStyle(simpletable.StyleCompact)
+	return table.String()
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contexts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/log"
+)
+
+var (
+	_ builder.CommandWithDocs    = (*Use)(nil)
+	_ builder.CommandWithArgs    = (*Use)(nil)
+	_ builder.CommandWithLogger  = (*Use)(nil)
+	_ builder.CommandWithExecute = (*Use)(nil)
+)
+
+type Use struct {
+	logger log.Logger
+
+	args struct {
+		Name string
+	}
+}
+
+func (u *Use) Usage() string {
+	return "use NAME"
+}
+
+func (u *Use) Docs() builder.Docs {
+	return builder.Docs{
+		Short:   "Switch to a context, used by the following commands",
+		Example: "meroxa contexts use staging\nmeroxa contexts use default",
+	}
+}
+
+func (u *Use) Logger(logger log.Logger) {
+	u.logger = logger
+}
+
+func (u *Use) ParseArgs(args []string) error {
+	if len(args) < 1 {
+		return errors.New("requires context name")
+	}
+
+	u.args.Name = args[0]
+	return nil
+}
+
+func (u *Use) Execute(ctx context.Context) error {
+	if err := global.UseContext(u.args.Name); err != nil {
+		return err
+	}
+
+	u.logger.Infof(ctx, "Switched to context %q.", u.args.Name)
+	return nil
+}
@@ -20,8 +20,6 @@ import (
 	"context"
 	"errors"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
@@ -33,6 +31,7 @@ var (
 	_ builder.CommandWithClient  = (*Describe)(nil)
 	_ builder.CommandWithLogger  = (*Describe)(nil)
 	_ builder.CommandWithExecute = (*Describe)(nil)
+	_ builder.CommandWithWatch   = (*Describe)(nil)
 )
 
 type describeEnvironmentClient interface {
@@ -55,6 +54,9 @@ func (d *Describe) Usage() string {
 func (d *Describe) Docs() builder.Docs {
 	return builder.Docs{
 		Short: "Describe environment",
+		Example: `meroxa environments describe my-env
+meroxa environments describe my-env --watch
+meroxa environments describe my-env --watch=10s --until-state ready,provisioning_error`,
 	}
 }
 
@@ -64,8 +66,7 @@ func (d *Describe) Execute(ctx context.Context) error {
 		return err
 	}
 
-	d.logger.Info(ctx, display.EnvironmentTable(environment))
-	d.logger.JSON(ctx, environment)
+	d.logger.Output(ctx, environment)
 
 	return nil
 }
@@ -86,3 +87,7 @@ func (d *Describe) ParseArgs(args []string) error {
 	d.args.NameOrUUID = args[0]
 	return nil
 }
+
+func (d *Describe) Watchable() bool {
+	return true
+}
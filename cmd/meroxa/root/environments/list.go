@@ -19,20 +19,21 @@ package environments
 import (
 	"context"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
+	"github.com/meroxa/cli/utils/display"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
 )
 
 var (
-	_ builder.CommandWithDocs      = (*List)(nil)
-	_ builder.CommandWithClient    = (*List)(nil)
-	_ builder.CommandWithLogger    = (*List)(nil)
-	_ builder.CommandWithExecute   = (*List)(nil)
-	_ builder.CommandWithAliases   = (*List)(nil)
-	_ builder.CommandWithNoHeaders = (*List)(nil)
+	_ builder.CommandWithDocs        = (*List)(nil)
+	_ builder.CommandWithClient      = (*List)(nil)
+	_ builder.CommandWithLogger      = (*List)(nil)
+	_ builder.CommandWithExecute     = (*List)(nil)
+	_ builder.CommandWithAliases     = (*List)(nil)
+	_ builder.CommandWithNoHeaders   = (*List)(nil)
+	_ builder.CommandWithListOptions = (*List)(nil)
+	_ builder.CommandWithWatch       = (*List)(nil)
 )
 
 type listEnvironmentsClient interface {
@@ -43,6 +44,7 @@ type List struct {
 	client      listEnvironmentsClient
 	logger      log.Logger
 	hideHeaders bool
+	listOptions display.ListOptions
 }
 
 func (l *List) Usage() string {
@@ -66,8 +68,12 @@ func (l *List) Execute(ctx context.Context) error {
 		return err
 	}
 
-	l.logger.JSON(ctx, environments)
-	l.logger.Info(ctx, display.EnvironmentsTable(environments, l.hideHeaders))
+	out, err := display.List(environments, l.listOptions)
+	if err != nil {
+		return err
+	}
+
+	l.logger.Output(ctx, out)
 
 	return nil
 }
@@ -83,3 +89,11 @@ func (l *List) Client(client meroxa.Client) {
 func (l *List) HideHeaders(hide bool) {
 	l.hideHeaders = hide
 }
+
+func (l *List) ListOptions(o display.ListOptions) {
+	l.listOptions = o
+}
+
+func (l *List) Watchable() bool {
+	return true
+}
@@ -0,0 +1,228 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/manifest"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var (
+	_ builder.CommandWithDocs    = (*Export)(nil)
+	_ builder.CommandWithFlags   = (*Export)(nil)
+	_ builder.CommandWithClient  = (*Export)(nil)
+	_ builder.CommandWithLogger  = (*Export)(nil)
+	_ builder.CommandWithExecute = (*Export)(nil)
+)
+
+type exportClient interface {
+	ListEnvironments(ctx context.Context) ([]*meroxa.Environment, error)
+	ListResources(ctx context.Context) ([]*meroxa.Resource, error)
+	ListPipelines(ctx context.Context) ([]*meroxa.Pipeline, error)
+	ListPipelineConnectors(ctx context.Context, pipelineNameOrID string) ([]*meroxa.Connector, error)
+	ListFunctions(ctx context.Context) ([]*meroxa.Function, error)
+	ListApplications(ctx context.Context) ([]*meroxa.Application, error)
+}
+
+type Export struct {
+	client exportClient
+	logger log.Logger
+
+	flags struct {
+		Dir    string `long:"dir" usage:"write one manifest per entity into this directory instead of printing a single manifest"`
+		Format string `long:"format" usage:"manifest format, either yaml or json (default yaml)"`
+	}
+}
+
+func (e *Export) Usage() string {
+	return "export"
+}
+
+func (e *Export) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Export the entities of your account as manifests",
+		Long: `Use the export command to snapshot the environments, resources, pipelines, connectors, functions and
+applications of your Meroxa account as manifests that can be given to "meroxa apply".
+
+Secrets such as passwords, tokens and private keys are never written out. They are replaced by ${NAME}
+placeholders which "meroxa apply" fills in from the environment variable NAME, so the same manifests can
+be used to back up an account or to clone it into another one.`,
+		Example: `meroxa export > meroxa.yaml
+meroxa export --format json > meroxa.json
+meroxa export --dir ./meroxa`,
+	}
+}
+
+func (e *Export) Flags() []builder.Flag {
+	return builder.BuildFlags(&e.flags)
+}
+
+func (e *Export) Client(client meroxa.Client) {
+	e.client = client
+}
+
+func (e *Export) Logger(logger log.Logger) {
+	e.logger = logger
+}
+
+func (e *Export) Execute(ctx context.Context) error {
+	var asJSON bool
+	switch e.flags.Format {
+	case "", "yaml":
+	case "json":
+		asJSON = true
+	default:
+		return fmt.Errorf("unsupported format %q, use either yaml or json", e.flags.Format)
+	}
+
+	m, err := e.export(ctx)
+	if err != nil {
+		return err
+	}
+
+	if e.flags.Dir == "" {
+		out, err := m.Marshal(asJSON)
+		if err != nil {
+			return err
+		}
+		e.logger.Info(ctx, string(out))
+		e.logger.JSON(ctx, m)
+		return nil
+	}
+
+	n, err := writeDir(e.flags.Dir, m, asJSON)
+	if err != nil {
+		return err
+	}
+	e.logger.Infof(ctx, "%d manifest(s) successfully exported to %q!", n, e.flags.Dir)
+	e.logger.JSON(ctx, m)
+	return nil
+}
+
+func (e *Export) export(ctx context.Context) (*manifest.Manifest, error) {
+	m := &manifest.Manifest{}
+
+	envs, err := e.client.ListEnvironments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, env := range envs {
+		m.Environments = append(m.Environments, exportEnvironment(env))
+	}
+
+	resources, err := e.client.ListResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resources {
+		m.Resources = append(m.Resources, exportResource(r))
+	}
+
+	pipelines, err := e.client.ListPipelines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pipelines {
+		m.Pipelines = append(m.Pipelines, exportPipeline(p))
+
+		connectors, err := e.client.ListPipelineConnectors(ctx, p.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range connectors {
+			m.Connectors = append(m.Connectors, exportConnector(c))
+		}
+	}
+
+	funcs, err := e.client.ListFunctions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range funcs {
+		m.Functions = append(m.Functions, exportFunction(f))
+	}
+
+	apps, err := e.client.ListApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range apps {
+		m.Applications = append(m.Applications, exportApplication(app))
+	}
+
+	return m, nil
+}
+
+// writeDir writes every entity of m to DIR/<kind>s/<name>.<ext> and returns the number of files written.
+func writeDir(dir string, m *manifest.Manifest, asJSON bool) (int, error) {
+	ext := ".yaml"
+	if asJSON {
+		ext = ".json"
+	}
+
+	type entityManifest struct {
+		kind, name string
+		m          *manifest.Manifest
+	}
+	var parts []entityManifest
+	add := func(kind, name string, part *manifest.Manifest) {
+		parts = append(parts, entityManifest{kind: kind, name: name, m: part})
+	}
+	for i := range m.Environments {
+		add(manifest.KindEnvironment, m.Environments[i].Name, &manifest.Manifest{Environments: m.Environments[i : i+1]})
+	}
+	for i := range m.Resources {
+		add(manifest.KindResource, m.Resources[i].Name, &manifest.Manifest{Resources: m.Resources[i : i+1]})
+	}
+	for i := range m.Pipelines {
+		add(manifest.KindPipeline, m.Pipelines[i].Name, &manifest.Manifest{Pipelines: m.Pipelines[i : i+1]})
+	}
+	for i := range m.Connectors {
+		add(manifest.KindConnector, m.Connectors[i].Name, &manifest.Manifest{Connectors: m.Connectors[i : i+1]})
+	}
+	for i := range m.Functions {
+		add(manifest.KindFunction, m.Functions[i].Name, &manifest.Manifest{Functions: m.Functions[i : i+1]})
+	}
+	for i := range m.Applications {
+		add(manifest.KindApplication, m.Applications[i].Name, &manifest.Manifest{Applications: m.Applications[i : i+1]})
+	}
+
+	for _, p := range parts {
+		out, err := p.m.Marshal(asJSON)
+		if err != nil {
+			return 0, err
+		}
+
+		kindDir := filepath.Join(dir, p.kind+"s")
+		if err = os.MkdirAll(kindDir, 0o755); err != nil {
+			return 0, err
+		}
+		path := filepath.Join(kindDir, filepath.Base(p.name)+ext)
+		if err = os.WriteFile(path, out, 0o644); err != nil { //nolint:gosec
+			return 0, fmt.Errorf("could not write manifest %q: %w", path, err)
+		}
+	}
+
+	return len(parts), nil
+}
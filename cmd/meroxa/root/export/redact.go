@@ -0,0 +1,258 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/meroxa/cli/cmd/meroxa/manifest"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+// platformPrefix marks metadata the platform manages on its own, which must not be sent back when creating entities.
+const platformPrefix = "mx:"
+
+// secretKeys are the substrings identifying configuration keys whose values must not be exported.
+var secretKeys = []string{"password", "secret", "token", "private_key", "access_key", "api_key", "credentials", "sslkey"}
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// placeholder returns the ${NAME} reference used in place of a secret, e.g. ${RESOURCE_PG_PASSWORD}.
+func placeholder(kind, name, field string) string {
+	v := strings.ToUpper(strings.Join([]string{kind, name, field}, "_"))
+	return manifest.Placeholder(strings.Trim(nonAlphanumeric.ReplaceAllString(v, "_"), "_"))
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactConfig(kind, name string, config map[string]interface{}) map[string]interface{} {
+	if len(config) == 0 {
+		return nil
+	}
+	return redactMap(kind, name, "", config, false)
+}
+
+// redactMap redacts the secrets of nested objects too, naming their placeholders after the path of the key,
+// e.g. ${CONNECTOR_PG_SOURCE_AUTH_PASSWORD}. Every value under a secret key is redacted.
+func redactMap(kind, name, prefix string, m map[string]interface{}, secret bool) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		field := k
+		if prefix != "" {
+			field = prefix + "_" + k
+		}
+		out[k] = redactValue(kind, name, field, v, secret || isSecretKey(k))
+	}
+	return out
+}
+
+func redactValue(kind, name, field string, v interface{}, secret bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return redactMap(kind, name, field, v, secret)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = redactValue(kind, name, fmt.Sprintf("%s_%d", field, i), e, secret)
+		}
+		return out
+	case string:
+		if !secret && strings.Contains(v, "://") {
+			return redactURL(kind, name, field, v)
+		}
+	}
+	if secret {
+		return placeholder(kind, name, field)
+	}
+	return v
+}
+
+// redactURL replaces the password and the secret query parameters in the URL of field, keeping the rest of it
+// readable. Placeholders are named after field, e.g. ${RESOURCE_PG_URL_PASSWORD}. A URL that can't be parsed is
+// replaced as a whole since it may still hold a password.
+func redactURL(kind, name, field, raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return placeholder(kind, name, field)
+	}
+	query, redacted := redactQuery(kind, name, field, u.RawQuery)
+	u.RawQuery = query
+
+	if _, ok := u.User.Password(); !ok {
+		if !redacted {
+			return raw
+		}
+		return u.String()
+	}
+	user := u.User.Username()
+	u.User = url.User(user)
+	password := placeholder(kind, name, field+"_password")
+	return strings.Replace(u.String(), u.User.String()+"@", u.User.String()+":"+password+"@", 1)
+}
+
+// redactQuery replaces the values of query parameters named like secrets, e.g. sslpassword or sslkey.
+// Placeholders are left unescaped so they are expanded when the manifest is read.
+func redactQuery(kind, name, field, rawQuery string) (string, bool) {
+	if rawQuery == "" {
+		return rawQuery, false
+	}
+	params := strings.Split(rawQuery, "&")
+	redacted := false
+	for i, p := range params {
+		k, _, _ := strings.Cut(p, "=")
+		key, err := url.QueryUnescape(k)
+		if err != nil || !isSecretKey(key) {
+			continue
+		}
+		params[i] = k + "=" + placeholder(kind, name, field+"_"+key)
+		redacted = true
+	}
+	return strings.Join(params, "&"), redacted
+}
+
+func redactSecret(kind, name, field, v string) string {
+	if v == "" {
+		return ""
+	}
+	return placeholder(kind, name, field)
+}
+
+func withoutPlatformMetadata(metadata map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		if !strings.HasPrefix(k, platformPrefix) {
+			out[k] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// environmentRef keeps only the name so manifests can be applied to another account.
+func environmentRef(env *meroxa.EntityIdentifier) *meroxa.EntityIdentifier {
+	if env == nil || env.Name == "" || env.Name == string(meroxa.EnvironmentTypeCommon) {
+		return nil
+	}
+	return &meroxa.EntityIdentifier{Name: env.Name}
+}
+
+func exportEnvironment(env *meroxa.Environment) meroxa.CreateEnvironmentInput {
+	return meroxa.CreateEnvironmentInput{
+		Name:          env.Name,
+		Type:          env.Type,
+		Provider:      env.Provider,
+		Region:        env.Region,
+		Configuration: redactConfig(manifest.KindEnvironment, env.Name, env.Configuration),
+	}
+}
+
+func exportResource(r *meroxa.Resource) meroxa.CreateResourceInput {
+	in := meroxa.CreateResourceInput{
+		Name:        r.Name,
+		Type:        r.Type,
+		URL:         redactURL(manifest.KindResource, r.Name, "url", r.URL),
+		Metadata:    withoutPlatformMetadata(r.Metadata),
+		Environment: environmentRef(r.Environment),
+	}
+
+	if c := r.Credentials; c != nil {
+		in.Credentials = &meroxa.Credentials{
+			Username:      c.Username,
+			Password:      redactSecret(manifest.KindResource, r.Name, "password", c.Password),
+			CACert:        c.CACert,
+			ClientCert:    c.ClientCert,
+			ClientCertKey: redactSecret(manifest.KindResource, r.Name, "client_cert_key", c.ClientCertKey),
+			UseSSL:        c.UseSSL,
+			Token:         redactSecret(manifest.KindResource, r.Name, "token", c.Token),
+		}
+	}
+
+	// The API only returns the public key of a tunnel, the private one has to be provided again.
+	if r.SSHTunnel != nil {
+		in.SSHTunnel = &meroxa.ResourceSSHTunnelInput{
+			Address:    r.SSHTunnel.Address,
+			PrivateKey: placeholder(manifest.KindResource, r.Name, "ssh_private_key"),
+		}
+	}
+
+	return in
+}
+
+func exportPipeline(p *meroxa.Pipeline) meroxa.CreatePipelineInput {
+	return meroxa.CreatePipelineInput{
+		Name:        p.Name,
+		Metadata:    withoutPlatformMetadata(p.Metadata),
+		Environment: environmentRef(p.Environment),
+	}
+}
+
+func exportConnector(c *meroxa.Connector) meroxa.CreateConnectorInput {
+	// Metadata is left out since it's set by the API from the connector type.
+	return meroxa.CreateConnectorInput{
+		Name:          c.Name,
+		ResourceName:  c.ResourceName,
+		PipelineName:  c.PipelineName,
+		Type:          c.Type,
+		Configuration: redactConfig(manifest.KindConnector, c.Name, c.Configuration),
+	}
+}
+
+func exportFunction(f *meroxa.Function) meroxa.CreateFunctionInput {
+	in := meroxa.CreateFunctionInput{
+		Name:         f.Name,
+		InputStream:  f.InputStream,
+		OutputStream: f.OutputStream,
+		Pipeline:     meroxa.PipelineIdentifier{Name: f.Pipeline.Name},
+		Image:        f.Image,
+		Command:      f.Command,
+		Args:         f.Args,
+	}
+
+	if len(f.EnvVars) > 0 {
+		in.EnvVars = make(map[string]string, len(f.EnvVars))
+		for k, v := range f.EnvVars {
+			if isSecretKey(k) {
+				v = placeholder(manifest.KindFunction, f.Name, k)
+			}
+			in.EnvVars[k] = v
+		}
+	}
+
+	return in
+}
+
+func exportApplication(app *meroxa.Application) meroxa.CreateApplicationInput {
+	return meroxa.CreateApplicationInput{
+		Name:        app.Name,
+		Language:    app.Language,
+		Pipeline:    meroxa.EntityIdentifier{Name: app.Pipeline.Name},
+		Environment: environmentRef(app.Environment),
+	}
+}
@@ -22,7 +22,6 @@ import (
 
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
-	"github.com/meroxa/cli/utils/display"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
 )
 
@@ -63,8 +62,7 @@ func (d *Describe) Execute(ctx context.Context) error {
 		return err
 	}
 
-	d.logger.JSON(ctx, flinkJob)
-	d.logger.Info(ctx, display.FlinkJobTable(flinkJob))
+	d.logger.Output(ctx, flinkJob)
 
 	return nil
 }
@@ -26,11 +26,13 @@ import (
 )
 
 var (
-	_ builder.CommandWithDocs    = (*List)(nil)
-	_ builder.CommandWithClient  = (*List)(nil)
-	_ builder.CommandWithLogger  = (*List)(nil)
-	_ builder.CommandWithExecute = (*List)(nil)
-	_ builder.CommandWithAliases = (*List)(nil)
+	_ builder.CommandWithDocs        = (*List)(nil)
+	_ builder.CommandWithClient      = (*List)(nil)
+	_ builder.CommandWithLogger      = (*List)(nil)
+	_ builder.CommandWithExecute     = (*List)(nil)
+	_ builder.CommandWithAliases     = (*List)(nil)
+	_ builder.CommandWithListOptions = (*List)(nil)
+	_ builder.CommandWithWatch       = (*List)(nil)
 )
 
 type listJobsClient interface {
@@ -40,6 +42,8 @@ type listJobsClient interface {
 type List struct {
 	client listJobsClient
 	logger log.Logger
+
+	listOptions display.ListOptions
 }
 
 func (l *List) Usage() string {
@@ -62,8 +66,12 @@ func (l *List) Execute(ctx context.Context) error {
 		return err
 	}
 
-	l.logger.JSON(ctx, flinkJobs)
-	l.logger.Info(ctx, display.FlinkJobsTable(flinkJobs))
+	out, err := display.List(flinkJobs, l.listOptions)
+	if err != nil {
+		return err
+	}
+
+	l.logger.Output(ctx, out)
 	output := "\n ✨ To view your Flink Jobs, visit https://dashboard.meroxa.io/apps"
 	l.logger.Info(ctx, output)
 
@@ -77,3 +85,11 @@ func (l *List) Logger(logger log.Logger) {
 func (l *List) Client(client meroxa.Client) {
 	l.client = client
 }
+
+func (l *List) ListOptions(o display.ListOptions) {
+	l.listOptions = o
+}
+
+func (l *List) Watchable() bool {
+	return true
+}
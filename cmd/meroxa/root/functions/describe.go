@@ -4,8 +4,6 @@ import (
 	"context"
 	"errors"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
@@ -48,8 +46,7 @@ func (d *Describe) Execute(ctx context.Context) error {
 		return err
 	}
 
-	d.logger.Info(ctx, display.FunctionTable(fun))
-	d.logger.JSON(ctx, fun)
+	d.logger.Output(ctx, fun)
 
 	return nil
 }
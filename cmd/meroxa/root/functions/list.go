@@ -3,8 +3,6 @@ package functions
 import (
 	"context"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
@@ -35,8 +33,7 @@ func (l *List) Execute(ctx context.Context) error {
 		return err
 	}
 
-	l.logger.JSON(ctx, funs)
-	l.logger.Info(ctx, display.FunctionsTable(funs, l.hideHeaders))
+	l.logger.Output(ctx, funs)
 
 	return nil
 }
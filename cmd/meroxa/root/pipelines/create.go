@@ -25,6 +25,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/global"
 	"github.com/meroxa/cli/cmd/meroxa/root/environments"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
@@ -61,6 +62,9 @@ type Create struct {
 
 func (c *Create) Execute(ctx context.Context) error {
 	var env string
+	if c.flags.Environment == "" {
+		c.flags.Environment = global.GetDefaultEnvironment()
+	}
 
 	p := &meroxa.CreatePipelineInput{
 		Name: c.args.Name,
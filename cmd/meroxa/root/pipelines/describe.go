@@ -20,8 +20,6 @@ import (
 	"context"
 	"errors"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
@@ -65,9 +63,7 @@ func (d *Describe) Execute(ctx context.Context) error {
 		return err
 	}
 
-	d.logger.Info(ctx, display.PipelineTable(p))
-
-	d.logger.JSON(ctx, p)
+	d.logger.Output(ctx, p)
 
 	return nil
 }
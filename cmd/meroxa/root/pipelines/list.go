@@ -19,21 +19,22 @@ package pipelines
 import (
 	"context"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
+	"github.com/meroxa/cli/utils/display"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
 )
 
 var (
-	_ builder.CommandWithDocs       = (*List)(nil)
-	_ builder.CommandWithClient     = (*List)(nil)
-	_ builder.CommandWithLogger     = (*List)(nil)
-	_ builder.CommandWithExecute    = (*List)(nil)
-	_ builder.CommandWithAliases    = (*List)(nil)
-	_ builder.CommandWithNoHeaders  = (*List)(nil)
-	_ builder.CommandWithDeprecated = (*List)(nil)
+	_ builder.CommandWithDocs        = (*List)(nil)
+	_ builder.CommandWithClient      = (*List)(nil)
+	_ builder.CommandWithLogger      = (*List)(nil)
+	_ builder.CommandWithExecute     = (*List)(nil)
+	_ builder.CommandWithAliases     = (*List)(nil)
+	_ builder.CommandWithNoHeaders   = (*List)(nil)
+	_ builder.CommandWithDeprecated  = (*List)(nil)
+	_ builder.CommandWithListOptions = (*List)(nil)
+	_ builder.CommandWithWatch       = (*List)(nil)
 )
 
 type listPipelinesClient interface {
@@ -44,6 +45,7 @@ type List struct {
 	client      listPipelinesClient
 	logger      log.Logger
 	hideHeaders bool
+	listOptions display.ListOptions
 }
 
 func (l *List) Usage() string {
@@ -67,8 +69,12 @@ func (l *List) Execute(ctx context.Context) error {
 		return err
 	}
 
-	l.logger.JSON(ctx, pipelines)
-	l.logger.Info(ctx, display.PipelinesTable(pipelines, l.hideHeaders))
+	out, err := display.List(pipelines, l.listOptions)
+	if err != nil {
+		return err
+	}
+
+	l.logger.Output(ctx, out)
 
 	return nil
 }
@@ -88,3 +94,11 @@ func (l *List) HideHeaders(hide bool) {
 func (*List) Deprecated() string {
 	return "we encourage you to list your applications via `apps list` instead."
 }
+
+func (l *List) ListOptions(o display.ListOptions) {
+	l.listOptions = o
+}
+
+func (l *List) Watchable() bool {
+	return true
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/global"
 	"github.com/meroxa/cli/cmd/meroxa/root/environments"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
@@ -183,6 +184,9 @@ func (c *Create) ParseArgs(args []string) error {
 
 func (c *Create) Execute(ctx context.Context) error {
 	var env string
+	if c.flags.Environment == "" {
+		c.flags.Environment = global.GetDefaultEnvironment()
+	}
 
 	input := meroxa.CreateResourceInput{
 		Type:     meroxa.ResourceTypeName(c.flags.Type),
@@ -20,8 +20,6 @@ import (
 	"context"
 	"errors"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
@@ -64,7 +62,7 @@ func (d *Describe) Execute(ctx context.Context) error {
 		return err
 	}
 
-	d.logger.Info(ctx, display.ResourceTable(resource))
+	d.logger.Output(ctx, resource)
 
 	if tun := resource.SSHTunnel; tun != nil {
 		nextSteps := "\nPaste the following public key on your host:"
@@ -77,8 +75,6 @@ func (d *Describe) Execute(ctx context.Context) error {
 		d.logger.Info(ctx, tun.PublicKey)
 	}
 
-	d.logger.JSON(ctx, resource)
-
 	return nil
 }
 
@@ -0,0 +1,156 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/cli/utils/display"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var (
+	_ builder.CommandWithDocs      = (*Introspect)(nil)
+	_ builder.CommandWithArgs      = (*Introspect)(nil)
+	_ builder.CommandWithFlags     = (*Introspect)(nil)
+	_ builder.CommandWithClient    = (*Introspect)(nil)
+	_ builder.CommandWithLogger    = (*Introspect)(nil)
+	_ builder.CommandWithExecute   = (*Introspect)(nil)
+	_ builder.CommandWithNoHeaders = (*Introspect)(nil)
+)
+
+type introspectResourceClient interface {
+	IntrospectResource(ctx context.Context, nameOrID string) (*meroxa.ResourceIntrospection, error)
+}
+
+type Introspect struct {
+	client      introspectResourceClient
+	logger      log.Logger
+	hideHeaders bool
+
+	args struct {
+		NameOrID string
+	}
+	flags struct {
+		Collection string `long:"collection" usage:"only show the schema and samples of this collection"`
+	}
+}
+
+func (i *Introspect) Usage() string {
+	return "introspect NAME"
+}
+
+func (i *Introspect) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Show the collections, schemas, capabilities and sample records of a resource",
+		Long: `Show what a resource exposes, as discovered by Meroxa: its collections (e.g. tables or topics),
+the schema of each collection, the capabilities of the resource and a few sample records.
+
+Use it to find which collection to pass to "Records" in a Turbine Data Application.`,
+		Example: `meroxa resources introspect my-postgres
+meroxa resources introspect my-postgres --collection orders
+meroxa resources introspect my-mongo --json`,
+	}
+}
+
+func (i *Introspect) Flags() []builder.Flag {
+	return builder.BuildFlags(&i.flags)
+}
+
+func (i *Introspect) Client(client meroxa.Client) {
+	i.client = client
+}
+
+func (i *Introspect) Logger(logger log.Logger) {
+	i.logger = logger
+}
+
+func (i *Introspect) HideHeaders(hide bool) {
+	i.hideHeaders = hide
+}
+
+func (i *Introspect) ParseArgs(args []string) error {
+	if len(args) < 1 {
+		return errors.New("requires resource name")
+	}
+
+	i.args.NameOrID = args[0]
+	return nil
+}
+
+func (i *Introspect) Execute(ctx context.Context) error {
+	ri, err := i.client.IntrospectResource(ctx, i.args.NameOrID)
+	if err != nil {
+		return err
+	}
+
+	collections := display.IntrospectionCollections(ri)
+	if c := i.flags.Collection; c != "" {
+		if ri, err = filterIntrospection(ri, c, collections); err != nil {
+			return err
+		}
+		collections = []string{c}
+	}
+
+	i.logger.Info(ctx, display.ResourceIntrospectionTable(ri))
+
+	if len(collections) == 0 {
+		i.logger.Infof(ctx, "\nNo collections found for resource %q.", i.args.NameOrID)
+	} else {
+		i.logger.Infof(ctx, "\nCollections:\n%s", display.IntrospectionCollectionsTable(ri, i.hideHeaders))
+	}
+	if len(ri.Capabilities) > 0 {
+		i.logger.Infof(ctx, "\nCapabilities:\n%s", display.IntrospectionCapabilitiesTable(ri, i.hideHeaders))
+	}
+	for _, c := range collections {
+		i.logger.Infof(ctx, "\n%s", display.IntrospectionCollectionTable(ri, c, i.hideHeaders))
+	}
+
+	i.logger.JSON(ctx, ri)
+	return nil
+}
+
+// filterIntrospection narrows down an introspection result to a single collection.
+func filterIntrospection(ri *meroxa.ResourceIntrospection, collection string, collections []string) (*meroxa.ResourceIntrospection, error) {
+	found := false
+	for _, c := range collections {
+		if c == collection {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("collection %q not found, available collections: %s", collection, strings.Join(collections, ", "))
+	}
+
+	filtered := *ri
+	filtered.Collections = []string{collection}
+	filtered.Schemas = nil
+	if s, ok := ri.Schemas[collection]; ok {
+		filtered.Schemas = map[string]string{collection: s}
+	}
+	filtered.Samples = nil
+	if s, ok := ri.Samples[collection]; ok {
+		filtered.Samples = map[string][]string{collection: s}
+	}
+	return &filtered, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+	"github.com/meroxa/meroxa-go/pkg/mock"
+)
+
+func generateIntrospection() meroxa.ResourceIntrospection {
+	return meroxa.ResourceIntrospection{
+		UUID:        "e7b5b2a1-7b6b-4a0e-9a4c-1f3c5d2e8a90",
+		Collections: []string{"orders", "customers"},
+		Schemas: map[string]string{
+			"orders": `{"type":"struct","fields":[{"field":"id","type":"int32"}]}`,
+		},
+		Capabilities: map[string]string{"cdc": "true", "snapshot": "true"},
+		Samples: map[string][]string{
+			"orders": {`{"id":1}`, `{"id":2}`},
+		},
+		ResourceVersion: "13.4",
+	}
+}
+
+func TestIntrospectResourceArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  error
+		name string
+	}{
+		{args: nil, err: errors.New("requires resource name"), name: ""},
+		{args: []string{"resource-name"}, err: nil, name: "resource-name"},
+	}
+
+	for _, tt := range tests {
+		i := &Introspect{}
+		err := i.ParseArgs(tt.args)
+
+		if err != nil && tt.err.Error() != err.Error() {
+			t.Fatalf("expected \"%s\" got \"%s\"", tt.err, err)
+		}
+
+		if tt.name != i.args.NameOrID {
+			t.Fatalf("expected \"%s\" got \"%s\"", tt.name, i.args.NameOrID)
+		}
+	}
+}
+
+func TestIntrospectResourceExecution(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	ri := generateIntrospection()
+	client.
+		EXPECT().
+		IntrospectResource(ctx, "my-postgres").
+		Return(&ri, nil)
+
+	i := &Introspect{
+		client: client,
+		logger: logger,
+	}
+	i.args.NameOrID = "my-postgres"
+
+	err := i.Execute(ctx)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	gotLeveledOutput := logger.LeveledOutput()
+	for _, want := range []string{"COLLECTION", "orders", "customers", "cdc", `"field": "id"`, `{"id":2}`, "No schema available."} {
+		if !strings.Contains(gotLeveledOutput, want) {
+			t.Fatalf("expected %q in output:\n%s", want, gotLeveledOutput)
+		}
+	}
+
+	var gotIntrospection meroxa.ResourceIntrospection
+	err = json.Unmarshal([]byte(logger.JSONOutput()), &gotIntrospection)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	if !reflect.DeepEqual(gotIntrospection, ri) {
+		t.Fatalf("expected \"%v\", got \"%v\"", ri, gotIntrospection)
+	}
+}
+
+func TestIntrospectResourceCollection(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+
+	ri := generateIntrospection()
+	client.
+		EXPECT().
+		IntrospectResource(ctx, "my-postgres").
+		Return(&ri, nil).
+		Times(2)
+
+	logger := log.NewTestLogger()
+	i := &Introspect{client: client, logger: logger}
+	i.args.NameOrID = "my-postgres"
+	i.flags.Collection = "orders"
+
+	if err := i.Execute(ctx); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	var gotIntrospection meroxa.ResourceIntrospection
+	if err := json.Unmarshal([]byte(logger.JSONOutput()), &gotIntrospection); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if want := []string{"orders"}; !reflect.DeepEqual(gotIntrospection.Collections, want) {
+		t.Fatalf("expected collections %v, got %v", want, gotIntrospection.Collections)
+	}
+	if strings.Contains(logger.LeveledOutput(), "customers") {
+		t.Fatalf("expected collection \"customers\" to be filtered out:\n%s", logger.LeveledOutput())
+	}
+
+	i.flags.Collection = "invoices"
+	err := i.Execute(ctx)
+	wantErr := `collection "invoices" not found, available collections: customers, orders`
+	if err == nil || err.Error() != wantErr {
+		t.Fatalf("expected error %q, got %v", wantErr, err)
+	}
+}
@@ -26,13 +26,15 @@ import (
 )
 
 var (
-	_ builder.CommandWithDocs      = (*List)(nil)
-	_ builder.CommandWithClient    = (*List)(nil)
-	_ builder.CommandWithLogger    = (*List)(nil)
-	_ builder.CommandWithExecute   = (*List)(nil)
-	_ builder.CommandWithAliases   = (*List)(nil)
-	_ builder.CommandWithFlags     = (*List)(nil)
-	_ builder.CommandWithNoHeaders = (*List)(nil)
+	_ builder.CommandWithDocs        = (*List)(nil)
+	_ builder.CommandWithClient      = (*List)(nil)
+	_ builder.CommandWithLogger      = (*List)(nil)
+	_ builder.CommandWithExecute     = (*List)(nil)
+	_ builder.CommandWithAliases     = (*List)(nil)
+	_ builder.CommandWithFlags       = (*List)(nil)
+	_ builder.CommandWithNoHeaders   = (*List)(nil)
+	_ builder.CommandWithListOptions = (*List)(nil)
+	_ builder.CommandWithWatch       = (*List)(nil)
 )
 
 type listResourcesClient interface {
@@ -44,6 +46,7 @@ type List struct {
 	client      listResourcesClient
 	logger      log.Logger
 	hideHeaders bool
+	listOptions display.ListOptions
 
 	flags struct {
 		Types bool `long:"types" short:"" usage:"list resource types"`
@@ -81,8 +84,7 @@ func (l *List) Execute(ctx context.Context) error {
 			return err
 		}
 
-		l.logger.JSON(ctx, rTypes)
-		l.logger.Info(ctx, display.ResourceTypesTable(rTypes, l.hideHeaders))
+		l.logger.Output(ctx, rTypes)
 
 		output := "\n ✨ View a complete list of available and upcoming resources in the dashboard: https://dashboard.meroxa.io/resources/new"
 		l.logger.Info(ctx, output)
@@ -94,8 +96,12 @@ func (l *List) Execute(ctx context.Context) error {
 		return err
 	}
 
-	l.logger.JSON(ctx, resources)
-	l.logger.Info(ctx, display.ResourcesTable(resources, l.hideHeaders))
+	out, err := display.List(resources, l.listOptions)
+	if err != nil {
+		return err
+	}
+
+	l.logger.Output(ctx, out)
 	output := "\n ✨ To view your resources, visit https://dashboard.meroxa.io/resources"
 	l.logger.Info(ctx, output)
 
@@ -113,3 +119,11 @@ func (l *List) Client(client meroxa.Client) {
 func (l *List) HideHeaders(hide bool) {
 	l.hideHeaders = hide
 }
+
+func (l *List) ListOptions(o display.ListOptions) {
+	l.listOptions = o
+}
+
+func (l *List) Watchable() bool {
+	return true
+}
@@ -47,6 +47,7 @@ func (*Resources) SubCommands() []*cobra.Command {
 	return []*cobra.Command{
 		builder.BuildCobraCommand(&Create{}),
 		builder.BuildCobraCommand(&Describe{}),
+		builder.BuildCobraCommand(&Introspect{}),
 		builder.BuildCobraCommand(&List{}),
 		builder.BuildCobraCommand(&Remove{}),
 		builder.BuildCobraCommand(&Update{}),
@@ -18,19 +18,24 @@ package root
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 
 	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/clierrors"
 	"github.com/meroxa/cli/cmd/meroxa/global"
 	"github.com/meroxa/cli/cmd/meroxa/root/account"
 	"github.com/meroxa/cli/cmd/meroxa/root/api"
+	"github.com/meroxa/cli/cmd/meroxa/root/apply"
 	"github.com/meroxa/cli/cmd/meroxa/root/apps"
 	"github.com/meroxa/cli/cmd/meroxa/root/auth"
 	"github.com/meroxa/cli/cmd/meroxa/root/billing"
 	"github.com/meroxa/cli/cmd/meroxa/root/builds"
 	"github.com/meroxa/cli/cmd/meroxa/root/config"
 	"github.com/meroxa/cli/cmd/meroxa/root/connectors"
+	"github.com/meroxa/cli/cmd/meroxa/root/contexts"
 	"github.com/meroxa/cli/cmd/meroxa/root/environments"
+	"github.com/meroxa/cli/cmd/meroxa/root/export"
 	"github.com/meroxa/cli/cmd/meroxa/root/flink"
 	"github.com/meroxa/cli/cmd/meroxa/root/functions"
 	"github.com/meroxa/cli/cmd/meroxa/root/login"
@@ -40,17 +45,42 @@ import (
 	"github.com/meroxa/cli/cmd/meroxa/root/resources"
 	"github.com/meroxa/cli/cmd/meroxa/root/transforms"
 	"github.com/meroxa/cli/cmd/meroxa/root/version"
+	"github.com/meroxa/cli/cmd/meroxa/root/wait"
 	"github.com/meroxa/cli/cmd/meroxa/root/whoami"
 
 	"github.com/spf13/cobra"
 )
 
 func Run() {
-	ctx := context.Background()
+	if code := execute(context.Background(), Cmd()); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// execute runs cmd and returns the exit code of the CLI, printing the error the command failed with if any.
+func execute(ctx context.Context, cmd *cobra.Command) int {
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		e := clierrors.From(err)
+		printError(cmd, e)
+		return e.ExitCode()
+	}
+	return 0
+}
+
+// printError prints a JSON error object when the output is meant for scripts (e.g. --json), and the error
+// followed by its hints otherwise.
+func printError(cmd *cobra.Command, e *clierrors.Error) {
+	if global.StructuredOutput() {
+		b, err := json.MarshalIndent(e, "", "\t")
+		if err == nil {
+			cmd.Println(string(b))
+			return
+		}
+	}
 
-	rootCmd := Cmd()
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
-		os.Exit(1)
+	cmd.PrintErrln("Error:", e.Error())
+	for _, h := range e.Hints {
+		cmd.PrintErrln("Hint:", h)
 	}
 }
 
@@ -71,10 +101,14 @@ meroxa resources list --types
 			return global.PersistentPreRunE(cmd)
 		},
 		SilenceUsage:      true,
+		SilenceErrors:     true,
 		DisableAutoGenTag: true,
 		TraverseChildren:  true,
 	}
 
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return clierrors.Wrap(err, clierrors.CategoryValidation, "invalid_flag", "Run the command with --help to see its flags")
+	})
 	global.RegisterGlobalFlags(cmd)
 
 	// Subcommands
@@ -82,6 +116,7 @@ meroxa resources list --types
 
 	cmd.AddCommand(builder.BuildCobraCommand(&account.Account{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&api.API{}))
+	cmd.AddCommand(builder.BuildCobraCommand(&apply.Apply{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&auth.Auth{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&apps.Apps{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&billing.Billing{}))
@@ -89,8 +124,10 @@ meroxa resources list --types
 	cmd.AddCommand(builder.BuildCobraCommand(&config.Config{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&connectors.Connect{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&connectors.Connectors{}))
+	cmd.AddCommand(builder.BuildCobraCommand(&contexts.Contexts{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&functions.Functions{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&environments.Environments{}))
+	cmd.AddCommand(builder.BuildCobraCommand(&export.Export{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&flink.Job{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&login.Login{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&logout.Logout{}))
@@ -99,6 +136,7 @@ meroxa resources list --types
 	cmd.AddCommand(builder.BuildCobraCommand(&resources.Resources{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&transforms.Transforms{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&version.Version{}))
+	cmd.AddCommand(builder.BuildCobraCommand(&wait.Wait{}))
 	cmd.AddCommand(builder.BuildCobraCommand(&whoami.WhoAmI{}))
 
 	return cmd
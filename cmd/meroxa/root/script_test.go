@@ -0,0 +1,587 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cased/cased-go"
+
+	"github.com/meroxa/cli/cmd/meroxa/global"
+	"github.com/meroxa/cli/cmd/meroxa/root/apps"
+	"github.com/meroxa/cli/mock-server/emulator"
+)
+
+var update = flag.Bool("update", false, "update the expected output of scripts in testdata/scripts")
+
+// scenarioFile is the file of a script configuring the emulator, see emulator.Scenario.
+const scenarioFile = "scenario.yaml"
+
+// TestScripts runs the scripts in testdata/scripts end-to-end, against the commands of Cmd and the Meroxa API
+// emulator. Scripts are archives whose comment is the script and whose files are extracted to its working
+// directory, $WORK. Each line of a script is one of the commands below, failing the test if it doesn't hold.
+// Prefixing a command with ! expects it to fail, e.g. a meroxa command exiting with a non-zero code.
+//
+//	meroxa ARGS...         run the CLI, logged in with MEROXA_ACCESS_TOKEN
+//	exec PROGRAM ARGS...   run a program, e.g. git
+//	exit CODE              check the exit code of the last command
+//	stdout|stderr REGEXP   check the output of the last command matches REGEXP
+//	cmp stdout|stderr FILE check the output of the last command is FILE, rewritten with -update
+//	cmpenv stdout|stderr FILE
+//	                       same as cmp, expanding environment variables in FILE first
+//	stdin FILE             use FILE as the input of the next meroxa command
+//	env KEY=VALUE          set an environment variable, unset it when VALUE is empty
+//	cd DIR                 change the working directory
+//	advance DURATION       move the clock of the emulator forward
+//
+// Arguments are separated by spaces. Text in single quotes is kept as is, with two single quotes standing for
+// one, and environment variables like $WORK are expanded elsewhere, e.g.
+//
+//	stderr 'please login or signup by running ''meroxa login'''
+//
+// The emulator moves entities to their final state right away, unless the scenario.yaml file of the script sets a step.
+func TestScripts(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "scripts", "*.txtar"))
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if len(files) == 0 {
+		t.Fatal("expected scripts in testdata/scripts")
+	}
+
+	oldPublishEvent, oldTurbineCLI := global.PublishEvent, apps.TurbineCLI
+	global.PublishEvent = func(cased.AuditEvent) {}
+	apps.TurbineCLI = newFakeTurbineCLI
+	t.Cleanup(func() {
+		global.PublishEvent, apps.TurbineCLI = oldPublishEvent, oldTurbineCLI
+	})
+
+	for _, file := range files {
+		file := file
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".txtar"), func(t *testing.T) {
+			runScript(t, file)
+		})
+	}
+}
+
+// script is the state of a script being run.
+type script struct {
+	t       *testing.T
+	file    string
+	archive *archive
+	work    string
+	clock   *scriptClock
+
+	stdin    string
+	stdout   string
+	stderr   string
+	exitCode int
+	updated  bool
+}
+
+// scriptClock is the clock of the emulator of a script, only moving forward with advance.
+type scriptClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *scriptClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *scriptClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func runScript(t *testing.T, file string) {
+	// Scripts run in their own working directory.
+	file, err := filepath.Abs(file)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	s := &script{
+		t:       t,
+		file:    file,
+		archive: parseArchive(data),
+		work:    t.TempDir(),
+		clock:   &scriptClock{now: time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)},
+	}
+
+	var opts emulator.Options
+	for _, f := range s.archive.files {
+		path := filepath.Join(s.work, f.name)
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+		if err = os.WriteFile(path, f.data, 0o644); err != nil { //nolint:gosec
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+		if f.name == scenarioFile {
+			if opts.Scenario, err = emulator.ParseScenario(f.data); err != nil {
+				t.Fatalf("%s: %v", scenarioFile, err)
+			}
+		}
+	}
+
+	// UUIDs are sequential so that the output of commands doesn't change from one run to the next.
+	var ids atomic.Int64
+	opts.NewUUID = func() string {
+		return fmt.Sprintf("00000000-0000-4000-8000-%012d", ids.Add(1))
+	}
+	opts.Now = s.clock.Now
+	srv, err := emulator.New(opts)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	s.setupEnv(server.URL)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	s.chdir(s.work)
+	defer s.chdir(wd)
+
+	for i, line := range strings.Split(string(s.archive.comment), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err = s.run(line); err != nil {
+			t.Fatalf("%s:%d: %s: %v\n%s", file, i+1, line, err, s.output())
+		}
+	}
+
+	if *update && s.updated {
+		if err = os.WriteFile(file, s.archive.format(), 0o644); err != nil { //nolint:gosec
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+	}
+}
+
+// setupEnv logs the CLI in without going through meroxa login, pointing it to the emulator at apiURL. The
+// configuration is kept in the working directory of the script.
+func (s *script) setupEnv(apiURL string) {
+	for k, v := range map[string]string{
+		"WORK":                               s.work,
+		"HOME":                               s.work,
+		"XDG_CONFIG_HOME":                    filepath.Join(s.work, ".config"),
+		global.APIURLEnv:                     apiURL,
+		"MEROXA_" + global.AccessTokenEnv:    accessToken(),
+		"MEROXA_" + global.PublishMetricsEnv: "false",
+		"MEROXA_" + global.DisableNotificationsUpdate: "true",
+		"GIT_AUTHOR_NAME":     "Meroxa",
+		"GIT_AUTHOR_EMAIL":    "emulator@meroxa.io",
+		"GIT_AUTHOR_DATE":     "2023-01-02T03:04:05Z",
+		"GIT_COMMITTER_NAME":  "Meroxa",
+		"GIT_COMMITTER_EMAIL": "emulator@meroxa.io",
+		"GIT_COMMITTER_DATE":  "2023-01-02T03:04:05Z",
+		"GIT_CONFIG_NOSYSTEM": "1",
+		// app.json is read again by each command rather than once per process.
+		"UNIT_TEST": "1",
+	} {
+		s.t.Setenv(k, v)
+	}
+	for _, k := range []string{
+		global.APIKeyEnv,
+		global.ContextEnv,
+		global.CredentialsStoreEnv,
+		global.DefaultAccountEnv,
+		global.DefaultEnvironmentEnv,
+		global.APIRetriesEnv,
+	} {
+		s.setenv(k, "")
+	}
+}
+
+// accessToken returns a JWT access token that doesn't expire, the emulator doesn't check its signature.
+func accessToken() string {
+	enc := base64.RawURLEncoding
+	return strings.Join([]string{
+		enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)),
+		enc.EncodeToString([]byte(`{"sub":"emulator","exp":4102444800}`)),
+		enc.EncodeToString([]byte("signature")),
+	}, ".")
+}
+
+// setenv sets an environment variable for the rest of the test, unsetting it when value is empty.
+func (s *script) setenv(key, value string) {
+	s.t.Setenv(key, value)
+	if value == "" {
+		os.Unsetenv(key)
+	}
+}
+
+func (s *script) chdir(dir string) {
+	if err := os.Chdir(dir); err != nil {
+		s.t.Fatalf("not expected error, got %q", err.Error())
+	}
+}
+
+func (s *script) output() string {
+	var b strings.Builder
+	if s.stdout != "" {
+		fmt.Fprintf(&b, "[stdout]\n%s\n", s.stdout)
+	}
+	if s.stderr != "" {
+		fmt.Fprintf(&b, "[stderr]\n%s\n", s.stderr)
+	}
+	return b.String()
+}
+
+func (s *script) run(line string) error {
+	negate := false
+	if strings.HasPrefix(line, "!") {
+		negate = true
+		line = strings.TrimSpace(line[1:])
+	}
+
+	args, err := splitArgs(line)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("missing command")
+	}
+
+	name, args := args[0], args[1:]
+	switch name {
+	case "meroxa":
+		s.meroxa(args)
+		return s.checkExitCode(negate)
+	case "exec":
+		if len(args) == 0 {
+			return errors.New("usage: exec PROGRAM ARGS...")
+		}
+		s.exec(args[0], args[1:])
+		return s.checkExitCode(negate)
+	case "exit":
+		return s.exit(args)
+	case "stdout", "stderr":
+		return s.match(name, args, negate)
+	case "cmp", "cmpenv":
+		return s.cmp(args, name == "cmpenv")
+	case "stdin":
+		if len(args) != 1 {
+			return errors.New("usage: stdin FILE")
+		}
+		s.stdin = args[0]
+		return nil
+	case "env":
+		for _, kv := range args {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return errors.New("usage: env KEY=VALUE...")
+			}
+			s.setenv(k, v)
+		}
+		return nil
+	case "cd":
+		if len(args) != 1 {
+			return errors.New("usage: cd DIR")
+		}
+		return os.Chdir(args[0])
+	case "advance":
+		if len(args) != 1 {
+			return errors.New("usage: advance DURATION")
+		}
+		d, err := time.ParseDuration(args[0])
+		if err != nil {
+			return err
+		}
+		s.clock.Advance(d)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// splitArgs splits a line of a script into arguments separated by spaces. Text in single quotes is kept as is,
+// with two single quotes standing for one, and environment variables are expanded elsewhere.
+func splitArgs(line string) ([]string, error) {
+	var (
+		args   []string
+		arg    strings.Builder
+		inArg  bool
+		quoted bool
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\'' && i+1 < len(line) && line[i+1] == '\'':
+			arg.WriteByte('\'')
+			i++
+		case c == '\'':
+			quoted = !quoted
+			inArg = true
+		case quoted:
+			arg.WriteByte(c)
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			// Expand the variables up to the next quote or space.
+			j := strings.IndexAny(line[i:], "' \t")
+			if j < 0 {
+				j = len(line) - i
+			}
+			arg.WriteString(os.ExpandEnv(line[i : i+j]))
+			inArg = true
+			i += j - 1
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// meroxa runs the CLI in-process with args, capturing what it writes to stdout and stderr.
+func (s *script) meroxa(args []string) {
+	stdout, stderr := s.tempFile("stdout"), s.tempFile("stderr")
+	oldStdout, oldStderr, oldStdin := os.Stdout, os.Stderr, os.Stdin
+	os.Stdout, os.Stderr = stdout, stderr
+	if s.stdin != "" {
+		f, err := os.Open(s.stdin)
+		if err != nil {
+			s.t.Fatalf("not expected error, got %q", err.Error())
+		}
+		defer f.Close()
+		os.Stdin = f
+		s.stdin = ""
+	}
+
+	cmd := Cmd()
+	cmd.SetArgs(args)
+	s.exitCode = execute(context.Background(), cmd)
+	os.Stdout, os.Stderr, os.Stdin = oldStdout, oldStderr, oldStdin
+
+	s.stdout, s.stderr = s.readFile(stdout), s.readFile(stderr)
+}
+
+func (s *script) exec(program string, args []string) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(program, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	s.exitCode = 0
+	if err := cmd.Run(); err != nil {
+		s.exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			s.exitCode = exitErr.ExitCode()
+		}
+	}
+	s.stdout, s.stderr = stdout.String(), stderr.String()
+}
+
+func (s *script) tempFile(name string) *os.File {
+	f, err := os.CreateTemp(s.t.TempDir(), name)
+	if err != nil {
+		s.t.Fatalf("not expected error, got %q", err.Error())
+	}
+	return f
+}
+
+func (s *script) readFile(f *os.File) string {
+	defer f.Close()
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		s.t.Fatalf("not expected error, got %q", err.Error())
+	}
+	return string(b)
+}
+
+func (s *script) checkExitCode(negate bool) error {
+	switch {
+	case negate && s.exitCode == 0:
+		return errors.New("expected command to fail")
+	case !negate && s.exitCode != 0:
+		return fmt.Errorf("expected command to succeed, exited with %d", s.exitCode)
+	}
+	return nil
+}
+
+func (s *script) exit(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: exit CODE")
+	}
+	want, err := strconv.Atoi(args[0])
+	if err != nil {
+		return err
+	}
+	if s.exitCode != want {
+		return fmt.Errorf("expected exit code %d, got %d", want, s.exitCode)
+	}
+	return nil
+}
+
+func (s *script) match(name string, args []string, negate bool) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s REGEXP", name)
+	}
+	re, err := regexp.Compile(`(?m)` + args[0])
+	if err != nil {
+		return err
+	}
+
+	out := s.stdout
+	if name == "stderr" {
+		out = s.stderr
+	}
+	switch matched := re.MatchString(out); {
+	case negate && matched:
+		return fmt.Errorf("expected %s not to match %q", name, args[0])
+	case !negate && !matched:
+		return fmt.Errorf("expected %s to match %q", name, args[0])
+	}
+	return nil
+}
+
+func (s *script) cmp(args []string, expandEnv bool) error {
+	if len(args) != 2 || (args[0] != "stdout" && args[0] != "stderr") {
+		return errors.New("usage: cmp stdout|stderr FILE")
+	}
+	got := s.stdout
+	if args[0] == "stderr" {
+		got = s.stderr
+	}
+
+	name := args[1]
+	if *update && !expandEnv {
+		if f := s.archive.file(s.archiveName(name)); f != nil {
+			if string(f.data) != got {
+				f.data = []byte(got)
+				s.updated = true
+			}
+			return nil
+		}
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	want := string(b)
+	if expandEnv {
+		want = os.ExpandEnv(want)
+	}
+	if got != want {
+		return fmt.Errorf("%s differs from %s:\n[want]\n%s\n[got]\n%s", args[0], name, want, got)
+	}
+	return nil
+}
+
+// archiveName returns the name in the archive of the script of the file at path, relative to the working
+// directory.
+func (s *script) archiveName(path string) string {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	name, err := filepath.Rel(s.work, path)
+	if err != nil {
+		return ""
+	}
+	return filepath.ToSlash(name)
+}
+
+// archive is a text archive of files, in the txtar format: a comment followed by files, each starting with a
+// "-- name --" line.
+type archive struct {
+	comment []byte
+	files   []*archiveFile
+}
+
+type archiveFile struct {
+	name string
+	data []byte
+}
+
+var archiveMarker = regexp.MustCompile(`(?m)^-- (\S+) --$\n?`)
+
+func parseArchive(data []byte) *archive {
+	a := &archive{}
+	markers := archiveMarker.FindAllSubmatchIndex(data, -1)
+	if len(markers) == 0 {
+		a.comment = data
+		return a
+	}
+
+	a.comment = data[:markers[0][0]]
+	for i, m := range markers {
+		end := len(data)
+		if i+1 < len(markers) {
+			end = markers[i+1][0]
+		}
+		a.files = append(a.files, &archiveFile{name: string(data[m[2]:m[3]]), data: data[m[1]:end]})
+	}
+	return a
+}
+
+func (a *archive) file(name string) *archiveFile {
+	for _, f := range a.files {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (a *archive) format() []byte {
+	var b bytes.Buffer
+	b.Write(a.comment)
+	for _, f := range a.files {
+		fmt.Fprintf(&b, "-- %s --\n", f.name)
+		b.Write(f.data)
+		if len(f.data) > 0 && f.data[len(f.data)-1] != '\n' {
+			b.WriteByte('\n')
+		}
+	}
+	return b.Bytes()
+}
@@ -19,8 +19,6 @@ package transforms
 import (
 	"context"
 
-	"github.com/meroxa/cli/utils/display"
-
 	"github.com/meroxa/cli/cmd/meroxa/builder"
 	"github.com/meroxa/cli/log"
 	"github.com/meroxa/meroxa-go/pkg/meroxa"
@@ -66,8 +64,7 @@ func (l *List) Execute(ctx context.Context) error {
 		return err
 	}
 
-	l.logger.JSON(ctx, transforms)
-	l.logger.Info(ctx, display.TransformsTable(transforms, l.hideHeaders))
+	l.logger.Output(ctx, transforms)
 
 	return nil
 }
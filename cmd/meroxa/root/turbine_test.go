@@ -0,0 +1,120 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package root
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/meroxa/cli/cmd/meroxa/turbine"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/turbine-core/pkg/ir"
+)
+
+// fakeSpecFile is the file of a fake Turbine app giving the deployment spec it would build.
+const fakeSpecFile = "spec.json"
+
+// fakeTurbineCLI is a Turbine app that doesn't need the toolchain of its language to be deployed. The deployment
+// spec it builds is read from its spec.json file, git is still used to get its commit.
+type fakeTurbineCLI struct {
+	turbine.Git
+	path string
+}
+
+var _ turbine.CLI = (*fakeTurbineCLI)(nil)
+
+func newFakeTurbineCLI(_ log.Logger, _ ir.Lang, path string) (turbine.CLI, error) {
+	return &fakeTurbineCLI{path: path}, nil
+}
+
+func (t *fakeTurbineCLI) spec() (*ir.DeploymentSpec, error) {
+	b, err := os.ReadFile(filepath.Join(t.path, fakeSpecFile))
+	if err != nil {
+		return nil, err
+	}
+	var spec ir.DeploymentSpec
+	if err = json.Unmarshal(b, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+func (t *fakeTurbineCLI) GetDeploymentSpec(_ context.Context, imageName string) (string, error) {
+	spec, err := t.spec()
+	if err != nil {
+		return "", err
+	}
+	for i := range spec.Functions {
+		spec.Functions[i].Image = imageName
+	}
+	b, err := json.Marshal(spec)
+	return string(b), err
+}
+
+func (t *fakeTurbineCLI) GetResources(context.Context) ([]turbine.ApplicationResource, error) {
+	spec, err := t.spec()
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]turbine.ApplicationResource, 0, len(spec.Connectors))
+	for _, c := range spec.Connectors {
+		resources = append(resources, turbine.ApplicationResource{
+			Name:        c.Resource,
+			Source:      c.Type == ir.ConnectorSource,
+			Destination: c.Type == ir.ConnectorDestination,
+			Collection:  c.Collection,
+		})
+	}
+	return resources, nil
+}
+
+func (t *fakeTurbineCLI) NeedsToBuild(context.Context) (bool, error) {
+	spec, err := t.spec()
+	if err != nil {
+		return false, err
+	}
+	return len(spec.Functions) > 0, nil
+}
+
+func (t *fakeTurbineCLI) GetVersion(context.Context) (string, error) {
+	return "fake", nil
+}
+
+func (t *fakeTurbineCLI) CreateDockerfile(context.Context, string) (string, error) {
+	return t.path, nil
+}
+
+func (t *fakeTurbineCLI) CleanupDockerfile(log.Logger, string) {}
+
+func (t *fakeTurbineCLI) StartGrpcServer(context.Context, string) (func(), error) {
+	return func() {}, nil
+}
+
+func (t *fakeTurbineCLI) Init(context.Context, string) error {
+	return errors.New("fake Turbine apps can't be initialized")
+}
+
+func (t *fakeTurbineCLI) Run(context.Context) error {
+	return errors.New("fake Turbine apps can't be run")
+}
+
+func (t *fakeTurbineCLI) Upgrade(bool) error {
+	return errors.New("fake Turbine apps can't be upgraded")
+}
@@ -0,0 +1,288 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/clierrors"
+	"github.com/meroxa/cli/cmd/meroxa/poll"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/cli/utils/display"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+const defaultTimeout = 10 * time.Minute
+
+var (
+	_ builder.CommandWithDocs    = (*Wait)(nil)
+	_ builder.CommandWithArgs    = (*Wait)(nil)
+	_ builder.CommandWithFlags   = (*Wait)(nil)
+	_ builder.CommandWithClient  = (*Wait)(nil)
+	_ builder.CommandWithLogger  = (*Wait)(nil)
+	_ builder.CommandWithExecute = (*Wait)(nil)
+)
+
+type waitClient interface {
+	GetApplication(ctx context.Context, nameOrUUID string) (*meroxa.Application, error)
+	GetBuild(ctx context.Context, uuid string) (*meroxa.Build, error)
+	GetConnectorByNameOrID(ctx context.Context, nameOrID string) (*meroxa.Connector, error)
+	GetEnvironment(ctx context.Context, nameOrUUID string) (*meroxa.Environment, error)
+	GetFlinkJob(ctx context.Context, nameOrUUID string) (*meroxa.FlinkJob, error)
+	GetPipelineByName(ctx context.Context, name string) (*meroxa.Pipeline, error)
+	GetResourceByNameOrID(ctx context.Context, nameOrID string) (*meroxa.Resource, error)
+}
+
+// kind is a kind of entity that can be waited for.
+type kind struct {
+	name    string
+	aliases []string
+	get     func(ctx context.Context, client waitClient, nameOrUUID string) (interface{}, error)
+	// failed reports whether an entity in this state won't reach any other state on its own.
+	failed func(state string) bool
+}
+
+func inStates(states ...string) func(string) bool {
+	return func(state string) bool {
+		for _, s := range states {
+			if strings.EqualFold(state, s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var kinds = []kind{
+	{
+		name:    "app",
+		aliases: []string{"apps", "application", "applications"},
+		get: func(ctx context.Context, client waitClient, nameOrUUID string) (interface{}, error) {
+			return client.GetApplication(ctx, nameOrUUID)
+		},
+		failed: inStates(string(meroxa.ApplicationStateFailed)),
+	},
+	{
+		name:    "build",
+		aliases: []string{"builds"},
+		get: func(ctx context.Context, client waitClient, uuid string) (interface{}, error) {
+			return client.GetBuild(ctx, uuid)
+		},
+		failed: inStates("error"),
+	},
+	{
+		name:    "connector",
+		aliases: []string{"connectors"},
+		get: func(ctx context.Context, client waitClient, nameOrUUID string) (interface{}, error) {
+			return client.GetConnectorByNameOrID(ctx, nameOrUUID)
+		},
+		failed: inStates(
+			string(meroxa.ConnectorStateFailed),
+			string(meroxa.ConnectorStateCrashed),
+			string(meroxa.ConnectorStateDOA),
+		),
+	},
+	{
+		name:    "environment",
+		aliases: []string{"environments", "env"},
+		get: func(ctx context.Context, client waitClient, nameOrUUID string) (interface{}, error) {
+			return client.GetEnvironment(ctx, nameOrUUID)
+		},
+		failed: func(state string) bool {
+			return strings.HasSuffix(state, "_error") || state == string(meroxa.EnvironmentStateDeprovisioned)
+		},
+	},
+	{
+		name:    "flink",
+		aliases: []string{"flink-job", "flink-jobs"},
+		get: func(ctx context.Context, client waitClient, nameOrUUID string) (interface{}, error) {
+			return client.GetFlinkJob(ctx, nameOrUUID)
+		},
+		failed: inStates(string(meroxa.FlinkJobLifecycleStateFailed), string(meroxa.FlinkJobLifecycleStateDoa)),
+	},
+	{
+		name:    "pipeline",
+		aliases: []string{"pipelines"},
+		get: func(ctx context.Context, client waitClient, name string) (interface{}, error) {
+			return client.GetPipelineByName(ctx, name)
+		},
+		failed: inStates(),
+	},
+	{
+		name:    "resource",
+		aliases: []string{"resources"},
+		get: func(ctx context.Context, client waitClient, nameOrUUID string) (interface{}, error) {
+			return client.GetResourceByNameOrID(ctx, nameOrUUID)
+		},
+		failed: inStates(string(meroxa.ResourceStateError)),
+	},
+}
+
+func kindNames() []string {
+	names := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		names = append(names, k.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func findKind(name string) (kind, error) {
+	for _, k := range kinds {
+		if strings.EqualFold(k.name, name) || inStates(k.aliases...)(name) {
+			return k, nil
+		}
+	}
+	return kind{}, fmt.Errorf("unknown kind %q, use one of %s", name, strings.Join(kindNames(), ", "))
+}
+
+type Wait struct {
+	client waitClient
+	logger log.Logger
+
+	args struct {
+		Kind       kind
+		NameOrUUID string
+	}
+
+	flags struct {
+		For     string        `long:"for" usage:"condition to wait for, e.g. state=ready" required:"true"`
+		Timeout time.Duration `long:"timeout" usage:"how long to wait before giving up, e.g. 30s or 10m (default 10m0s)"`
+	}
+
+	// backoff is how long to wait between checks of the state.
+	backoff poll.Backoff
+}
+
+func (w *Wait) Usage() string {
+	return fmt.Sprintf("wait %s NAMEorUUID", strings.Join(kindNames(), "|"))
+}
+
+func (w *Wait) Docs() builder.Docs {
+	return builder.Docs{
+		Short: "Wait for an entity to reach a state",
+		Long: `Wait until an environment, resource, connector, pipeline, app, build or Flink job reaches a state.
+
+The command exits successfully once the state is reached. It fails if the entity ends up in a state
+it won't recover from on its own (e.g. a failed connector), or if the state isn't reached within --timeout.`,
+		Example: `meroxa wait environment my-env --for state=ready --timeout 30m
+meroxa wait resource my-postgres --for state=ready
+meroxa wait build 8e1bb1d7-0d63-4d4a-9a3e-a32f5ba01ef2 --for state=complete`,
+	}
+}
+
+func (w *Wait) Flags() []builder.Flag {
+	return builder.BuildFlags(&w.flags)
+}
+
+func (w *Wait) ParseArgs(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("requires a kind (%s) and a name", strings.Join(kindNames(), ", "))
+	}
+
+	k, err := findKind(args[0])
+	if err != nil {
+		return err
+	}
+	w.args.Kind = k
+	w.args.NameOrUUID = args[1]
+	return nil
+}
+
+// parseCondition parses the value of --for.
+func parseCondition(condition string) (string, error) {
+	key, value, ok := strings.Cut(condition, "=")
+	if !ok || strings.TrimSpace(key) != "state" || strings.TrimSpace(value) == "" {
+		return "", clierrors.Newf(clierrors.CategoryValidation, "invalid_condition",
+			"invalid condition %q, expected state=STATE (e.g. state=ready)", condition)
+	}
+	return strings.TrimSpace(value), nil
+}
+
+func (w *Wait) Execute(ctx context.Context) error {
+	want, err := parseCondition(w.flags.For)
+	if err != nil {
+		return err
+	}
+
+	timeout := w.flags.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	backoff := w.backoff
+	if backoff == (poll.Backoff{}) {
+		backoff = poll.Exponential(time.Second, 15*time.Second)
+	}
+
+	k, name := w.args.Kind, w.args.NameOrUUID
+	cctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		entity interface{}
+		state  string
+	)
+	w.logger.StartSpinner("\t", fmt.Sprintf("Waiting for %s %q to be %s...", k.name, name, want))
+	err = poll.Until(cctx, backoff, func(ctx context.Context) (bool, error) {
+		e, err := k.get(ctx, w.client, name)
+		if err != nil {
+			return false, err
+		}
+
+		entity = e
+		if states, ok := display.States(e); ok && len(states) == 1 {
+			state = states[0]
+		}
+		switch {
+		case strings.EqualFold(state, want):
+			return true, nil
+		case k.failed(state):
+			return false, clierrors.Newf(clierrors.CategoryRemote, "failure_state",
+				"%s %q is %s and won't be %s", k.name, name, state, want)
+		}
+		return false, nil
+	})
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) && cctx.Err() != nil:
+		w.logger.StopSpinnerWithStatus(fmt.Sprintf("%s %q isn't %s yet", k.name, name, want), log.Failed)
+		return clierrors.Newf(clierrors.CategoryTimeout, "wait_timeout",
+			"timed out after %s waiting for %s %q to be %s, its state is %q", timeout, k.name, name, want, state).
+			WithHints("Use --timeout to wait longer")
+	case err != nil:
+		w.logger.StopSpinnerWithStatus(fmt.Sprintf("%s %q isn't %s", k.name, name, want), log.Failed)
+		return err
+	}
+
+	w.logger.StopSpinnerWithStatus(fmt.Sprintf("%s %q is %s", k.name, name, want), log.Successful)
+	w.logger.JSON(ctx, entity)
+	return nil
+}
+
+func (w *Wait) Client(client meroxa.Client) {
+	w.client = client
+}
+
+func (w *Wait) Logger(logger log.Logger) {
+	w.logger = logger
+}
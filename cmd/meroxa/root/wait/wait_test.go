@@ -0,0 +1,213 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/meroxa/cli/cmd/meroxa/builder"
+	"github.com/meroxa/cli/cmd/meroxa/poll"
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/cli/utils"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+	"github.com/meroxa/meroxa-go/pkg/mock"
+)
+
+func TestWaitArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  error
+		kind string
+		name string
+	}{
+		{args: nil, err: errors.New("requires a kind (app, build, connector, environment, flink, pipeline, resource) and a name")},
+		{args: []string{"environment"}, err: errors.New("requires a kind (app, build, connector, environment, flink, pipeline, resource) and a name")},
+		{args: []string{"database", "my-db"}, err: errors.New(`unknown kind "database", use one of app, build, connector, environment, flink, pipeline, resource`)},
+		{args: []string{"environment", "my-env"}, kind: "environment", name: "my-env"},
+		{args: []string{"env", "my-env"}, kind: "environment", name: "my-env"},
+		{args: []string{"apps", "my-app"}, kind: "app", name: "my-app"},
+		{args: []string{"Connector", "my-conn"}, kind: "connector", name: "my-conn"},
+	}
+
+	for _, tt := range tests {
+		w := &Wait{}
+		err := w.ParseArgs(tt.args)
+
+		if tt.err != nil {
+			if err == nil || tt.err.Error() != err.Error() {
+				t.Fatalf("expected \"%s\" got \"%v\"", tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+		if tt.kind != w.args.Kind.name || tt.name != w.args.NameOrUUID {
+			t.Fatalf("expected %s %q got %s %q", tt.kind, tt.name, w.args.Kind.name, w.args.NameOrUUID)
+		}
+	}
+}
+
+func TestWaitFlags(t *testing.T) {
+	expectedFlags := []struct {
+		name     string
+		required bool
+	}{
+		{name: "for", required: true},
+		{name: "timeout", required: false},
+	}
+
+	c := builder.BuildCobraCommand(&Wait{})
+
+	for _, f := range expectedFlags {
+		cf := c.Flags().Lookup(f.name)
+		if cf == nil {
+			t.Fatalf("expected flag \"%s\" to be present", f.name)
+		}
+
+		if f.required && !utils.IsFlagRequired(cf) {
+			t.Fatalf("expected flag \"%s\" to be required", f.name)
+		}
+	}
+}
+
+func newWait(t *testing.T, client waitClient, logger log.Logger, args []string, condition string) *Wait {
+	t.Helper()
+	w := &Wait{
+		client:  client,
+		logger:  logger,
+		backoff: poll.Constant(time.Millisecond),
+	}
+	if err := w.ParseArgs(args); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	w.flags.For = condition
+	return w
+}
+
+func environment(state meroxa.EnvironmentState) *meroxa.Environment {
+	return &meroxa.Environment{Name: "my-env", Status: meroxa.EnvironmentViewStatus{State: state}}
+}
+
+func TestWaitExecution(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	gomock.InOrder(
+		client.EXPECT().GetEnvironment(gomock.Any(), "my-env").Return(environment(meroxa.EnvironmentStateProvisioning), nil),
+		client.EXPECT().GetEnvironment(gomock.Any(), "my-env").Return(environment(meroxa.EnvironmentStateProvisioning), nil),
+		client.EXPECT().GetEnvironment(gomock.Any(), "my-env").Return(environment(meroxa.EnvironmentStateReady), nil),
+	)
+
+	w := newWait(t, client, logger, []string{"environment", "my-env"}, "state=ready")
+	if err := w.Execute(ctx); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+
+	var got meroxa.Environment
+	if err := json.Unmarshal([]byte(logger.JSONOutput()), &got); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if got.Status.State != meroxa.EnvironmentStateReady {
+		t.Fatalf("expected environment to be ready, got %q", got.Status.State)
+	}
+}
+
+func TestWaitExecutionWithFailureState(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	c := utils.GenerateConnector("", "my-conn")
+	c.State = meroxa.ConnectorStateFailed
+	client.EXPECT().GetConnectorByNameOrID(gomock.Any(), "my-conn").Return(&c, nil)
+
+	w := newWait(t, client, logger, []string{"connector", "my-conn"}, "state=running")
+	err := w.Execute(ctx)
+	want := `connector "my-conn" is failed and won't be running`
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+}
+
+func TestWaitExecutionForFailureState(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	c := utils.GenerateConnector("", "my-conn")
+	c.State = meroxa.ConnectorStateFailed
+	client.EXPECT().GetConnectorByNameOrID(gomock.Any(), "my-conn").Return(&c, nil)
+
+	w := newWait(t, client, logger, []string{"connector", "my-conn"}, "state=failed")
+	if err := w.Execute(ctx); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+}
+
+func TestWaitExecutionTimeout(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	client.EXPECT().
+		GetEnvironment(gomock.Any(), "my-env").
+		Return(environment(meroxa.EnvironmentStateProvisioning), nil).
+		MinTimes(1)
+
+	w := newWait(t, client, logger, []string{"environment", "my-env"}, "state=ready")
+	w.flags.Timeout = 20 * time.Millisecond
+
+	err := w.Execute(ctx)
+	want := `timed out after 20ms waiting for environment "my-env" to be ready, its state is "provisioning"`
+	if err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+}
+
+func TestWaitExecutionErrors(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	client := mock.NewMockClient(ctrl)
+	logger := log.NewTestLogger()
+
+	client.EXPECT().GetResourceByNameOrID(gomock.Any(), "my-db").Return(nil, errors.New("not found"))
+
+	w := newWait(t, client, logger, []string{"resource", "my-db"}, "state=ready")
+	if err := w.Execute(ctx); err == nil || err.Error() != "not found" {
+		t.Fatalf("expected error \"not found\", got %v", err)
+	}
+
+	for _, condition := range []string{"ready", "status=ready", "state="} {
+		w := newWait(t, client, logger, []string{"resource", "my-db"}, condition)
+		err := w.Execute(ctx)
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid condition") {
+			t.Fatalf("expected invalid condition error for %q, got %v", condition, err)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package turbine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/meroxa/cli/log"
+	"github.com/meroxa/turbine-core/pkg/app"
+	"github.com/meroxa/turbine-core/pkg/server"
+
+	pb "github.com/meroxa/turbine-core/lib/go/github.com/meroxa/turbine/core"
+)
+
+// SampleFetcher returns sample records of a resource collection, used to record fixtures.
+type SampleFetcher func(ctx context.Context, resource, collection string) ([]string, error)
+
+// RunOptions controls where a local run reads records from and where it writes them to.
+// Fixture and output files are named RESOURCE/COLLECTION.json and contain a JSON array of records.
+type RunOptions struct {
+	// FixturesDir is read instead of the fixtures declared in app.json.
+	FixturesDir string
+	// OutDir receives the records written to each destination.
+	OutDir string
+	// Samples, when set, is used to fetch the records of each collection which are then saved in FixturesDir.
+	Samples SampleFetcher
+	Logger  log.Logger
+}
+
+// SetRunOptions replaces the runner used by Run with one reading and writing records as configured by opts.
+func (t *Core) SetRunOptions(opts RunOptions) {
+	t.runner = newFixtureServer(opts)
+}
+
+type fixtureRecord struct {
+	Key       interface{}     `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	Timestamp string          `json:"timestamp,omitempty"`
+}
+
+type fixtureServer struct {
+	*grpc.Server
+	logger log.Logger
+}
+
+var _ server.Server = (*fixtureServer)(nil)
+
+func newFixtureServer(opts RunOptions) *fixtureServer {
+	s := grpc.NewServer()
+	pb.RegisterTurbineServiceServer(s, newFixtureRunService(opts))
+	return &fixtureServer{Server: s, logger: opts.Logger}
+}
+
+func (s *fixtureServer) Run(ctx context.Context) {
+	s.RunAddr(ctx, server.ListenAddress)
+}
+
+func (s *fixtureServer) RunAddr(ctx context.Context, addr string) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		s.logger.Errorf(ctx, "failed to listen: %v", err)
+		return
+	}
+
+	if err = s.Serve(listener); err != nil {
+		s.logger.Errorf(ctx, "failed to serve: %v", err)
+	}
+}
+
+type fixtureRunService struct {
+	pb.UnimplementedTurbineServiceServer
+
+	opts    RunOptions
+	config  app.Config
+	appPath string
+
+	mu      sync.Mutex
+	written map[string][]fixtureRecord
+}
+
+func newFixtureRunService(opts RunOptions) *fixtureRunService {
+	return &fixtureRunService{
+		opts:    opts,
+		written: map[string][]fixtureRecord{},
+	}
+}
+
+func (s *fixtureRunService) Init(ctx context.Context, req *pb.InitRequest) (*emptypb.Empty, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	config, err := app.ReadConfig(req.AppName, req.ConfigFilePath)
+	if err != nil {
+		return nil, err
+	}
+	s.config = config
+	s.appPath = req.ConfigFilePath
+
+	return new(emptypb.Empty), nil
+}
+
+func (s *fixtureRunService) GetResource(ctx context.Context, req *pb.GetResourceRequest) (*pb.Resource, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &pb.Resource{
+		Name: req.Name,
+	}, nil
+}
+
+func (s *fixtureRunService) ReadCollection(ctx context.Context, req *pb.ReadCollectionRequest) (*pb.Collection, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		records []fixtureRecord
+		err     error
+	)
+	if s.opts.Samples != nil {
+		records, err = s.record(ctx, req.Resource.Name, req.Collection)
+	} else {
+		records, err = s.readFixture(req.Resource.Name, req.Collection)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rr := make([]*pb.Record, 0, len(records))
+	for _, r := range records {
+		rr = append(rr, r.toProto())
+	}
+	return &pb.Collection{
+		Name:    req.Collection,
+		Records: rr,
+	}, nil
+}
+
+func (s *fixtureRunService) WriteCollectionToResource(ctx context.Context, req *pb.WriteCollectionRequest) (*emptypb.Empty, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	records := make([]fixtureRecord, 0, len(req.SourceCollection.Records))
+	for _, r := range req.SourceCollection.Records {
+		records = append(records, newFixtureRecord(r))
+	}
+
+	name := req.Resource.Name
+	if s.opts.OutDir == "" {
+		s.opts.Logger.Infof(ctx, "Destination %s/%s received %d records:", name, req.TargetCollection, len(records))
+		for _, r := range records {
+			s.opts.Logger.Info(ctx, string(r.Value))
+		}
+		return new(emptypb.Empty), nil
+	}
+
+	file := fixturePath(s.opts.OutDir, name, req.TargetCollection)
+
+	// A destination can be written to several times during a run, the output file holds all of its records.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.written[file] = append(s.written[file], records...)
+	if err := writeFixture(file, s.written[file]); err != nil {
+		return nil, err
+	}
+
+	s.opts.Logger.Infof(ctx, "Destination %s/%s received %d records, written to %s", name, req.TargetCollection, len(records), file)
+	return new(emptypb.Empty), nil
+}
+
+func (s *fixtureRunService) AddProcessToCollection(ctx context.Context, req *pb.ProcessCollectionRequest) (*pb.Collection, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return req.Collection, nil
+}
+
+func (s *fixtureRunService) RegisterSecret(ctx context.Context, req *pb.Secret) (*emptypb.Empty, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return new(emptypb.Empty), nil
+}
+
+// readFixture looks for the records of a collection in the fixtures directory, first in RESOURCE/COLLECTION.json
+// and then in RESOURCE.json. Without a fixtures directory, the fixture declared in app.json is used.
+func (s *fixtureRunService) readFixture(resource, collection string) ([]fixtureRecord, error) {
+	if s.opts.FixturesDir == "" {
+		file, ok := s.config.Resources[resource]
+		if !ok {
+			return nil, status.Error(
+				codes.InvalidArgument,
+				fmt.Sprintf(
+					"No fixture file found for resource %s. Ensure that the resource is declared in your app.json.",
+					resource,
+				),
+			)
+		}
+		return readFixtureFile(path.Join(s.appPath, file), collection)
+	}
+
+	candidates := []string{
+		fixturePath(s.opts.FixturesDir, resource, collection),
+		fixturePath(s.opts.FixturesDir, resource, ""),
+	}
+	for _, file := range candidates {
+		if _, err := os.Stat(file); err == nil {
+			return readFixtureFile(file, collection)
+		}
+	}
+	return nil, status.Error(
+		codes.NotFound,
+		fmt.Sprintf("No fixture file found for collection %q of resource %s, expected %s.", collection, resource, candidates[0]),
+	)
+}
+
+// record fetches sample records of a collection and saves them as its fixture.
+func (s *fixtureRunService) record(ctx context.Context, resource, collection string) ([]fixtureRecord, error) {
+	samples, err := s.opts.Samples(ctx, resource, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]fixtureRecord, 0, len(samples))
+	for i, sample := range samples {
+		records = append(records, fixtureRecord{
+			Key:   fmt.Sprint(i + 1),
+			Value: jsonValue([]byte(sample)),
+		})
+	}
+
+	file := fixturePath(s.opts.FixturesDir, resource, collection)
+	if err = writeFixture(file, records); err != nil {
+		return nil, err
+	}
+
+	s.opts.Logger.Infof(ctx, "Recorded %d records of %s/%s to %s", len(records), resource, collection, file)
+	return records, nil
+}
+
+func fixturePath(dir, resource, collection string) string {
+	if collection == "" {
+		return filepath.Join(dir, resource+".json")
+	}
+	return filepath.Join(dir, resource, collection+".json")
+}
+
+// readFixtureFile reads either a JSON array of records, or an object with an array of records per collection
+// as used by the fixtures declared in app.json.
+func readFixtureFile(file, collection string) ([]fixtureRecord, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []fixtureRecord
+	if b = bytes.TrimSpace(b); bytes.HasPrefix(b, []byte("[")) {
+		err = json.Unmarshal(b, &records)
+	} else {
+		var byCollection map[string][]fixtureRecord
+		err = json.Unmarshal(b, &byCollection)
+		records = byCollection[collection]
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture file %s: %w", file, err)
+	}
+	return records, nil
+}
+
+func writeFixture(file string, records []fixtureRecord) error {
+	if records == nil {
+		records = []fixtureRecord{}
+	}
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(b, '\n'), 0o644) //nolint:gosec
+}
+
+func newFixtureRecord(r *pb.Record) fixtureRecord {
+	fr := fixtureRecord{
+		Key:   r.GetKey(),
+		Value: jsonValue(r.GetValue()),
+	}
+	if ts := r.GetTimestamp(); ts != nil {
+		fr.Timestamp = ts.AsTime().UTC().Format(time.RFC3339)
+	}
+	return fr
+}
+
+func (r fixtureRecord) toProto() *pb.Record {
+	ts := timestamppb.New(time.Now())
+	if t, err := time.Parse(time.RFC3339, r.Timestamp); err == nil {
+		ts = timestamppb.New(t)
+	}
+
+	var value bytes.Buffer
+	if err := json.Compact(&value, r.Value); err != nil {
+		value.WriteString("null")
+	}
+	return &pb.Record{
+		Key:       fmt.Sprintf("%v", r.Key),
+		Value:     value.Bytes(),
+		Timestamp: ts,
+	}
+}
+
+// jsonValue keeps b as is when it's valid JSON and stores it as a JSON string otherwise.
+func jsonValue(b []byte) json.RawMessage {
+	if json.Valid(b) {
+		return b
+	}
+	s, _ := json.Marshal(string(b))
+	return s
+}
@@ -0,0 +1,95 @@
+package turbine
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/meroxa/cli/log"
+	pb "github.com/meroxa/turbine-core/lib/go/github.com/meroxa/turbine/core"
+)
+
+func readCollection(ctx context.Context, t *testing.T, s *fixtureRunService, resource, collection string) []string {
+	t.Helper()
+	c, err := s.ReadCollection(ctx, &pb.ReadCollectionRequest{
+		Resource:   &pb.Resource{Name: resource},
+		Collection: collection,
+	})
+	require.NoError(t, err)
+
+	var values []string
+	for _, r := range c.Records {
+		values = append(values, string(r.Value))
+	}
+	return values
+}
+
+func Test_FixtureRunServiceReadCollection(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pg", "orders.json"),
+		[]byte(`[{"key":1,"value":{"id":1},"timestamp":"2022-10-18T12:00:00Z"}]`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mongo.json"),
+		[]byte(`{"users":[{"key":"a","value":{"name":"alice"}}]}`), 0o644))
+
+	s := newFixtureRunService(RunOptions{FixturesDir: dir, Logger: log.NewTestLogger()})
+
+	require.Equal(t, []string{`{"id":1}`}, readCollection(ctx, t, s, "pg", "orders"))
+	require.Equal(t, []string{`{"name":"alice"}`}, readCollection(ctx, t, s, "mongo", "users"))
+
+	_, err := s.ReadCollection(ctx, &pb.ReadCollectionRequest{
+		Resource:   &pb.Resource{Name: "pg"},
+		Collection: "customers",
+	})
+	require.ErrorContains(t, err, filepath.Join(dir, "pg", "customers.json"))
+}
+
+func Test_FixtureRunServiceWriteCollection(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := newFixtureRunService(RunOptions{OutDir: dir, Logger: log.NewTestLogger()})
+
+	for _, v := range []string{`{"id":1}`, "not json"} {
+		_, err := s.WriteCollectionToResource(ctx, &pb.WriteCollectionRequest{
+			Resource:         &pb.Resource{Name: "s3"},
+			SourceCollection: &pb.Collection{Name: "orders", Records: []*pb.Record{{Key: "1", Value: []byte(v)}}},
+			TargetCollection: "archive",
+		})
+		require.NoError(t, err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "s3", "archive.json"))
+	require.NoError(t, err)
+
+	var got []fixtureRecord
+	require.NoError(t, json.Unmarshal(b, &got))
+	require.Len(t, got, 2)
+	require.JSONEq(t, `{"id":1}`, string(got[0].Value))
+	require.JSONEq(t, `"not json"`, string(got[1].Value))
+}
+
+func Test_FixtureRunServiceRecord(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	s := newFixtureRunService(RunOptions{
+		FixturesDir: dir,
+		Logger:      log.NewTestLogger(),
+		Samples: func(_ context.Context, resource, collection string) ([]string, error) {
+			require.Equal(t, "pg", resource)
+			require.Equal(t, "orders", collection)
+			return []string{`{"id":1}`, `{"id":2}`}, nil
+		},
+	})
+	require.Equal(t, []string{`{"id":1}`, `{"id":2}`}, readCollection(ctx, t, s, "pg", "orders"))
+
+	// The recorded fixture is replayed without fetching samples again.
+	s = newFixtureRunService(RunOptions{FixturesDir: dir, Logger: log.NewTestLogger()})
+	require.Equal(t, []string{`{"id":1}`, `{"id":2}`}, readCollection(ctx, t, s, "pg", "orders"))
+}
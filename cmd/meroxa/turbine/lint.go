@@ -0,0 +1,264 @@
+package turbine
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// AppConfigSchemaURL is where the JSON Schema of app.json is published, for editors to validate app.json with it.
+const AppConfigSchemaURL = "https://raw.githubusercontent.com/meroxa/cli/main/cmd/meroxa/turbine/app.schema.json"
+
+//go:embed app.schema.json
+var appConfigSchema string
+
+const (
+	// SeverityError is the severity of problems that keep an app from being deployed or run.
+	SeverityError = "error"
+	// SeverityWarning is the severity of problems that should be fixed but don't keep an app from being deployed.
+	SeverityWarning = "warning"
+)
+
+// ConfigProblem is a problem found in the app.json file of an app, at the position of the field it's about.
+type ConfigProblem struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Field    string `json:"field"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (p ConfigProblem) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", p.File, p.Line, p.Column, p.Severity, p.Message)
+}
+
+// ConfigLint collects the problems found in the app.json file of an app.
+type ConfigLint struct {
+	// Config is the configuration of the app, nil when app.json doesn't match its schema.
+	Config   *AppConfig
+	Problems []ConfigProblem
+
+	file    string
+	data    []byte
+	offsets map[string]int
+}
+
+var quotedName = regexp.MustCompile(`'((?:[^'\\]|\\.)*)'`)
+
+// LintConfigFile checks the app.json file of the app in appPath against its JSON Schema, and that the fixtures
+// it declares exist. It returns an error only when app.json can't be read, problems are collected in ConfigLint.
+func LintConfigFile(appPath string) (*ConfigLint, error) {
+	file := filepath.Join(appPath, "app.json")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not find an app.json file on path %q."+
+			" Try a different value for `--path`", appPath)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+
+	l := &ConfigLint{Problems: []ConfigProblem{}, file: file, data: data, offsets: jsonOffsets(data)}
+
+	var v interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			l.addAt(int(syntaxErr.Offset)-1, "", SeverityError, "invalid JSON: %v", err)
+		} else {
+			l.Add("", SeverityError, "invalid JSON: %v", err)
+		}
+		return l, nil
+	}
+
+	schema, err := jsonschema.CompileString(AppConfigSchemaURL, appConfigSchema)
+	if err != nil {
+		return nil, err
+	}
+	if err = schema.Validate(v); err != nil {
+		var ve *jsonschema.ValidationError
+		if !errors.As(err, &ve) {
+			return nil, err
+		}
+		l.addSchemaErrors(ve)
+		return l, nil
+	}
+
+	var cfg AppConfig
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		l.Add("", SeverityError, "%v", err)
+		return l, nil
+	}
+	l.Config = &cfg
+
+	for name, fixture := range cfg.Resources {
+		if _, err = os.Stat(filepath.Join(appPath, fixture)); err != nil {
+			l.Add(ResourcePointer(name), SeverityWarning, "fixture %q of resource %q not found, meroxa apps run will fail without --fixtures",
+				fixture, name)
+		}
+	}
+	return l, nil
+}
+
+// ResourcePointer is the JSON pointer of the fixture of a resource in app.json.
+func ResourcePointer(name string) string {
+	return "/resources/" + escapePointer(name)
+}
+
+// Add adds a problem about the field at pointer, a JSON pointer such as /resources/pg.
+func (l *ConfigLint) Add(pointer, severity, format string, args ...interface{}) {
+	offset, ok := l.offsets[pointer]
+	for !ok && pointer != "" {
+		// Problems about missing fields are reported on their parent.
+		pointer = pointer[:strings.LastIndex(pointer, "/")]
+		offset, ok = l.offsets[pointer]
+	}
+	l.addAt(offset, pointer, severity, format, args...)
+}
+
+func (l *ConfigLint) addAt(offset int, pointer, severity, format string, args ...interface{}) {
+	line, column := position(l.data, offset)
+	p := ConfigProblem{
+		File:     l.file,
+		Line:     line,
+		Column:   column,
+		Field:    pointer,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	}
+
+	// Problems are kept in the order of their position in app.json.
+	i := sort.Search(len(l.Problems), func(i int) bool {
+		q := l.Problems[i]
+		return q.Line > line || q.Line == line && q.Column > column
+	})
+	l.Problems = append(l.Problems, ConfigProblem{})
+	copy(l.Problems[i+1:], l.Problems[i:])
+	l.Problems[i] = p
+}
+
+// Errors returns how many problems keep the app from being deployed.
+func (l *ConfigLint) Errors() int {
+	n := 0
+	for _, p := range l.Problems {
+		if p.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// addSchemaErrors adds the errors of the leaves of ve, which are the ones about a specific field.
+func (l *ConfigLint) addSchemaErrors(ve *jsonschema.ValidationError) {
+	if len(ve.Causes) > 0 {
+		for _, c := range ve.Causes {
+			l.addSchemaErrors(c)
+		}
+		return
+	}
+
+	field := ve.InstanceLocation
+	if strings.HasSuffix(ve.KeywordLocation, "/additionalProperties") && strings.HasPrefix(ve.Message, "additionalProperties") {
+		// Unknown fields are reported where they are rather than on their parent.
+		for _, m := range quotedName.FindAllStringSubmatch(ve.Message, -1) {
+			name, err := strconv.Unquote(`"` + m[1] + `"`)
+			if err != nil {
+				name = m[1]
+			}
+			l.Add(field+"/"+escapePointer(name), SeverityError, "unknown field %q", name)
+		}
+		return
+	}
+
+	name := strings.TrimPrefix(field, "/")
+	if name == "" {
+		l.Add(field, SeverityError, "%s", ve.Message)
+		return
+	}
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	l.Add(field, SeverityError, "%s: %s", strings.ReplaceAll(name, "/", "."), ve.Message)
+}
+
+// escapePointer escapes a token of a JSON pointer like JSON Schema validation errors do.
+func escapePointer(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	token = strings.ReplaceAll(token, "/", "~1")
+	return url.PathEscape(token)
+}
+
+// jsonOffsets returns the offset in data of each value of a JSON document by JSON pointer, which is the offset
+// of their key for the fields of objects.
+func jsonOffsets(data []byte) map[string]int {
+	offsets := map[string]int{"": skipSeparators(data, 0)}
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var walk func(pointer string) error
+	walk = func(pointer string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		delim, ok := tok.(json.Delim)
+		if !ok {
+			return nil
+		}
+		for i := 0; dec.More(); i++ {
+			offset := skipSeparators(data, int(dec.InputOffset()))
+			p := pointer + "/" + strconv.Itoa(i)
+			if delim == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				p = pointer + "/" + escapePointer(key.(string))
+			}
+			offsets[p] = offset
+			if err = walk(p); err != nil {
+				return err
+			}
+		}
+		// Closing delimiter.
+		_, err = dec.Token()
+		return err
+	}
+	// Positions are best effort, the offsets of invalid documents are the ones up to the error.
+	_ = walk("")
+	return offsets
+}
+
+func skipSeparators(data []byte, offset int) int {
+	for offset < len(data) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// position returns the line and column of offset in data, starting at 1.
+func position(data []byte, offset int) (line, column int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(data) {
+		offset = len(data)
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = offset - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,135 @@
+package turbine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/meroxa/turbine-core/pkg/ir"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintConfigFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		appJSON  string
+		config   *AppConfig
+		problems []string
+	}{
+		{
+			name: "Valid app.json",
+			appJSON: `{
+  "name": "my-app",
+  "language": "golang",
+  "environment": "common",
+  "resources": {"source_name": "fixtures/demo-cdc.json"},
+  "vendor": "false",
+  "module_init": "true"
+}`,
+			config: &AppConfig{
+				Name:        "my-app",
+				Language:    ir.GoLang,
+				Environment: "common",
+				Resources:   map[string]string{"source_name": "fixtures/demo-cdc.json"},
+				Vendor:      "false",
+				ModuleInit:  "true",
+			},
+			problems: []string{},
+		},
+		{
+			name: "Invalid JSON",
+			appJSON: `{
+  "name": "my-app",
+  "language": 
+}`,
+			problems: []string{"4:1: error: invalid JSON: invalid character '}' looking for beginning of value"},
+		},
+		{
+			name:     "Missing fields",
+			appJSON:  `{"name": "my-app"}`,
+			problems: []string{"1:1: error: missing properties: 'language'"},
+		},
+		{
+			name: "Invalid fields",
+			appJSON: `{
+  "name": "My App",
+  "language": "cobol",
+  "vendor": true,
+  "resources": {"pg": 1, "s3": "fixtures/s3.json"},
+  "resource": {}
+}`,
+			problems: []string{
+				"2:3: error: name: does not match pattern '^[a-z][a-z0-9-_]*$'",
+				`3:3: error: language: value must be one of "go", "golang", "js", "javascript", "nodejs", "py", "python", "python3", "rb", "ruby"`,
+				`4:3: error: vendor: value must be one of "true", "false"`,
+				"5:17: error: resources.pg: expected string, but got number",
+				`6:3: error: unknown field "resource"`,
+			},
+		},
+		{
+			name: "Missing fixture",
+			appJSON: `{
+  "name": "my-app",
+  "language": "ruby",
+  "resources": {
+    "demopg": "fixtures/missing.json"
+  }
+}`,
+			config: &AppConfig{
+				Name:      "my-app",
+				Language:  ir.Ruby,
+				Resources: map[string]string{"demopg": "fixtures/missing.json"},
+			},
+			problems: []string{
+				`5:5: warning: fixture "fixtures/missing.json" of resource "demopg" not found, meroxa apps run will fail without --fixtures`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "fixtures"), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "fixtures", "demo-cdc.json"), []byte("{}"), 0o644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "app.json"), []byte(tc.appJSON), 0o644))
+
+			lint, err := LintConfigFile(dir)
+			require.NoError(t, err)
+			assert.Equal(t, tc.config, lint.Config)
+
+			problems := make([]string, 0, len(lint.Problems))
+			for _, p := range lint.Problems {
+				assert.Equal(t, filepath.Join(dir, "app.json"), p.File)
+				problems = append(problems, fmt.Sprintf("%d:%d: %s: %s", p.Line, p.Column, p.Severity, p.Message))
+			}
+			assert.Equal(t, tc.problems, problems)
+		})
+	}
+}
+
+func TestLintConfigFileMissing(t *testing.T) {
+	_, err := LintConfigFile(t.TempDir())
+	require.Error(t, err)
+}
+
+func TestConfigLintAdd(t *testing.T) {
+	data := []byte("{\n  \"name\": \"my-app\",\n  \"resources\": {\n    \"pg\": \"fixtures/pg.json\"\n  }\n}\n")
+	l := &ConfigLint{file: "app.json", data: data, offsets: jsonOffsets(data)}
+
+	l.Add(ResourcePointer("pg"), SeverityWarning, "resource %q isn't used by the application", "pg")
+	l.Add(ResourcePointer("s3"), SeverityError, "resource %q is missing", "s3")
+	l.Add("", SeverityError, "no resources defined in your Turbine app")
+
+	var got []string
+	for _, p := range l.Problems {
+		got = append(got, p.String())
+	}
+	assert.Equal(t, []string{
+		"app.json:1:1: error: no resources defined in your Turbine app",
+		"app.json:3:3: error: resource \"s3\" is missing",
+		"app.json:4:5: warning: resource \"pg\" isn't used by the application",
+	}, got)
+	assert.Equal(t, 2, l.Errors())
+}
@@ -0,0 +1,143 @@
+package turbine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CommonEnvironment is the environment apps are deployed to unless configured otherwise.
+const CommonEnvironment = "common"
+
+var profileName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// ConfigOverlay is an app.PROFILE.json file, which overrides the settings of app.json that differ between the
+// environments an app is deployed to, e.g. app.staging.json and app.prod.json.
+type ConfigOverlay struct {
+	Environment   string            `json:"environment"`
+	Resources     map[string]string `json:"resources"`
+	ResourceNames map[string]string `json:"resource_names"`
+	Secrets       map[string]string `json:"secrets"`
+}
+
+// OverlayPath returns the path of the overlay of profile for the app in appPath.
+func OverlayPath(appPath, profile string) string {
+	return filepath.Join(appPath, fmt.Sprintf("app.%s.json", profile))
+}
+
+// ReadProfileConfigFile reads app.json merged with the overlay of profile, or only app.json without a profile.
+func ReadProfileConfigFile(appPath, profile string) (*AppConfig, error) {
+	base, err := ReadConfigFile(appPath)
+	if err != nil || profile == "" {
+		return base, err
+	}
+
+	overlay, err := ReadConfigOverlay(appPath, profile)
+	if err != nil {
+		return nil, err
+	}
+	cfg := base.Merge(overlay)
+	return &cfg, nil
+}
+
+// ReadConfigOverlay reads the overlay of profile, rejecting the settings overlays can't override.
+func ReadConfigOverlay(appPath, profile string) (*ConfigOverlay, error) {
+	if !profileName.MatchString(profile) {
+		return nil, fmt.Errorf("invalid profile %q, profiles only have letters, digits, dashes and underscores", profile)
+	}
+
+	file := OverlayPath(appPath, profile)
+	b, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("could not find an %s file for profile %q on path %q", filepath.Base(file), profile, appPath)
+		}
+		return nil, err
+	}
+
+	var overlay ConfigOverlay
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&overlay); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w, overlays can only set environment, resources, resource_names and secrets",
+			filepath.Base(file), err)
+	}
+	return &overlay, nil
+}
+
+// Merge returns the configuration with the settings of overlay. Maps are merged, their entries are overridden one
+// by one and entries set to an empty string are removed.
+func (c *AppConfig) Merge(overlay *ConfigOverlay) AppConfig {
+	merged := *c
+	if overlay.Environment != "" {
+		merged.Environment = overlay.Environment
+	}
+	merged.Resources = mergeMaps(c.Resources, overlay.Resources)
+	merged.ResourceNames = mergeMaps(c.ResourceNames, overlay.ResourceNames)
+	merged.Secrets = mergeMaps(c.Secrets, overlay.Secrets)
+	return merged
+}
+
+func mergeMaps(base, overlay map[string]string) map[string]string {
+	if base == nil && overlay == nil {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		if v == "" {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// DeployEnvironment returns the environment the app is configured to be deployed to, empty when it's not set or
+// it's the common environment.
+func (c *AppConfig) DeployEnvironment() string {
+	if strings.EqualFold(c.Environment, CommonEnvironment) {
+		return ""
+	}
+	return c.Environment
+}
+
+// PlatformResource returns the Platform resource the app is deployed with for a resource used in its code.
+func (c *AppConfig) PlatformResource(name string) string {
+	if n, ok := c.ResourceNames[name]; ok && n != "" {
+		return n
+	}
+	return name
+}
+
+// ExpandSecrets returns the secrets of the app with the environment variables they refer to replaced by their
+// values, failing when one isn't set.
+func (c *AppConfig) ExpandSecrets() (map[string]string, error) {
+	secrets := make(map[string]string, len(c.Secrets))
+	var missing []string
+	seen := map[string]bool{}
+	for name, value := range c.Secrets {
+		secrets[name] = os.Expand(value, func(v string) string {
+			val, ok := os.LookupEnv(v)
+			if !ok && !seen[v] {
+				missing = append(missing, v)
+				seen[v] = true
+			}
+			return val
+		})
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("secrets refer to environment variables which aren't set: %s", strings.Join(missing, ", "))
+	}
+	return secrets, nil
+}
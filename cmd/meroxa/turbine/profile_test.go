@@ -0,0 +1,83 @@
+package turbine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/meroxa/turbine-core/pkg/ir"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadProfileConfigFile(t *testing.T) {
+	t.Setenv("UNIT_TEST", "true")
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.json": `{
+  "name": "my-app",
+  "language": "golang",
+  "environment": "common",
+  "resources": {"pg": "fixtures/pg.json", "mysql": "fixtures/mysql.json"},
+  "secrets": {"LOG_LEVEL": "debug"}
+}`,
+		"app.prod.json": `{
+  "environment": "prod",
+  "resources": {"mysql": ""},
+  "resource_names": {"pg": "pg-prod"},
+  "secrets": {"API_KEY": "${API_KEY}"}
+}`,
+		"app.invalid.json": `{"name": "other-app"}`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	cfg, err := ReadProfileConfigFile(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, "common", cfg.Environment)
+	assert.Equal(t, "", cfg.DeployEnvironment())
+	assert.Equal(t, "pg", cfg.PlatformResource("pg"))
+
+	cfg, err = ReadProfileConfigFile(dir, "prod")
+	require.NoError(t, err)
+	assert.Equal(t, &AppConfig{
+		Name:          "my-app",
+		Language:      ir.GoLang,
+		Environment:   "prod",
+		Resources:     map[string]string{"pg": "fixtures/pg.json"},
+		ResourceNames: map[string]string{"pg": "pg-prod"},
+		Secrets:       map[string]string{"LOG_LEVEL": "debug", "API_KEY": "${API_KEY}"},
+	}, cfg)
+	assert.Equal(t, "prod", cfg.DeployEnvironment())
+	assert.Equal(t, "pg-prod", cfg.PlatformResource("pg"))
+	assert.Equal(t, "s3", cfg.PlatformResource("s3"))
+
+	base, err := ReadConfigFile(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "common", base.Environment, "app.json must not be changed by overlays")
+
+	_, err = ReadProfileConfigFile(dir, "invalid")
+	require.EqualError(t, err,
+		`invalid app.invalid.json: json: unknown field "name", overlays can only set environment, resources, resource_names and secrets`)
+
+	_, err = ReadProfileConfigFile(dir, "staging")
+	require.ErrorContains(t, err, `could not find an app.staging.json file for profile "staging"`)
+
+	_, err = ReadProfileConfigFile(dir, "../prod")
+	require.ErrorContains(t, err, `invalid profile "../prod"`)
+}
+
+func TestExpandSecrets(t *testing.T) {
+	t.Setenv("API_KEY", "s3cr3t")
+	cfg := &AppConfig{Secrets: map[string]string{"API_KEY": "${API_KEY}", "URL": "https://$API_KEY@example.com"}}
+
+	secrets, err := cfg.ExpandSecrets()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"API_KEY": "s3cr3t", "URL": "https://s3cr3t@example.com"}, secrets)
+
+	cfg.Secrets["OTHER"] = "${MISSING_SECRET}-${MISSING_SECRET}"
+	_, err = cfg.ExpandSecrets()
+	require.EqualError(t, err, "secrets refer to environment variables which aren't set: MISSING_SECRET")
+}
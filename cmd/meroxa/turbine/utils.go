@@ -47,6 +47,12 @@ type AppConfig struct {
 	Resources   map[string]string `json:"resources"`
 	Vendor      string            `json:"vendor"`
 	ModuleInit  string            `json:"module_init"`
+	// ResourceNames maps the resources used in the code of the app to the Platform resources it's deployed with,
+	// resources which aren't mapped are deployed with the Platform resource of the same name.
+	ResourceNames map[string]string `json:"resource_names,omitempty"`
+	// Secrets are added to the secrets of the app when it's deployed, values can refer to environment variables
+	// with ${VAR} rather than being checked in.
+	Secrets map[string]string `json:"secrets,omitempty"`
 }
 
 var prefetched *AppConfig
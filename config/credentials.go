@@ -0,0 +1,146 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrCredentialNotFound is returned by a CredentialStore when it holds no value for a key.
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore keeps secrets, such as OAuth tokens, out of the configuration file.
+type CredentialStore interface {
+	// Name identifies the store, e.g. "secret-service".
+	Name() string
+	Get(key string) (string, error)
+	Has(key string) (bool, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// CredentialConfig is a Config which reads and writes the given credential keys from a CredentialStore
+// and every other key from the wrapped Config. Writes to the store are deferred until Save is called.
+type CredentialConfig struct {
+	Config
+
+	store     CredentialStore
+	namespace string
+	keys      map[string]bool
+
+	values  map[string]string
+	pending map[string]bool
+	err     error
+}
+
+// NewCredentialConfig is the constructor for CredentialConfig. Credentials are stored under namespace, so that
+// several sets of credentials can share a store.
+func NewCredentialConfig(cfg Config, store CredentialStore, namespace string, keys ...string) *CredentialConfig {
+	c := &CredentialConfig{
+		Config:    cfg,
+		store:     store,
+		namespace: namespace,
+		keys:      make(map[string]bool, len(keys)),
+		values:    make(map[string]string),
+		pending:   make(map[string]bool),
+	}
+	for _, k := range keys {
+		c.keys[strings.ToUpper(k)] = true
+	}
+	return c
+}
+
+// Store returns the store holding credentials.
+func (c *CredentialConfig) Store() CredentialStore {
+	return c.store
+}
+
+func (c *CredentialConfig) isCredential(key string) bool {
+	return c.keys[strings.ToUpper(key)]
+}
+
+func (c *CredentialConfig) storeKey(key string) string {
+	return c.namespace + "/" + strings.ToUpper(key)
+}
+
+func (c *CredentialConfig) Set(key string, value interface{}) {
+	if !c.isCredential(key) {
+		c.Config.Set(key, value)
+		return
+	}
+
+	s, _ := value.(string)
+	c.values[strings.ToUpper(key)] = s
+	c.pending[strings.ToUpper(key)] = true
+}
+
+// GetString returns the value of key. Errors from the store are kept and returned by Err, an empty string is
+// returned in that case.
+func (c *CredentialConfig) GetString(key string) string {
+	if !c.isCredential(key) {
+		return c.Config.GetString(key)
+	}
+
+	k := strings.ToUpper(key)
+	if v, ok := c.values[k]; ok {
+		return v
+	}
+
+	v, err := c.store.Get(c.storeKey(k))
+	switch {
+	case errors.Is(err, ErrCredentialNotFound):
+	case err != nil:
+		c.err = err
+		return ""
+	}
+	c.values[k] = v
+	return v
+}
+
+// Has reports whether the store holds a value for key, without reading it.
+func (c *CredentialConfig) Has(key string) (bool, error) {
+	if v, ok := c.values[strings.ToUpper(key)]; ok {
+		return v != "", nil
+	}
+	return c.store.Has(c.storeKey(key))
+}
+
+// Err returns the first error encountered while reading from the store.
+func (c *CredentialConfig) Err() error {
+	return c.err
+}
+
+// Save writes the credentials set since the last call to the store. Empty values are deleted from it.
+func (c *CredentialConfig) Save() error {
+	for k := range c.pending {
+		var err error
+		if v := c.values[k]; v == "" {
+			err = c.store.Delete(c.storeKey(k))
+			if errors.Is(err, ErrCredentialNotFound) {
+				err = nil
+			}
+		} else {
+			err = c.store.Set(c.storeKey(k), v)
+		}
+		if err != nil {
+			return err
+		}
+		delete(c.pending, k)
+	}
+	return nil
+}
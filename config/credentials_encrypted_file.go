@@ -0,0 +1,219 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	encryptedFileVersion = 1
+	// encryptedFileIterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256.
+	encryptedFileIterations = 600000
+	encryptedFileKeyLen     = 32
+	encryptedFileSaltLen    = 16
+)
+
+// ErrWrongPassphrase is returned when credentials can't be decrypted with the given passphrase.
+var ErrWrongPassphrase = errors.New("could not decrypt credentials, wrong passphrase")
+
+// EncryptedFileStore keeps credentials in a file, each encrypted with AES-256-GCM using a key derived from a
+// passphrase. The passphrase is only asked for when a credential is read or written.
+type EncryptedFileStore struct {
+	path       string
+	passphrase func() (string, error)
+	iterations int
+
+	key []byte
+}
+
+type encryptedFile struct {
+	Version    int               `json:"version"`
+	Salt       []byte            `json:"salt"`
+	Iterations int               `json:"iterations"`
+	Entries    map[string][]byte `json:"entries"`
+}
+
+// NewEncryptedFileStore is the constructor for EncryptedFileStore.
+func NewEncryptedFileStore(path string, passphrase func() (string, error)) *EncryptedFileStore {
+	return &EncryptedFileStore{
+		path:       path,
+		passphrase: passphrase,
+		iterations: encryptedFileIterations,
+	}
+}
+
+func (s *EncryptedFileStore) Name() string {
+	return "encrypted-file"
+}
+
+func (s *EncryptedFileStore) Get(key string) (string, error) {
+	f, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	sealed, ok := f.Entries[key]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+
+	gcm, err := s.cipher(f)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("invalid credential %q in %s", key, s.path)
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	// The key is used as additional data so that entries can't be swapped.
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		s.key = nil
+		return "", ErrWrongPassphrase
+	}
+	return string(plaintext), nil
+}
+
+func (s *EncryptedFileStore) Has(key string) (bool, error) {
+	f, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	_, ok := f.Entries[key]
+	return ok, nil
+}
+
+func (s *EncryptedFileStore) Set(key, value string) error {
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	gcm, err := s.cipher(f)
+	if err != nil {
+		return err
+	}
+	// Make sure the passphrase matches the one of the existing entries before adding a new one.
+	for k := range f.Entries {
+		if _, err = s.Get(k); err != nil {
+			return err
+		}
+		break
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	f.Entries[key] = gcm.Seal(nonce, nonce, []byte(value), []byte(key))
+	return s.write(f)
+}
+
+// Keys returns the keys of the credentials in the store, sorted. They aren't encrypted, so no passphrase is needed.
+func (s *EncryptedFileStore) Keys() ([]string, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(f.Entries))
+	for k := range f.Entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *EncryptedFileStore) Delete(key string) error {
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := f.Entries[key]; !ok {
+		return ErrCredentialNotFound
+	}
+	delete(f.Entries, key)
+	return s.write(f)
+}
+
+func (s *EncryptedFileStore) read() (*encryptedFile, error) {
+	b, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		salt := make([]byte, encryptedFileSaltLen)
+		if _, err = io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, err
+		}
+		return &encryptedFile{
+			Version:    encryptedFileVersion,
+			Salt:       salt,
+			Iterations: s.iterations,
+			Entries:    make(map[string][]byte),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f encryptedFile
+	if err = json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("could not read credentials file %s: %w", s.path, err)
+	}
+	if f.Version != encryptedFileVersion {
+		return nil, fmt.Errorf("unsupported credentials file version %d in %s", f.Version, s.path)
+	}
+	if f.Entries == nil {
+		f.Entries = make(map[string][]byte)
+	}
+	return &f, nil
+}
+
+func (s *EncryptedFileStore) write(f *encryptedFile) error {
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(s.path, b)
+}
+
+func (s *EncryptedFileStore) cipher(f *encryptedFile) (cipher.AEAD, error) {
+	if s.key == nil {
+		passphrase, err := s.passphrase()
+		if err != nil {
+			return nil, err
+		}
+		if passphrase == "" {
+			return nil, errors.New("a passphrase is required to encrypt credentials")
+		}
+		s.key = pbkdf2.Key([]byte(passphrase), f.Salt, f.Iterations, encryptedFileKeyLen, sha256.New)
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileStore keeps credentials in a JSON file only readable by the current user. It's used where neither the
+// Secret Service nor a passphrase are available.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore is the constructor for FileStore.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) Name() string {
+	return "file"
+}
+
+func (s *FileStore) Get(key string) (string, error) {
+	values, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	v, ok := values[key]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return v, nil
+}
+
+func (s *FileStore) Has(key string) (bool, error) {
+	values, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	_, ok := values[key]
+	return ok, nil
+}
+
+func (s *FileStore) Set(key, value string) error {
+	values, err := s.read()
+	if err != nil {
+		return err
+	}
+	values[key] = value
+	return s.write(values)
+}
+
+func (s *FileStore) Delete(key string) error {
+	values, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := values[key]; !ok {
+		return ErrCredentialNotFound
+	}
+	delete(values, key)
+	return s.write(values)
+}
+
+func (s *FileStore) read() (map[string]string, error) {
+	values := make(map[string]string)
+	b, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("could not read credentials file %s: %w", s.path, err)
+	}
+	return values, nil
+}
+
+func (s *FileStore) write(values map[string]string) error {
+	b, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(s.path, b)
+}
+
+// writePrivateFile writes b to path, only readable and writable by the current user.
+func writePrivateFile(path string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return err
+	}
+	// WriteFile doesn't change the permissions of an existing file.
+	return os.Chmod(path, 0o600)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const secretServiceName = "meroxa-cli"
+
+// SecretServiceStore keeps credentials in the Secret Service of the desktop session (e.g. GNOME Keyring or
+// KWallet), through secret-tool which talks to it over D-Bus.
+type SecretServiceStore struct {
+	// run executes secret-tool with args, writing stdin to it, and returns its output.
+	run func(stdin string, args ...string) (string, error)
+}
+
+// NewSecretServiceStore is the constructor for SecretServiceStore.
+func NewSecretServiceStore() *SecretServiceStore {
+	return &SecretServiceStore{run: runSecretTool}
+}
+
+func (s *SecretServiceStore) Name() string {
+	return "secret-service"
+}
+
+func (s *SecretServiceStore) Get(key string) (string, error) {
+	out, err := s.run("", "lookup", "service", secretServiceName, "key", key)
+	if err != nil {
+		return "", err
+	}
+	if out == "" {
+		return "", ErrCredentialNotFound
+	}
+	return out, nil
+}
+
+func (s *SecretServiceStore) Has(key string) (bool, error) {
+	_, err := s.Get(key)
+	if errors.Is(err, ErrCredentialNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *SecretServiceStore) Set(key, value string) error {
+	_, err := s.run(value, "store", "--label", "Meroxa CLI "+key, "service", secretServiceName, "key", key)
+	return err
+}
+
+func (s *SecretServiceStore) Delete(key string) error {
+	_, err := s.run("", "clear", "service", secretServiceName, "key", key)
+	return err
+}
+
+func runSecretTool(stdin string, args ...string) (string, error) {
+	path, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return "", errors.New("the secret-service credentials store requires secret-tool, " +
+			"install it with your package manager (e.g. libsecret-tools)")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		// secret-tool lookup exits with 1 and no output when there's no secret.
+		if errors.As(err, &exitErr) && stderr.Len() == 0 && args[0] == "lookup" {
+			return "", nil
+		}
+		return "", fmt.Errorf("secret-tool %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSuffix(stdout.String(), "\n"), nil
+}
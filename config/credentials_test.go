@@ -0,0 +1,164 @@
+/*
+Copyright © 2022 Meroxa Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or impliee.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	testCredentialStore(t, NewFileStore(path))
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("expected credentials file to be private, got %v", perm)
+	}
+}
+
+func TestEncryptedFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc.json")
+	passphrase := func() (string, error) { return "secret", nil }
+
+	s := NewEncryptedFileStore(path, passphrase)
+	s.iterations = 10
+	testCredentialStore(t, s)
+
+	if err := s.Set("default/ACCESS_TOKEN", "my-token"); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "my-token") {
+		t.Fatal("expected credentials to be encrypted")
+	}
+
+	// A new store reads the file with the same passphrase.
+	if v, err := NewEncryptedFileStore(path, passphrase).Get("default/ACCESS_TOKEN"); err != nil || v != "my-token" {
+		t.Fatalf("expected %q, got %q (%v)", "my-token", v, err)
+	}
+
+	wrong := NewEncryptedFileStore(path, func() (string, error) { return "not-secret", nil })
+	// Listing keys doesn't need the passphrase.
+	if keys, err := wrong.Keys(); err != nil || strings.Join(keys, ",") != "default/ACCESS_TOKEN,staging/ACCESS_TOKEN" {
+		t.Fatalf("expected sorted keys, got %q (%v)", keys, err)
+	}
+	if _, err = wrong.Get("default/ACCESS_TOKEN"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected %v, got %v", ErrWrongPassphrase, err)
+	}
+	if err = wrong.Set("default/REFRESH_TOKEN", "other"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected %v, got %v", ErrWrongPassphrase, err)
+	}
+}
+
+func TestSecretServiceStore(t *testing.T) {
+	secrets := map[string]string{}
+	s := &SecretServiceStore{run: func(stdin string, args ...string) (string, error) {
+		key := args[len(args)-1]
+		switch args[0] {
+		case "lookup":
+			return secrets[key], nil
+		case "store":
+			secrets[key] = stdin
+		case "clear":
+			delete(secrets, key)
+		}
+		return "", nil
+	}}
+	testCredentialStore(t, s)
+}
+
+func TestCredentialConfig(t *testing.T) {
+	cfg := NewInMemoryConfig()
+	store := NewFileStore(filepath.Join(t.TempDir(), "credentials.json"))
+	if err := store.Set("staging/ACCESS_TOKEN", "stored-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCredentialConfig(cfg, store, "staging", "ACCESS_TOKEN", "REFRESH_TOKEN")
+
+	if got := c.GetString("access_token"); got != "stored-token" {
+		t.Fatalf("expected %q, got %q", "stored-token", got)
+	}
+	if ok, err := c.Has("REFRESH_TOKEN"); err != nil || ok {
+		t.Fatalf("expected no refresh token, got %v (%v)", ok, err)
+	}
+
+	c.Set("REFRESH_TOKEN", "refresh")
+	c.Set("ACTOR", "user@meroxa.io")
+	if got := cfg.GetString("ACTOR"); got != "user@meroxa.io" {
+		t.Fatalf("expected other keys to be kept in the config, got %q", got)
+	}
+	if _, ok := cfg.values["REFRESH_TOKEN"]; ok {
+		t.Fatalf("expected credentials to be kept out of the config")
+	}
+	if ok, _ := store.Has("staging/REFRESH_TOKEN"); ok {
+		t.Fatal("expected credentials not to be stored before Save")
+	}
+
+	c.Set("ACCESS_TOKEN", "")
+	if err := c.Save(); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if v, _ := store.Get("staging/REFRESH_TOKEN"); v != "refresh" {
+		t.Fatalf("expected %q, got %q", "refresh", v)
+	}
+	if _, err := store.Get("staging/ACCESS_TOKEN"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected empty credentials to be deleted, got %v", err)
+	}
+	if err := c.Err(); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+}
+
+func testCredentialStore(t *testing.T, s CredentialStore) {
+	t.Helper()
+
+	if _, err := s.Get("default/ACCESS_TOKEN"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected %v, got %v", ErrCredentialNotFound, err)
+	}
+	if err := s.Set("default/ACCESS_TOKEN", "token"); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if err := s.Set("staging/ACCESS_TOKEN", "staging-token"); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if v, err := s.Get("default/ACCESS_TOKEN"); err != nil || v != "token" {
+		t.Fatalf("expected %q, got %q (%v)", "token", v, err)
+	}
+	if ok, err := s.Has("staging/ACCESS_TOKEN"); err != nil || !ok {
+		t.Fatalf("expected credential to exist, got %v (%v)", ok, err)
+	}
+	if err := s.Delete("default/ACCESS_TOKEN"); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if ok, err := s.Has("default/ACCESS_TOKEN"); err != nil || ok {
+		t.Fatalf("expected credential to be deleted, got %v (%v)", ok, err)
+	}
+	if v, err := s.Get("staging/ACCESS_TOKEN"); err != nil || v != "staging-token" {
+		t.Fatalf("expected %q, got %q (%v)", "staging-token", v, err)
+	}
+}
@@ -2,7 +2,6 @@ package log
 
 import (
 	"context"
-	"encoding/json"
 	"io"
 	"log"
 )
@@ -20,15 +19,10 @@ type jsonLogger struct {
 }
 
 func (l *jsonLogger) JSON(_ context.Context, data interface{}) {
-	if raw, ok := data.(string); ok {
-		l.l.Print(raw)
-		return
-	}
-
-	p, err := json.MarshalIndent(data, "", "\t")
+	p, err := FormatJSON(data)
 	if err != nil {
 		l.l.Printf("could not marshal JSON: %s", err.Error())
 		return
 	}
-	l.l.Print(string(p))
+	l.l.Print(p)
 }
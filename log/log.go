@@ -6,23 +6,26 @@ type Logger interface {
 	LeveledLogger
 	JSONLogger
 	SpinnerLogger
+	OutputLogger
 }
 
 type logger struct {
 	LeveledLogger
 	JSONLogger
 	SpinnerLogger
+	OutputLogger
 }
 
-func New(l1 LeveledLogger, l2 JSONLogger, l3 SpinnerLogger) Logger {
+func New(l1 LeveledLogger, l2 JSONLogger, l3 SpinnerLogger, l4 OutputLogger) Logger {
 	return logger{
 		LeveledLogger: l1,
 		JSONLogger:    l2,
 		SpinnerLogger: l3,
+		OutputLogger:  l4,
 	}
 }
 
 func NewWithDevNull() Logger {
 	o, _ := os.Open(os.DevNull)
-	return New(NewLeveledLogger(o, Debug), NewJSONLogger(o), NewSpinnerLogger(o))
+	return New(NewLeveledLogger(o, Debug), NewJSONLogger(o), NewSpinnerLogger(o), NewOutputLogger(o, FormatJSON))
 }
@@ -0,0 +1,63 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+)
+
+// OutputLogger prints the result of a command, in the format chosen with --output.
+type OutputLogger interface {
+	Output(ctx context.Context, data interface{})
+}
+
+// Formatter renders the result of a command. Nothing is printed when it returns an empty string.
+type Formatter func(data interface{}) (string, error)
+
+// NewOutputLogger returns an OutputLogger printing data rendered by format.
+func NewOutputLogger(out io.Writer, format Formatter) OutputLogger {
+	return newFormattedLogger(out, format)
+}
+
+// NewFormattedJSONLogger returns a JSONLogger printing data rendered by format instead of JSON.
+func NewFormattedJSONLogger(out io.Writer, format Formatter) JSONLogger {
+	return newFormattedLogger(out, format)
+}
+
+// FormatJSON renders data as indented JSON, strings are expected to be JSON already and are kept as is.
+func FormatJSON(data interface{}) (string, error) {
+	if raw, ok := data.(string); ok {
+		return raw, nil
+	}
+
+	p, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return "", err
+	}
+	return string(p), nil
+}
+
+type formattedLogger struct {
+	l      *log.Logger
+	format Formatter
+}
+
+func newFormattedLogger(out io.Writer, format Formatter) *formattedLogger {
+	return &formattedLogger{l: log.New(out, "", 0), format: format}
+}
+
+func (l *formattedLogger) Output(_ context.Context, data interface{}) {
+	s, err := l.format(data)
+	if err != nil {
+		l.l.Printf("could not format output: %s", err.Error())
+		return
+	}
+	if s != "" {
+		l.l.Print(s)
+	}
+}
+
+func (l *formattedLogger) JSON(ctx context.Context, data interface{}) {
+	l.Output(ctx, data)
+}
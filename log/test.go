@@ -1,21 +1,56 @@
 package log
 
-import "bytes"
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/meroxa/cli/utils/display"
+)
 
 func NewTestLogger() *TestLogger {
 	var leveledBuf bytes.Buffer
 	var jsonBuf bytes.Buffer
 	var spinnerBuf bytes.Buffer
-	return &TestLogger{
+	l := &TestLogger{
 		leveledBuf: &leveledBuf,
 		jsonBuf:    &jsonBuf,
 		spinnerBuf: &spinnerBuf,
-		Logger: New(
-			NewLeveledLogger(&leveledBuf, Debug),
-			NewJSONLogger(&jsonBuf),
-			NewSpinnerLogger(&spinnerBuf),
-		),
 	}
+	l.Logger = New(
+		NewLeveledLogger(&leveledBuf, Debug),
+		NewJSONLogger(&jsonBuf),
+		NewSpinnerLogger(&spinnerBuf),
+		testOutputLogger{
+			// The result of commands is captured as JSON, the format used by --json, and as the table
+			// printed by default.
+			json:    NewOutputLogger(&jsonBuf, FormatJSON),
+			table:   NewOutputLogger(&leveledBuf, display.NewFormatter(display.OutputFormat{Name: display.OutputTable}, display.TableOptions{})),
+			leveled: &leveledBuf,
+			err:     &l.outputErr,
+		},
+	)
+	return l
+}
+
+// testOutputLogger prints the result of commands as JSON and as a table. A result without a table, which would only
+// be printed as JSON by default, is reported by TestLogger.OutputErr and in place of the table, so that tests
+// comparing the output fail with why.
+type testOutputLogger struct {
+	json, table OutputLogger
+	leveled     io.Writer
+	err         *error
+}
+
+func (l testOutputLogger) Output(ctx context.Context, data interface{}) {
+	l.json.Output(ctx, data)
+	if data != nil && !display.HasTable(data) {
+		*l.err = fmt.Errorf("no table registered for %T, register one with display.RegisterTable", data)
+		fmt.Fprintln(l.leveled, *l.err)
+		return
+	}
+	l.table.Output(ctx, data)
 }
 
 type TestLogger struct {
@@ -23,6 +58,7 @@ type TestLogger struct {
 	leveledBuf *bytes.Buffer
 	jsonBuf    *bytes.Buffer
 	spinnerBuf *bytes.Buffer
+	outputErr  error
 }
 
 var _ Logger = (*TestLogger)(nil)
@@ -38,3 +74,8 @@ func (l *TestLogger) LeveledOutput() string {
 func (l *TestLogger) SpinnerOutput() string {
 	return l.spinnerBuf.String()
 }
+
+// OutputErr returns why the last result without a table couldn't be printed as one, if any.
+func (l *TestLogger) OutputErr() error {
+	return l.outputErr
+}
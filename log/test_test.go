@@ -0,0 +1,27 @@
+package log
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTestLoggerOutputWithoutTable(t *testing.T) {
+	type result struct {
+		Name string `json:"name"`
+	}
+
+	logger := NewTestLogger()
+	logger.Output(context.Background(), result{Name: "pg"})
+
+	want := "no table registered for log.result, register one with display.RegisterTable"
+	if err := logger.OutputErr(); err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+	if got := logger.LeveledOutput(); !strings.Contains(got, want) {
+		t.Fatalf("expected the error in place of the table, got %q", got)
+	}
+	if got := logger.JSONOutput(); !strings.Contains(got, `"name": "pg"`) {
+		t.Fatalf("expected the result as JSON, got %q", got)
+	}
+}
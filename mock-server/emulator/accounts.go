@@ -0,0 +1,142 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+// resourceTypes are the types of resources the emulator supports.
+var resourceTypes = []meroxa.ResourceTypeName{
+	meroxa.ResourceTypePostgres,
+	meroxa.ResourceTypeMysql,
+	meroxa.ResourceTypeRedshift,
+	meroxa.ResourceTypeUrl,
+	meroxa.ResourceTypeS3,
+	meroxa.ResourceTypeMongodb,
+	meroxa.ResourceTypeElasticsearch,
+	meroxa.ResourceTypeSnowflake,
+	meroxa.ResourceTypeBigquery,
+	meroxa.ResourceTypeSqlserver,
+	meroxa.ResourceTypeCosmosdb,
+	meroxa.ResourceTypeKafka,
+	meroxa.ResourceTypeConfluentCloud,
+	meroxa.ResourceTypeNotion,
+}
+
+func (s *Server) listAccounts(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, []meroxa.Account{s.account})
+}
+
+func (s *Server) getUser(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.user)
+}
+
+func (s *Server) listTransforms(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, []meroxa.Transform{
+		{
+			ID:          1,
+			Name:        "ReplaceField",
+			Description: "Filter or rename fields",
+			Type:        "builtin",
+			Properties: []meroxa.Property{
+				{Name: "exclude", Type: "list"},
+				{Name: "include", Type: "list"},
+				{Name: "rename", Type: "list"},
+			},
+		},
+		{
+			ID:          2,
+			Name:        "MaskField",
+			Description: "Mask fields with a valid null value for the field type",
+			Type:        "builtin",
+			Properties: []meroxa.Property{
+				{Name: "fields", Required: true, Type: "list"},
+			},
+		},
+	})
+}
+
+func (s *Server) listResourceTypes(w http.ResponseWriter, _ *http.Request) {
+	names := make([]string, 0, len(resourceTypes))
+	for _, t := range resourceTypes {
+		names = append(names, string(t))
+	}
+	writeJSON(w, http.StatusOK, names)
+}
+
+func (s *Server) listResourceTypesV2(w http.ResponseWriter, _ *http.Request) {
+	types := make([]meroxa.ResourceType, 0, len(resourceTypes))
+	for _, t := range resourceTypes {
+		types = append(types, meroxa.ResourceType{
+			UUID:         uuid.NewSHA1(uuid.NameSpaceURL, []byte(t)).String(),
+			Name:         string(t),
+			ReleaseStage: meroxa.ResourceTypeReleaseStageGA,
+			Categories:   []string{"database"},
+			FormConfig: map[string]interface{}{
+				meroxa.ResourceTypeFormConfigHumanReadableKey: strings.ToUpper(string(t[:1])) + string(t[1:]),
+			},
+			OptedIn:   true,
+			HasAccess: true,
+		})
+	}
+	writeJSON(w, http.StatusOK, types)
+}
+
+// seed creates the entities of a scenario in their final state.
+func (s *Server) seed(seed Seed) error {
+	final := func(state string) timeline {
+		return timeline{since: s.now().UTC(), states: []string{state}}
+	}
+
+	for i := range seed.Environments {
+		in := &seed.Environments[i]
+		if err := seedError(KindEnvironment, in.Name, func(w http.ResponseWriter) bool {
+			_, ok := s.addEnvironment(w, in, final(string(meroxa.EnvironmentStateReady)))
+			return ok
+		}); err != nil {
+			return err
+		}
+	}
+	for i := range seed.Resources {
+		in := &seed.Resources[i]
+		if err := seedError(KindResource, in.Name, func(w http.ResponseWriter) bool {
+			_, ok := s.addResource(w, in, final(string(meroxa.ResourceStateReady)))
+			return ok
+		}); err != nil {
+			return err
+		}
+	}
+	for i := range seed.Pipelines {
+		in := &seed.Pipelines[i]
+		if err := seedError(KindPipeline, in.Name, func(w http.ResponseWriter) bool {
+			_, ok := s.addPipeline(w, in)
+			return ok
+		}); err != nil {
+			return err
+		}
+	}
+	for i := range seed.Apps {
+		in := &seed.Apps[i]
+		if err := seedError(KindApp, in.Name, func(w http.ResponseWriter) bool {
+			_, ok := s.addApp(w, in)
+			return ok
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedError creates an entity of a seed with add, returning the error it responded with if it couldn't.
+func seedError(kind Kind, name string, add func(w http.ResponseWriter) bool) error {
+	rec := httptest.NewRecorder()
+	if add(rec) {
+		return nil
+	}
+	return fmt.Errorf("could not seed %s %q: %s", kind, name, strings.TrimSpace(rec.Body.String()))
+}
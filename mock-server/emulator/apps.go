@@ -0,0 +1,258 @@
+package emulator
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var deploymentStates = []string{
+	string(meroxa.DeploymentStateDeploying),
+	string(meroxa.DeploymentStateDeployed),
+}
+
+// appStates are the states of apps by the state of their latest deployment.
+var appStates = map[meroxa.DeploymentState]meroxa.ApplicationState{
+	meroxa.DeploymentStateDeploying:        meroxa.ApplicationStateDeploying,
+	meroxa.DeploymentStateDeployed:         meroxa.ApplicationStateRunning,
+	meroxa.DeploymentStateDeployingError:   meroxa.ApplicationStateFailed,
+	meroxa.DeploymentStateRollingBack:      meroxa.ApplicationStateDegraded,
+	meroxa.DeploymentStateRollingBackError: meroxa.ApplicationStateFailed,
+}
+
+// specConnector is a connector of the spec of a deployment.
+type specConnector struct {
+	Type       string `json:"type"`
+	Resource   string `json:"resource"`
+	Collection string `json:"collection"`
+}
+
+// specConnectors returns the connectors of the spec of a deployment.
+func specConnectors(spec map[string]interface{}) []specConnector {
+	var s struct {
+		Connectors []specConnector `json:"connectors"`
+	}
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil
+	}
+	_ = json.Unmarshal(b, &s)
+	return s.Connectors
+}
+
+func (s *Server) listApps(w http.ResponseWriter, _ *http.Request) {
+	apps := make([]*meroxa.Application, 0, len(s.apps.entities))
+	for _, e := range s.apps.entities {
+		apps = append(apps, s.renderApp(e))
+	}
+	writeJSON(w, http.StatusOK, apps)
+}
+
+func (s *Server) getApp(w http.ResponseWriter, r *http.Request) {
+	e := s.apps.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindApp, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.renderApp(e))
+}
+
+// renderApp returns an app in the state of its latest deployment, with the resources and connectors it deployed.
+func (s *Server) renderApp(e *entity[meroxa.Application]) *meroxa.Application {
+	a := render(s, s.apps, e)
+	deployments := s.appDeployments(e)
+	if len(deployments) == 0 {
+		return a
+	}
+
+	a.Deployments = nil
+	for _, d := range deployments {
+		a.Deployments = append(a.Deployments, meroxa.EntityIdentifier{UUID: d.uuid})
+	}
+	latest := render(s, s.deployments, deployments[len(deployments)-1])
+	a.GitSha = latest.GitSha
+	a.Status.State = appStates[latest.Status.State]
+	a.Status.Details = latest.Status.Details
+
+	a.Resources, a.Connectors = nil, nil
+	for _, c := range specConnectors(latest.Spec) {
+		res := meroxa.ApplicationResource{
+			EntityIdentifier: meroxa.EntityIdentifier{Name: c.Resource},
+			Collection:       meroxa.ResourceCollection{Name: c.Collection},
+		}
+		if c.Type == string(meroxa.ConnectorTypeSource) {
+			res.Collection.Source = "true"
+		} else {
+			res.Collection.Destination = "true"
+		}
+		if r := s.resources.get(c.Resource); r != nil {
+			res.UUID = r.uuid
+			res.ResourceType = string(r.value.Type)
+			res.Status = string(render(s, s.resources, r).Status.State)
+		}
+		a.Resources = append(a.Resources, res)
+		a.Connectors = append(a.Connectors, meroxa.EntityDetails{
+			EntityIdentifier: meroxa.EntityIdentifier{Name: a.Name + "-" + c.Resource + "-" + c.Type},
+			ResourceUUID:     res.UUID,
+			ResourceType:     c.Type,
+			Status:           string(a.Status.State),
+		})
+	}
+	return a
+}
+
+func (s *Server) createApp(w http.ResponseWriter, r *http.Request) {
+	var in meroxa.CreateApplicationInput
+	if !decode(w, r, &in) {
+		return
+	}
+	if a, ok := s.addApp(w, &in); ok {
+		writeJSON(w, http.StatusOK, a)
+	}
+}
+
+func (s *Server) addApp(w http.ResponseWriter, in *meroxa.CreateApplicationInput) (*meroxa.Application, bool) {
+	details := make(map[string][]string)
+	required(details, "name", in.Name)
+	required(details, "language", in.Language)
+	if len(details) > 0 {
+		writeInvalid(w, details)
+		return nil, false
+	}
+	if s.apps.get(in.Name) != nil {
+		writeConflict(w, KindApp, in.Name)
+		return nil, false
+	}
+	env, ok := s.environment(w, in.Environment)
+	if !ok {
+		return nil, false
+	}
+
+	now := s.now().UTC()
+	a := &meroxa.Application{
+		Name:        in.Name,
+		Language:    in.Language,
+		GitSha:      in.GitSha,
+		Environment: env,
+		Pipeline:    meroxa.EntityDetails{EntityIdentifier: in.Pipeline},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	e := s.apps.add(in.Name, a, timeline{since: now, states: []string{string(meroxa.ApplicationStateInitialized)}})
+	a.UUID = e.uuid
+	return s.renderApp(e), true
+}
+
+func (s *Server) deleteApp(w http.ResponseWriter, r *http.Request) {
+	e := s.apps.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindApp, vars(r, "id"))
+		return
+	}
+	for _, d := range s.appDeployments(e) {
+		s.deployments.remove(d)
+	}
+	s.apps.remove(e)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getAppLogs(w http.ResponseWriter, r *http.Request) {
+	e := s.apps.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindApp, vars(r, "id"))
+		return
+	}
+	var lines []logLine
+	for _, d := range s.appDeployments(e) {
+		lines = append(lines, timelineLogs(s, "deployment "+d.uuid, d.timeline)...)
+	}
+	writeJSON(w, http.StatusOK, s.logs(e.name, lines))
+}
+
+// appDeployments returns the deployments of an app, the latest one last.
+func (s *Server) appDeployments(app *entity[meroxa.Application]) []*entity[meroxa.Deployment] {
+	var deployments []*entity[meroxa.Deployment]
+	for _, d := range s.deployments.entities {
+		if d.value.Application.UUID == app.uuid {
+			deployments = append(deployments, d)
+		}
+	}
+	return deployments
+}
+
+func (s *Server) createDeployment(w http.ResponseWriter, r *http.Request) {
+	app := s.apps.get(vars(r, "id"))
+	if app == nil {
+		writeNotFound(w, KindApp, vars(r, "id"))
+		return
+	}
+	var in meroxa.CreateDeploymentInput
+	if !decode(w, r, &in) {
+		return
+	}
+	details := make(map[string][]string)
+	required(details, "git_sha", in.GitSha)
+	if len(details) > 0 {
+		writeInvalid(w, details)
+		return
+	}
+
+	for _, d := range s.appDeployments(app) {
+		if render(s, s.deployments, d).Status.State == meroxa.DeploymentStateDeploying {
+			writeError(w, http.StatusConflict, "conflict", "app \""+app.name+"\" is already being deployed")
+			return
+		}
+	}
+
+	d := &meroxa.Deployment{
+		GitSha:      in.GitSha,
+		Application: meroxa.EntityIdentifier{UUID: app.uuid, Name: app.name},
+		CreatedAt:   s.now().UTC(),
+		Spec:        in.Spec,
+		SpecVersion: in.SpecVersion,
+		CreatedBy:   s.user.Email,
+	}
+	tl := s.newTimeline(KindDeployment, app.name, deploymentStates...)
+	// Deployments fail like they would on the platform when the resources of their spec don't exist.
+	for _, c := range specConnectors(in.Spec) {
+		if s.resources.get(c.Resource) == nil {
+			tl.states[len(tl.states)-1] = string(meroxa.DeploymentStateDeployingError)
+			tl.details = "could not find resource \"" + c.Resource + "\""
+			break
+		}
+	}
+	e := s.deployments.add("", d, tl)
+	d.UUID = e.uuid
+	app.value.UpdatedAt = d.CreatedAt
+	writeJSON(w, http.StatusOK, render(s, s.deployments, e))
+}
+
+func (s *Server) getLatestDeployment(w http.ResponseWriter, r *http.Request) {
+	app := s.apps.get(vars(r, "id"))
+	if app == nil {
+		writeNotFound(w, KindApp, vars(r, "id"))
+		return
+	}
+	deployments := s.appDeployments(app)
+	if len(deployments) == 0 {
+		writeError(w, http.StatusNotFound, "not_found", "app \""+app.name+"\" has no deployments")
+		return
+	}
+	writeJSON(w, http.StatusOK, render(s, s.deployments, deployments[len(deployments)-1]))
+}
+
+func (s *Server) getDeployment(w http.ResponseWriter, r *http.Request) {
+	app := s.apps.get(vars(r, "id"))
+	if app == nil {
+		writeNotFound(w, KindApp, vars(r, "id"))
+		return
+	}
+	for _, d := range s.appDeployments(app) {
+		if d.uuid == vars(r, "deployment") {
+			writeJSON(w, http.StatusOK, render(s, s.deployments, d))
+			return
+		}
+	}
+	writeNotFound(w, KindDeployment, vars(r, "deployment"))
+}
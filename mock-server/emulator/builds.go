@@ -0,0 +1,163 @@
+package emulator
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+// uploadsPath is where sources are uploaded to and downloaded from, as S3 would with presigned URLs.
+const uploadsPath = "/uploads"
+
+// maxUploadSize is the largest source that can be uploaded.
+const maxUploadSize = 512 << 20
+
+var buildStates = []string{"pending", "building", "complete"}
+
+// upload is the source of an app uploaded to build it.
+type upload struct {
+	data     []byte
+	uploaded bool
+}
+
+func (s *Server) createSource(w http.ResponseWriter, r *http.Request) {
+	var in meroxa.CreateSourceInputV2
+	if !decode(w, r, &in) {
+		return
+	}
+	env, ok := s.environment(w, in.Environment)
+	if !ok {
+		return
+	}
+
+	id := s.newUUID()
+	s.uploads[id] = &upload{}
+	u := baseURL(r) + uploadsPath + "/" + id
+	writeJSON(w, http.StatusOK, meroxa.Source{GetUrl: u, PutUrl: u, Environment: env})
+}
+
+func (s *Server) putUpload(w http.ResponseWriter, r *http.Request) {
+	u, ok := s.uploads[vars(r, "id")]
+	if !ok {
+		writeNotFound(w, "source", vars(r, "id"))
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxUploadSize+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "could not read source: "+err.Error())
+		return
+	}
+	if len(data) > maxUploadSize {
+		writeError(w, http.StatusRequestEntityTooLarge, "too_large", "source is larger than 512MB")
+		return
+	}
+	u.data, u.uploaded = data, true
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getUpload(w http.ResponseWriter, r *http.Request) {
+	u, ok := s.uploads[vars(r, "id")]
+	if !ok || !u.uploaded {
+		writeNotFound(w, "source", vars(r, "id"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(u.data)
+}
+
+func (s *Server) createBuild(w http.ResponseWriter, r *http.Request) {
+	var in meroxa.CreateBuildInput
+	if !decode(w, r, &in) {
+		return
+	}
+	details := make(map[string][]string)
+	required(details, "source_blob.url", in.SourceBlob.Url)
+	if len(details) > 0 {
+		writeInvalid(w, details)
+		return
+	}
+	env, ok := s.environment(w, in.Environment)
+	if !ok {
+		return
+	}
+
+	now := s.now().UTC()
+	b := &meroxa.Build{
+		CreatedAt:   now.Format(time.RFC3339),
+		UpdatedAt:   now.Format(time.RFC3339),
+		SourceBlob:  in.SourceBlob,
+		Environment: env,
+	}
+	tl := s.newTimeline(KindBuild, "", buildStates...)
+	// Builds of sources that weren't uploaded to the emulator fail like they would on the platform.
+	if prefix := baseURL(r) + uploadsPath + "/"; strings.HasPrefix(in.SourceBlob.Url, prefix) {
+		if u, ok := s.uploads[strings.TrimPrefix(in.SourceBlob.Url, prefix)]; !ok || !u.uploaded {
+			tl.states[len(tl.states)-1] = "error"
+			tl.details = "source " + in.SourceBlob.Url + " was not uploaded"
+		}
+	}
+	e := s.builds.add("", b, tl)
+	b.Uuid = e.uuid
+	b.Image = "registry.emulator.meroxa.io/builds/" + e.uuid + ":latest"
+	writeJSON(w, http.StatusOK, render(s, s.builds, e))
+}
+
+func (s *Server) getBuild(w http.ResponseWriter, r *http.Request) {
+	e := s.builds.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindBuild, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, render(s, s.builds, e))
+}
+
+func (s *Server) getBuildLogs(w http.ResponseWriter, r *http.Request) {
+	e := s.builds.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindBuild, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.logs(e.uuid, timelineLogs(s, "build "+e.uuid, e.timeline)))
+}
+
+// logLine is a line of the logs of an entity.
+type logLine struct {
+	source string
+	log    string
+	at     time.Time
+}
+
+// timelineLogs returns a line of logs for each state source reached.
+func timelineLogs(s *Server, source string, tl timeline) []logLine {
+	var lines []logLine
+	for _, st := range tl.reached(s.now().UTC(), s.step) {
+		l := logLine{source: source, log: source + " is " + st.name, at: st.since}
+		if st.details != "" {
+			l.log += ": " + st.details
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+func (s *Server) logs(query string, lines []logLine) *meroxa.Logs {
+	now := s.now().UTC()
+	logs := &meroxa.Logs{
+		Data: make([]meroxa.LogData, 0, len(lines)),
+		Metadata: meroxa.Metadata{
+			Limit:  1000,
+			Query:  query,
+			Source: "emulator",
+			Start:  now.Add(-time.Hour),
+			End:    now,
+		},
+	}
+	for _, l := range lines {
+		logs.Data = append(logs.Data, meroxa.LogData{Log: l.log, Source: l.source, Timestamp: l.at})
+	}
+	return logs
+}
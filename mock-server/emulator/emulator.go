@@ -0,0 +1,301 @@
+// Package emulator emulates the Meroxa API locally, so that the CLI can be exercised end-to-end without an account.
+//
+// Entities move through their states over time as they would on the platform, e.g. a build is pending, then
+// building, then complete. A Scenario can inject faults, change the state entities end up in and seed entities.
+package emulator
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+// DefaultStep is how long entities stay in each of their states before moving to the next one.
+const DefaultStep = time.Second
+
+// Options configure a Server.
+type Options struct {
+	// Step is how long entities stay in each of their states, 0 moves them to their final state right away.
+	Step time.Duration
+	// Scenario injects faults, overrides the final state of entities and seeds entities.
+	Scenario *Scenario
+	// Now returns the current time, used to move entities through their states. It defaults to time.Now.
+	Now func() time.Time
+	// NewUUID returns the UUID of a new entity. It defaults to random UUIDs.
+	NewUUID func() string
+}
+
+// Server is an http.Handler emulating the Meroxa API.
+type Server struct {
+	mu       sync.Mutex
+	step     time.Duration
+	now      func() time.Time
+	newUUID  func() string
+	scenario *Scenario
+	router   *mux.Router
+
+	account meroxa.Account
+	user    meroxa.User
+
+	resources    *collection[meroxa.Resource]
+	connectors   *collection[meroxa.Connector]
+	pipelines    *collection[meroxa.Pipeline]
+	apps         *collection[meroxa.Application]
+	deployments  *collection[meroxa.Deployment]
+	builds       *collection[meroxa.Build]
+	environments *collection[meroxa.Environment]
+	flinkJobs    *collection[meroxa.FlinkJob]
+	functions    *collection[meroxa.Function]
+
+	// uploads are the sources created to be uploaded with their PUT URL, by source UUID.
+	uploads map[string]*upload
+	// idempotent are the responses to requests with an idempotency key, sent again when a request is retried.
+	idempotent map[string]*recordedResponse
+}
+
+// New returns a Server emulating the Meroxa API, seeded with the entities of the scenario.
+func New(opts Options) (*Server, error) {
+	s := &Server{
+		step:       opts.Step,
+		now:        opts.Now,
+		newUUID:    opts.NewUUID,
+		scenario:   opts.Scenario,
+		uploads:    make(map[string]*upload),
+		idempotent: make(map[string]*recordedResponse),
+		user: meroxa.User{
+			Username:   "emulator",
+			Email:      "emulator@meroxa.io",
+			GivenName:  "Meroxa",
+			FamilyName: "Emulator",
+			Verified:   true,
+		},
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	if s.newUUID == nil {
+		s.newUUID = uuid.NewString
+	}
+	s.account = meroxa.Account{UUID: s.newUUID(), Name: "emulator", CompanyName: "Meroxa"}
+	s.user.UUID = s.newUUID()
+	if s.scenario == nil {
+		s.scenario = &Scenario{}
+	}
+	if s.scenario.Step != nil {
+		s.step = time.Duration(*s.scenario.Step)
+	}
+	s.user.LastLogin = s.now().UTC()
+
+	s.resources = newCollection(KindResource, s.newUUID, func(r *meroxa.Resource, st state) {
+		r.Status.State = meroxa.ResourceState(st.name)
+		r.Status.Details = st.details
+		r.Status.LastUpdatedAt = st.since
+	})
+	s.connectors = newCollection(KindConnector, s.newUUID, func(c *meroxa.Connector, st state) {
+		c.State = meroxa.ConnectorState(st.name)
+		c.Trace = st.details
+	})
+	s.pipelines = newCollection(KindPipeline, s.newUUID, func(p *meroxa.Pipeline, st state) {
+		p.State = meroxa.PipelineState(st.name)
+	})
+	s.apps = newCollection(KindApp, s.newUUID, func(a *meroxa.Application, st state) {
+		a.Status.State = meroxa.ApplicationState(st.name)
+		a.Status.Details = st.details
+	})
+	s.deployments = newCollection(KindDeployment, s.newUUID, func(d *meroxa.Deployment, st state) {
+		d.Status.State = meroxa.DeploymentState(st.name)
+		d.Status.Details = st.details
+	})
+	s.builds = newCollection(KindBuild, s.newUUID, func(b *meroxa.Build, st state) {
+		b.Status.State = st.name
+		b.Status.Details = st.details
+		b.UpdatedAt = st.since.Format(time.RFC3339)
+	})
+	s.environments = newCollection(KindEnvironment, s.newUUID, func(e *meroxa.Environment, st state) {
+		e.Status.State = meroxa.EnvironmentState(st.name)
+		e.Status.Details = st.details
+	})
+	s.flinkJobs = newCollection(KindFlink, s.newUUID, func(j *meroxa.FlinkJob, st state) {
+		j.Status.LifecycleState = meroxa.FlinkJobLifecycleState(st.name)
+		j.Status.Details = st.details
+		switch j.Status.LifecycleState {
+		case meroxa.FlinkJobLifecycleStateStable:
+			j.Status.State = string(meroxa.FlinkJobDesiredStateRunning)
+			j.Status.ReconciliationState = meroxa.FlinkJobReconciliationStateDeployed
+			j.Status.ManagerDeploymentState = meroxa.FlinkJobManagerDeploymentStateReady
+		case meroxa.FlinkJobLifecycleStateFailed, meroxa.FlinkJobLifecycleStateDoa:
+			j.Status.State = string(meroxa.FlinkJobLifecycleStateFailed)
+			j.Status.ReconciliationState = meroxa.FlinkJobReconciliationStateDeployed
+			j.Status.ManagerDeploymentState = meroxa.FlinkJobManagerDeploymentStateError
+		default:
+			j.Status.State = string(meroxa.FlinkJobDesiredStateRunning)
+			j.Status.ReconciliationState = meroxa.FlinkJobReconciliationStateUpgrading
+			j.Status.ManagerDeploymentState = meroxa.FlinkJobManagerDeploymentStateDeploying
+		}
+	})
+	s.functions = newCollection(KindFunction, s.newUUID, func(f *meroxa.Function, st state) {
+		f.Status.State = meroxa.FunctionState(st.name)
+		f.Status.Details = st.details
+	})
+
+	s.router = s.routes()
+	if err := s.seed(s.scenario.Seed); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.fault(w, r) {
+		return
+	}
+	s.serveIdempotent(w, r)
+}
+
+func (s *Server) routes() *mux.Router {
+	r := mux.NewRouter()
+	r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "route not found: "+r.Method+" "+r.URL.Path)
+	})
+	r.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed: "+r.Method+" "+r.URL.Path)
+	})
+
+	v1 := r.PathPrefix("/v1").Subrouter()
+	v2 := r.PathPrefix("/v2").Subrouter()
+
+	v1.HandleFunc("/accounts", s.listAccounts).Methods(http.MethodGet)
+	v1.HandleFunc("/users/me", s.getUser).Methods(http.MethodGet)
+	v1.HandleFunc("/transforms", s.listTransforms).Methods(http.MethodGet)
+	v1.HandleFunc("/resource-types", s.listResourceTypes).Methods(http.MethodGet)
+	v2.HandleFunc("/resource-types", s.listResourceTypesV2).Methods(http.MethodGet)
+
+	v1.HandleFunc("/resources", s.listResources).Methods(http.MethodGet)
+	v1.HandleFunc("/resources", s.createResource).Methods(http.MethodPost)
+	v1.HandleFunc("/resources/{id}", s.getResource).Methods(http.MethodGet)
+	v1.HandleFunc("/resources/{id}", s.updateResource).Methods(http.MethodPatch)
+	v1.HandleFunc("/resources/{id}", s.deleteResource).Methods(http.MethodDelete)
+	v1.HandleFunc("/resources/{id}/actions", s.resourceAction).Methods(http.MethodPost)
+	v1.HandleFunc("/resources/{id}/introspection", s.introspectResource).Methods(http.MethodGet)
+
+	v1.HandleFunc("/connectors", s.listConnectors).Methods(http.MethodGet)
+	v1.HandleFunc("/connectors", s.createConnector).Methods(http.MethodPost)
+	v1.HandleFunc("/connectors/{id}", s.getConnector).Methods(http.MethodGet)
+	v1.HandleFunc("/connectors/{id}", s.updateConnector).Methods(http.MethodPatch)
+	v1.HandleFunc("/connectors/{id}", s.deleteConnector).Methods(http.MethodDelete)
+	v1.HandleFunc("/connectors/{id}/status", s.updateConnectorStatus).Methods(http.MethodPost)
+
+	v1.HandleFunc("/pipelines", s.listPipelines).Methods(http.MethodGet)
+	v1.HandleFunc("/pipelines", s.createPipeline).Methods(http.MethodPost)
+	v1.HandleFunc("/pipelines/{id}", s.getPipeline).Methods(http.MethodGet)
+	v1.HandleFunc("/pipelines/{id}", s.updatePipeline).Methods(http.MethodPatch)
+	v1.HandleFunc("/pipelines/{id}", s.deletePipeline).Methods(http.MethodDelete)
+	v1.HandleFunc("/pipelines/{id}/status", s.updatePipelineStatus).Methods(http.MethodPost)
+	v1.HandleFunc("/pipelines/{id}/connectors", s.listPipelineConnectors).Methods(http.MethodGet)
+
+	v1.HandleFunc("/applications", s.listApps).Methods(http.MethodGet)
+	v1.HandleFunc("/applications", s.createApp).Methods(http.MethodPost)
+	v2.HandleFunc("/applications", s.createApp).Methods(http.MethodPost)
+	v1.HandleFunc("/applications/{id}", s.getApp).Methods(http.MethodGet)
+	v1.HandleFunc("/applications/{id}", s.deleteApp).Methods(http.MethodDelete)
+	v2.HandleFunc("/applications/{id}/logs", s.getAppLogs).Methods(http.MethodGet)
+	v1.HandleFunc("/applications/{id}/deployments", s.createDeployment).Methods(http.MethodPost)
+	v1.HandleFunc("/applications/{id}/deployments/latest", s.getLatestDeployment).Methods(http.MethodGet)
+	v1.HandleFunc("/applications/{id}/deployments/{deployment}", s.getDeployment).Methods(http.MethodGet)
+
+	v1.HandleFunc("/sources", s.createSource).Methods(http.MethodPost)
+	v2.HandleFunc("/sources", s.createSource).Methods(http.MethodPost)
+	r.HandleFunc(uploadsPath+"/{id}", s.putUpload).Methods(http.MethodPut)
+	r.HandleFunc(uploadsPath+"/{id}", s.getUpload).Methods(http.MethodGet)
+
+	v1.HandleFunc("/builds", s.createBuild).Methods(http.MethodPost)
+	v1.HandleFunc("/builds/{id}", s.getBuild).Methods(http.MethodGet)
+	v2.HandleFunc("/builds/{id}/logs", s.getBuildLogs).Methods(http.MethodGet)
+
+	v1.HandleFunc("/environments", s.listEnvironments).Methods(http.MethodGet)
+	v1.HandleFunc("/environments", s.createEnvironment).Methods(http.MethodPost)
+	v1.HandleFunc("/environments/{id}", s.getEnvironment).Methods(http.MethodGet)
+	v1.HandleFunc("/environments/{id}", s.updateEnvironment).Methods(http.MethodPatch)
+	v1.HandleFunc("/environments/{id}", s.deleteEnvironment).Methods(http.MethodDelete)
+	v1.HandleFunc("/environments/{id}/actions", s.environmentAction).Methods(http.MethodPost)
+
+	v1.HandleFunc("/flink-jobs", s.listFlinkJobs).Methods(http.MethodGet)
+	v1.HandleFunc("/flink-jobs", s.createFlinkJob).Methods(http.MethodPost)
+	v1.HandleFunc("/flink-jobs/{id}", s.getFlinkJob).Methods(http.MethodGet)
+	v1.HandleFunc("/flink-jobs/{id}", s.deleteFlinkJob).Methods(http.MethodDelete)
+	v2.HandleFunc("/flink-jobs/{id}/logs", s.getFlinkJobLogs).Methods(http.MethodGet)
+
+	v1.HandleFunc("/functions", s.listFunctions).Methods(http.MethodGet)
+	v1.HandleFunc("/functions", s.createFunction).Methods(http.MethodPost)
+	v1.HandleFunc("/functions/{id}", s.getFunction).Methods(http.MethodGet)
+	v1.HandleFunc("/functions/{id}", s.deleteFunction).Methods(http.MethodDelete)
+
+	return r
+}
+
+// apiError is the body of the responses of failed requests, as decoded by meroxa-go.
+type apiError struct {
+	Code    string              `json:"code"`
+	Message string              `json:"message"`
+	Details map[string][]string `json:"details,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, apiError{Code: code, Message: message})
+}
+
+func writeNotFound(w http.ResponseWriter, kind Kind, nameOrUUID string) {
+	writeError(w, http.StatusNotFound, "not_found", "could not find "+kind.noun()+" \""+nameOrUUID+"\"")
+}
+
+func writeConflict(w http.ResponseWriter, kind Kind, name string) {
+	writeError(w, http.StatusConflict, "conflict", kind.noun()+" \""+name+"\" already exists")
+}
+
+// writeInvalid responds with the invalid fields of a request, e.g. {"name": ["is required"]}.
+func writeInvalid(w http.ResponseWriter, details map[string][]string) {
+	writeJSON(w, http.StatusUnprocessableEntity, apiError{Code: "invalid_input", Message: "invalid input", Details: details})
+}
+
+// decode decodes the JSON body of r into v, responding with an error when it's invalid.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.Body == nil || r.ContentLength == 0 {
+		return true
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// baseURL is the URL of the emulator as seen by the client of r.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+func required(details map[string][]string, field, value string) {
+	if strings.TrimSpace(value) == "" {
+		details[field] = append(details[field], "is required")
+	}
+}
+
+func vars(r *http.Request, name string) string {
+	return mux.Vars(r)[name]
+}
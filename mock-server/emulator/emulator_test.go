@@ -0,0 +1,439 @@
+package emulator
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+// clock is the time of an emulator, moved forward by tests.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestServer(t *testing.T, scenario string) (*httptest.Server, meroxa.Client, *clock) {
+	t.Helper()
+	c := &clock{now: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)}
+	opts := Options{Step: time.Second, Now: c.Now}
+	if scenario != "" {
+		s, err := ParseScenario([]byte(scenario))
+		if err != nil {
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+		opts.Scenario = s
+	}
+
+	s, err := New(opts)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	client, err := meroxa.New(meroxa.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	return server, client, c
+}
+
+func TestResourceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	_, client, c := newTestServer(t, "")
+
+	in := &meroxa.CreateResourceInput{Name: "pg", Type: meroxa.ResourceTypePostgres, URL: "postgres://localhost:5432/db"}
+	r, err := client.CreateResource(ctx, in)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if r.UUID == "" || r.Status.State != meroxa.ResourceStatePending {
+		t.Fatalf("expected a pending resource with a UUID, got %+v", r)
+	}
+
+	for _, want := range []meroxa.ResourceState{meroxa.ResourceStateStarting, meroxa.ResourceStateReady, meroxa.ResourceStateReady} {
+		c.Advance(time.Second)
+		if r, err = client.GetResourceByNameOrID(ctx, r.UUID); err != nil {
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+		if r.Status.State != want {
+			t.Fatalf("expected resource to be %s, got %s", want, r.Status.State)
+		}
+	}
+
+	if _, err = client.CreateResource(ctx, in); err == nil || !strings.Contains(err.Error(), `resource "pg" already exists`) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	rr, err := client.ListResources(ctx)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if len(rr) != 1 || rr[0].Name != "pg" {
+		t.Fatalf("expected resource pg to be listed, got %+v", rr)
+	}
+
+	if err = client.DeleteResource(ctx, "pg"); err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if _, err = client.GetResourceByNameOrID(ctx, "pg"); err == nil || !strings.Contains(err.Error(), `could not find resource "pg"`) {
+		t.Fatalf("expected resource to be deleted, got %v", err)
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	_, client, _ := newTestServer(t, "")
+
+	_, err := client.CreateResource(context.Background(), &meroxa.CreateResourceInput{Name: "pg"})
+	if err == nil || !strings.Contains(err.Error(), "type") || !strings.Contains(err.Error(), "url") {
+		t.Fatalf("expected the missing fields to be reported, got %v", err)
+	}
+}
+
+func TestPipelinesAndConnectors(t *testing.T) {
+	ctx := context.Background()
+	_, client, c := newTestServer(t, `
+seed:
+  resources:
+    - name: pg
+      type: postgres
+      url: postgres://localhost:5432/db
+`)
+
+	p, err := client.CreatePipeline(ctx, &meroxa.CreatePipelineInput{Name: "my-pipeline"})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if p, err = client.GetPipelineByName(ctx, "my-pipeline"); err != nil || p.State != meroxa.PipelineStateHealthy {
+		t.Fatalf("expected a healthy pipeline, got %+v (%v)", p, err)
+	}
+
+	con, err := client.CreateConnector(ctx, &meroxa.CreateConnectorInput{
+		ResourceName: "pg",
+		PipelineName: "my-pipeline",
+		Type:         meroxa.ConnectorTypeSource,
+		Input:        "public.users",
+	})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	c.Advance(time.Second)
+	if con, err = client.GetConnectorByNameOrID(ctx, con.Name); err != nil || con.State != meroxa.ConnectorStateRunning {
+		t.Fatalf("expected a running connector, got %+v (%v)", con, err)
+	}
+
+	if con, err = client.UpdateConnectorStatus(ctx, con.Name, meroxa.ActionPause); err != nil || con.State != meroxa.ConnectorStatePaused {
+		t.Fatalf("expected a paused connector, got %+v (%v)", con, err)
+	}
+
+	cc, err := client.ListPipelineConnectors(ctx, "my-pipeline")
+	if err != nil || len(cc) != 1 {
+		t.Fatalf("expected the connector of the pipeline, got %+v (%v)", cc, err)
+	}
+	if err = client.DeletePipeline(ctx, "my-pipeline"); err == nil {
+		t.Fatal("expected pipelines with connectors not to be deleted")
+	}
+}
+
+func TestDeployApp(t *testing.T) {
+	ctx := context.Background()
+	_, client, c := newTestServer(t, `
+seed:
+  environments:
+    - name: my-env
+  resources:
+    - name: pg
+      type: postgres
+      url: postgres://localhost:5432/db
+`)
+	env := &meroxa.EntityIdentifier{Name: "my-env"}
+
+	app, err := client.CreateApplicationV2(ctx, &meroxa.CreateApplicationInput{Name: "my-app", Language: "golang", Environment: env})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if app.Status.State != meroxa.ApplicationStateInitialized || app.Environment.UUID == "" {
+		t.Fatalf("expected an initialized app in my-env, got %+v", app)
+	}
+
+	source, err := client.CreateSourceV2(ctx, &meroxa.CreateSourceInputV2{Environment: env})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	req, _ := http.NewRequest(http.MethodPut, source.PutUrl, bytes.NewBufferString("source"))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the source to be uploaded, got %v (%v)", resp, err)
+	}
+	resp.Body.Close()
+
+	build, err := client.CreateBuild(ctx, &meroxa.CreateBuildInput{SourceBlob: meroxa.SourceBlob{Url: source.GetUrl}})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	for _, want := range []string{"pending", "building", "complete"} {
+		if build, err = client.GetBuild(ctx, build.Uuid); err != nil || build.Status.State != want {
+			t.Fatalf("expected build to be %s, got %+v (%v)", want, build, err)
+		}
+		c.Advance(time.Second)
+	}
+	if build.Image == "" {
+		t.Fatal("expected the build to have an image")
+	}
+
+	d, err := client.CreateDeployment(ctx, &meroxa.CreateDeploymentInput{
+		Application: meroxa.EntityIdentifier{Name: "my-app"},
+		GitSha:      "abc",
+		SpecVersion: "0.2.0",
+		Spec: map[string]interface{}{
+			"connectors": []interface{}{
+				map[string]interface{}{"type": "source", "resource": "pg", "collection": "users"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if d.Status.State != meroxa.DeploymentStateDeploying {
+		t.Fatalf("expected deployment to be deploying, got %s", d.Status.State)
+	}
+	if app, err = client.GetApplication(ctx, "my-app"); err != nil || app.Status.State != meroxa.ApplicationStateDeploying {
+		t.Fatalf("expected app to be deploying, got %+v (%v)", app, err)
+	}
+
+	c.Advance(time.Second)
+	if d, err = client.GetLatestDeployment(ctx, "my-app"); err != nil || d.Status.State != meroxa.DeploymentStateDeployed {
+		t.Fatalf("expected deployment to be deployed, got %+v (%v)", d, err)
+	}
+	app, err = client.GetApplication(ctx, app.UUID)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if app.Status.State != meroxa.ApplicationStateRunning || app.GitSha != "abc" || len(app.Resources) != 1 {
+		t.Fatalf("expected a running app with its resources, got %+v", app)
+	}
+
+	logs, err := client.GetApplicationLogsV2(ctx, "my-app")
+	if err != nil || len(logs.Data) != 2 {
+		t.Fatalf("expected logs of the deployment, got %+v (%v)", logs, err)
+	}
+}
+
+func TestDeploymentFailures(t *testing.T) {
+	ctx := context.Background()
+	_, client, c := newTestServer(t, `
+seed:
+  apps:
+    - name: my-app
+      language: golang
+`)
+
+	build, err := client.CreateBuild(ctx, &meroxa.CreateBuildInput{SourceBlob: meroxa.SourceBlob{Url: "https://example.com/source.tar.gz"}})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	c.Advance(2 * time.Second)
+	if build, err = client.GetBuild(ctx, build.Uuid); err != nil || build.Status.State != "complete" {
+		t.Fatalf("expected builds of other sources to complete, got %+v (%v)", build, err)
+	}
+
+	source, err := client.CreateSource(ctx)
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	build, err = client.CreateBuild(ctx, &meroxa.CreateBuildInput{SourceBlob: meroxa.SourceBlob{Url: source.GetUrl}})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	c.Advance(2 * time.Second)
+	if build, err = client.GetBuild(ctx, build.Uuid); err != nil || build.Status.State != "error" {
+		t.Fatalf("expected builds of sources that weren't uploaded to fail, got %+v (%v)", build, err)
+	}
+
+	d, err := client.CreateDeployment(ctx, &meroxa.CreateDeploymentInput{
+		Application: meroxa.EntityIdentifier{Name: "my-app"},
+		GitSha:      "abc",
+		Spec: map[string]interface{}{
+			"connectors": []interface{}{map[string]interface{}{"type": "source", "resource": "missing"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	c.Advance(time.Second)
+	d, err = client.GetDeployment(ctx, "my-app", d.UUID)
+	if err != nil || d.Status.State != meroxa.DeploymentStateDeployingError || !strings.Contains(d.Status.Details, "missing") {
+		t.Fatalf("expected deployments of missing resources to fail, got %+v (%v)", d, err)
+	}
+}
+
+func TestScenarioOutcomes(t *testing.T) {
+	ctx := context.Background()
+	_, client, c := newTestServer(t, `
+outcomes:
+  - kind: environment
+    name: broken-*
+    state: provisioning_error
+    details: quota exceeded
+`)
+
+	for name, want := range map[string]meroxa.EnvironmentState{
+		"broken-env": meroxa.EnvironmentStateProvisioningError,
+		"my-env":     meroxa.EnvironmentStateReady,
+	} {
+		env, err := client.CreateEnvironment(ctx, &meroxa.CreateEnvironmentInput{Name: name})
+		if err != nil {
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+		if env.Status.State != meroxa.EnvironmentStatePreflightSuccess {
+			t.Fatalf("expected preflight checks of %s to pass, got %s", name, env.Status.State)
+		}
+		c.Advance(2 * time.Second)
+		env, err = client.GetEnvironment(ctx, name)
+		if err != nil || env.Status.State != want {
+			t.Fatalf("expected environment %s to be %s, got %+v (%v)", name, want, env, err)
+		}
+	}
+}
+
+func TestScenarioFaults(t *testing.T) {
+	ctx := context.Background()
+	server, client, _ := newTestServer(t, `
+faults:
+  - method: GET
+    path: /v1/resources
+    status: 502
+    body: bad gateway
+    times: 2
+  - path: /v1/environments/*
+    status: 429
+    retry_after: "3"
+  - path: /v1/pipelines
+    drop: true
+`)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListResources(ctx); err == nil || !strings.Contains(err.Error(), "502 Bad Gateway") {
+			t.Fatalf("expected request %d to fail, got %v", i+1, err)
+		}
+	}
+	if _, err := client.ListResources(ctx); err != nil {
+		t.Fatalf("expected requests after the fault to succeed, got %q", err.Error())
+	}
+
+	resp, err := http.Get(server.URL + "/v1/environments/my-env")
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "3" {
+		t.Fatalf("expected a rate limit, got %d with Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+
+	if _, err = client.ListPipelines(ctx); err == nil {
+		t.Fatal("expected the connection to be dropped")
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	server, client, _ := newTestServer(t, `
+faults:
+  - method: POST
+    path: /v1/resources
+    status: 502
+    times: 1
+    processed: true
+`)
+
+	create := func() *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/v1/resources",
+			strings.NewReader(`{"name":"pg","type":"postgres","url":"postgres://localhost/db"}`))
+		req.Header.Set(IdempotencyKeyHeader, "key")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("not expected error, got %q", err.Error())
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// The response of the first request is lost, the resource is created anyway.
+	if resp := create(); resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected the fault, got %d", resp.StatusCode)
+	}
+	for i := 0; i < 2; i++ {
+		resp := create()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Idempotent-Replayed") != "true" {
+			t.Fatalf("expected the response to be replayed, got %d", resp.StatusCode)
+		}
+	}
+
+	rr, err := client.ListResources(context.Background())
+	if err != nil || len(rr) != 1 {
+		t.Fatalf("expected a single resource, got %+v (%v)", rr, err)
+	}
+}
+
+func TestParseScenario(t *testing.T) {
+	s, err := ParseScenario([]byte(`{"step": "250ms", "faults": [{"path": "/v1/*", "status": 503}]}`))
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if time.Duration(*s.Step) != 250*time.Millisecond || len(s.Faults) != 1 || s.Faults[0].Status != 503 {
+		t.Fatalf("unexpected scenario %+v", s)
+	}
+
+	for scenario, wantErr := range map[string]string{
+		"faults:\n  - status: 500":       "path is required",
+		"outcomes:\n  - kind: build":     "kind and state are required",
+		"step: 1":                        "invalid duration",
+		"unknown: true":                  "unknown field",
+		"faults:\n  - path: /v1/[builds": "invalid path",
+	} {
+		if _, err = ParseScenario([]byte(scenario)); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("expected error containing %q for %q, got %v", wantErr, scenario, err)
+		}
+	}
+}
+
+func TestSeedErrors(t *testing.T) {
+	s, err := ParseScenario([]byte("seed:\n  resources:\n    - name: pg\n      type: postgres\n      url: postgres://db\n      environment:\n        name: missing"))
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	if _, err = New(Options{Scenario: s}); err == nil || !strings.Contains(err.Error(), `could not seed resource "pg"`) {
+		t.Fatalf("expected a seed error, got %v", err)
+	}
+}
+
+func TestUnknownRoute(t *testing.T) {
+	server, _, _ := newTestServer(t, "")
+
+	resp, err := http.Get(server.URL + "/v1/unknown")
+	if err != nil {
+		t.Fatalf("not expected error, got %q", err.Error())
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
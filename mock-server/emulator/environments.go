@@ -0,0 +1,141 @@
+package emulator
+
+import (
+	"net/http"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var environmentStates = []string{
+	string(meroxa.EnvironmentStatePreflightSuccess),
+	string(meroxa.EnvironmentStateProvisioning),
+	string(meroxa.EnvironmentStateReady),
+}
+
+func (s *Server) listEnvironments(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, renderAll(s, s.environments))
+}
+
+func (s *Server) getEnvironment(w http.ResponseWriter, r *http.Request) {
+	e := s.environments.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindEnvironment, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, render(s, s.environments, e))
+}
+
+func (s *Server) createEnvironment(w http.ResponseWriter, r *http.Request) {
+	var in meroxa.CreateEnvironmentInput
+	if !decode(w, r, &in) {
+		return
+	}
+	if env, ok := s.addEnvironment(w, &in, s.newTimeline(KindEnvironment, in.Name, environmentStates...)); ok {
+		writeJSON(w, http.StatusOK, preflight(env))
+	}
+}
+
+// preflight returns env as responded when it's created, updated or repaired: preflight checks run first, and the
+// response tells how they went.
+func preflight(env *meroxa.Environment) *meroxa.Environment {
+	if env.Status.State != meroxa.EnvironmentStatePreflightError {
+		env.Status.State = meroxa.EnvironmentStatePreflightSuccess
+	}
+	return env
+}
+
+func (s *Server) addEnvironment(w http.ResponseWriter, in *meroxa.CreateEnvironmentInput, tl timeline) (*meroxa.Environment, bool) {
+	details := make(map[string][]string)
+	required(details, "name", in.Name)
+	if len(details) > 0 {
+		writeInvalid(w, details)
+		return nil, false
+	}
+	if s.environments.get(in.Name) != nil {
+		writeConflict(w, KindEnvironment, in.Name)
+		return nil, false
+	}
+
+	now := s.now().UTC()
+	env := &meroxa.Environment{
+		Name:          in.Name,
+		Provider:      in.Provider,
+		Region:        in.Region,
+		Type:          in.Type,
+		Configuration: in.Configuration,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if env.Provider == "" {
+		env.Provider = meroxa.EnvironmentProviderAws
+	}
+	if env.Region == "" {
+		env.Region = meroxa.EnvironmentRegionUsEast1
+	}
+	if env.Type == "" {
+		env.Type = meroxa.EnvironmentTypeSelfHosted
+	}
+	e := s.environments.add(in.Name, env, tl)
+	env.UUID = e.uuid
+	return render(s, s.environments, e), true
+}
+
+func (s *Server) updateEnvironment(w http.ResponseWriter, r *http.Request) {
+	e := s.environments.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindEnvironment, vars(r, "id"))
+		return
+	}
+	var in meroxa.UpdateEnvironmentInput
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Name != "" && in.Name != e.name {
+		if s.environments.get(in.Name) != nil {
+			writeConflict(w, KindEnvironment, in.Name)
+			return
+		}
+		e.name = in.Name
+		e.value.Name = in.Name
+	}
+	if in.Configuration != nil {
+		e.value.Configuration = in.Configuration
+	}
+	e.value.UpdatedAt = s.now().UTC()
+	e.timeline = s.newTimeline(KindEnvironment, e.name, string(meroxa.EnvironmentStatePreflightSuccess),
+		string(meroxa.EnvironmentStateUpdating), string(meroxa.EnvironmentStateReady))
+	writeJSON(w, http.StatusOK, preflight(render(s, s.environments, e)))
+}
+
+func (s *Server) deleteEnvironment(w http.ResponseWriter, r *http.Request) {
+	e := s.environments.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindEnvironment, vars(r, "id"))
+		return
+	}
+	// Environments are kept once deprovisioned, as the platform does.
+	e.value.UpdatedAt = s.now().UTC()
+	e.timeline = s.newTimeline(KindEnvironment, e.name,
+		string(meroxa.EnvironmentStateDeprovisioning), string(meroxa.EnvironmentStateDeprovisioned))
+	writeJSON(w, http.StatusOK, render(s, s.environments, e))
+}
+
+func (s *Server) environmentAction(w http.ResponseWriter, r *http.Request) {
+	e := s.environments.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindEnvironment, vars(r, "id"))
+		return
+	}
+	var in meroxa.RepairEnvironmentInput
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Action != meroxa.EnvironmentActionRepair {
+		writeInvalid(w, map[string][]string{"action": {"must be repair"}})
+		return
+	}
+	e.value.UpdatedAt = s.now().UTC()
+	e.timeline = s.newTimeline(KindEnvironment, e.name, string(meroxa.EnvironmentStatePreflightSuccess),
+		string(meroxa.EnvironmentStateRepairing), string(meroxa.EnvironmentStateReady))
+	writeJSON(w, http.StatusOK, preflight(render(s, s.environments, e)))
+}
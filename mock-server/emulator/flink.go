@@ -0,0 +1,137 @@
+package emulator
+
+import (
+	"net/http"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var flinkJobStates = []string{
+	string(meroxa.FlinkJobLifecycleStateCreated),
+	string(meroxa.FlinkJobLifecycleStateDeploying),
+	string(meroxa.FlinkJobLifecycleStateStable),
+}
+
+var functionStates = []string{
+	string(meroxa.FunctionStatePending),
+	string(meroxa.FunctionStateRunning),
+}
+
+func (s *Server) listFlinkJobs(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, renderAll(s, s.flinkJobs))
+}
+
+func (s *Server) getFlinkJob(w http.ResponseWriter, r *http.Request) {
+	e := s.flinkJobs.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindFlink, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, render(s, s.flinkJobs, e))
+}
+
+func (s *Server) createFlinkJob(w http.ResponseWriter, r *http.Request) {
+	var in meroxa.CreateFlinkJobInput
+	if !decode(w, r, &in) {
+		return
+	}
+	details := make(map[string][]string)
+	required(details, "name", in.Name)
+	required(details, "jar_url", in.JarURL)
+	if len(details) > 0 {
+		writeInvalid(w, details)
+		return
+	}
+	if s.flinkJobs.get(in.Name) != nil {
+		writeConflict(w, KindFlink, in.Name)
+		return
+	}
+
+	now := s.now().UTC()
+	j := &meroxa.FlinkJob{
+		Name:      in.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	e := s.flinkJobs.add(in.Name, j, s.newTimeline(KindFlink, in.Name, flinkJobStates...))
+	j.UUID = e.uuid
+	writeJSON(w, http.StatusOK, render(s, s.flinkJobs, e))
+}
+
+func (s *Server) deleteFlinkJob(w http.ResponseWriter, r *http.Request) {
+	e := s.flinkJobs.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindFlink, vars(r, "id"))
+		return
+	}
+	s.flinkJobs.remove(e)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getFlinkJobLogs(w http.ResponseWriter, r *http.Request) {
+	e := s.flinkJobs.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindFlink, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.logs(e.name, timelineLogs(s, "flink job "+e.name, e.timeline)))
+}
+
+func (s *Server) listFunctions(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, renderAll(s, s.functions))
+}
+
+func (s *Server) getFunction(w http.ResponseWriter, r *http.Request) {
+	e := s.functions.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindFunction, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, render(s, s.functions, e))
+}
+
+func (s *Server) createFunction(w http.ResponseWriter, r *http.Request) {
+	var in meroxa.CreateFunctionInput
+	if !decode(w, r, &in) {
+		return
+	}
+	details := make(map[string][]string)
+	required(details, "name", in.Name)
+	required(details, "image", in.Image)
+	if len(details) > 0 {
+		writeInvalid(w, details)
+		return
+	}
+	if s.functions.get(in.Name) != nil {
+		writeConflict(w, KindFunction, in.Name)
+		return
+	}
+
+	now := s.now().UTC()
+	f := &meroxa.Function{
+		Name:         in.Name,
+		InputStream:  in.InputStream,
+		OutputStream: in.OutputStream,
+		Image:        in.Image,
+		Command:      in.Command,
+		Args:         in.Args,
+		EnvVars:      in.EnvVars,
+		Pipeline:     in.Pipeline,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	e := s.functions.add(in.Name, f, s.newTimeline(KindFunction, in.Name, functionStates...))
+	f.UUID = e.uuid
+	writeJSON(w, http.StatusOK, render(s, s.functions, e))
+}
+
+func (s *Server) deleteFunction(w http.ResponseWriter, r *http.Request) {
+	e := s.functions.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindFunction, vars(r, "id"))
+		return
+	}
+	f := render(s, s.functions, e)
+	s.functions.remove(e)
+	writeJSON(w, http.StatusOK, f)
+}
@@ -0,0 +1,53 @@
+package emulator
+
+import (
+	"net/http"
+	"net/http/httptest"
+)
+
+// IdempotencyKeyHeader identifies a create request sent again, e.g. when it's retried, so that it's carried out once.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// recordedResponse is a response sent again to requests with the same idempotency key.
+type recordedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// serveIdempotent handles r, carrying POST requests with an idempotency key out only once. The response to the
+// first one is sent again to the next ones.
+func (s *Server) serveIdempotent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if r.Method != http.MethodPost || key == "" {
+		s.router.ServeHTTP(w, r)
+		return
+	}
+
+	key = r.URL.Path + " " + key
+	if resp, ok := s.idempotent[key]; ok {
+		w.Header().Set("Idempotent-Replayed", "true")
+		resp.write(w)
+		return
+	}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, r)
+	resp := &recordedResponse{status: rec.Code, header: rec.Header(), body: rec.Body.Bytes()}
+	// Requests failing because of the emulator can be carried out once it's fixed.
+	if resp.status < http.StatusInternalServerError {
+		s.idempotent[key] = resp
+	}
+	resp.write(w)
+}
+
+func (resp *recordedResponse) write(w http.ResponseWriter) {
+	for k, v := range resp.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.status)
+	_, _ = w.Write(resp.body)
+}
@@ -0,0 +1,329 @@
+package emulator
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var connectorStates = []string{
+	string(meroxa.ConnectorStatePending),
+	string(meroxa.ConnectorStateRunning),
+}
+
+func (s *Server) listConnectors(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, renderAll(s, s.connectors))
+}
+
+func (s *Server) getConnector(w http.ResponseWriter, r *http.Request) {
+	e := s.connectors.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindConnector, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, render(s, s.connectors, e))
+}
+
+func (s *Server) createConnector(w http.ResponseWriter, r *http.Request) {
+	var in meroxa.CreateConnectorInput
+	if !decode(w, r, &in) {
+		return
+	}
+	details := make(map[string][]string)
+	required(details, "resource_name", in.ResourceName)
+	required(details, "pipeline_name", in.PipelineName)
+	if len(details) > 0 {
+		writeInvalid(w, details)
+		return
+	}
+
+	res := s.resources.get(in.ResourceName)
+	if res == nil {
+		writeNotFound(w, KindResource, in.ResourceName)
+		return
+	}
+	pipeline := s.pipelines.get(in.PipelineName)
+	if pipeline == nil {
+		writeNotFound(w, KindPipeline, in.PipelineName)
+		return
+	}
+
+	typ := in.Type
+	if t, ok := in.Metadata["mx:connectorType"].(string); ok && typ == "" {
+		typ = meroxa.ConnectorType(t)
+	}
+	if typ != meroxa.ConnectorTypeSource && typ != meroxa.ConnectorTypeDestination {
+		writeInvalid(w, map[string][]string{"connector_type": {"must be one of source, destination"}})
+		return
+	}
+
+	name := in.Name
+	if name == "" {
+		name = res.name + "-" + string(typ)
+	}
+	if s.connectors.get(name) != nil {
+		writeConflict(w, KindConnector, name)
+		return
+	}
+
+	input, _ := in.Configuration["input"].(string)
+	streams := map[string]interface{}{}
+	if typ == meroxa.ConnectorTypeSource {
+		streams["output"] = []string{name + "." + input}
+	} else {
+		streams["input"] = []string{input}
+	}
+
+	now := s.now().UTC()
+	c := &meroxa.Connector{
+		Configuration: in.Configuration,
+		CreatedAt:     now,
+		Environment:   res.value.Environment,
+		Metadata:      in.Metadata,
+		Name:          name,
+		PipelineName:  pipeline.name,
+		ResourceName:  res.name,
+		Streams:       streams,
+		Type:          typ,
+		UpdatedAt:     now,
+	}
+	e := s.connectors.add(name, c, s.newTimeline(KindConnector, name, connectorStates...))
+	c.UUID = e.uuid
+	writeJSON(w, http.StatusOK, render(s, s.connectors, e))
+}
+
+func (s *Server) updateConnector(w http.ResponseWriter, r *http.Request) {
+	e := s.connectors.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindConnector, vars(r, "id"))
+		return
+	}
+	var in meroxa.UpdateConnectorInput
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Name != "" && in.Name != e.name {
+		if s.connectors.get(in.Name) != nil {
+			writeConflict(w, KindConnector, in.Name)
+			return
+		}
+		e.name = in.Name
+		e.value.Name = in.Name
+	}
+	if in.Configuration != nil {
+		e.value.Configuration = in.Configuration
+		// Connectors are restarted with their new configuration.
+		e.timeline = s.newTimeline(KindConnector, e.name, connectorStates...)
+	}
+	e.value.UpdatedAt = s.now().UTC()
+	writeJSON(w, http.StatusOK, render(s, s.connectors, e))
+}
+
+func (s *Server) updateConnectorStatus(w http.ResponseWriter, r *http.Request) {
+	e := s.connectors.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindConnector, vars(r, "id"))
+		return
+	}
+	var in struct {
+		State meroxa.Action `json:"state"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+
+	switch in.State {
+	case meroxa.ActionPause:
+		e.timeline = timeline{since: s.now().UTC(), states: []string{string(meroxa.ConnectorStatePaused)}}
+	case meroxa.ActionResume:
+		e.timeline = s.newTimeline(KindConnector, e.name, connectorStates[1:]...)
+	case meroxa.ActionRestart:
+		e.timeline = s.newTimeline(KindConnector, e.name, connectorStates...)
+	default:
+		writeInvalid(w, map[string][]string{"state": {"must be one of pause, resume, restart"}})
+		return
+	}
+	e.value.UpdatedAt = s.now().UTC()
+	writeJSON(w, http.StatusOK, render(s, s.connectors, e))
+}
+
+func (s *Server) deleteConnector(w http.ResponseWriter, r *http.Request) {
+	e := s.connectors.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindConnector, vars(r, "id"))
+		return
+	}
+	s.connectors.remove(e)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listPipelines(w http.ResponseWriter, r *http.Request) {
+	// Pipelines are looked up by name with a query, e.g. /v1/pipelines?name=my-pipeline.
+	if name := r.URL.Query().Get("name"); name != "" {
+		e := s.pipelines.get(name)
+		if e == nil || e.name != name {
+			writeNotFound(w, KindPipeline, name)
+			return
+		}
+		writeJSON(w, http.StatusOK, render(s, s.pipelines, e))
+		return
+	}
+	writeJSON(w, http.StatusOK, renderAll(s, s.pipelines))
+}
+
+// pipeline returns the pipeline with the given UUID, name or ID, its position in the order pipelines were created.
+func (s *Server) pipeline(nameOrID string) *entity[meroxa.Pipeline] {
+	if e := s.pipelines.get(nameOrID); e != nil {
+		return e
+	}
+	if id, err := strconv.Atoi(nameOrID); err == nil && id > 0 && id <= len(s.pipelines.entities) {
+		return s.pipelines.entities[id-1]
+	}
+	return nil
+}
+
+func (s *Server) getPipeline(w http.ResponseWriter, r *http.Request) {
+	e := s.pipeline(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindPipeline, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, render(s, s.pipelines, e))
+}
+
+func (s *Server) createPipeline(w http.ResponseWriter, r *http.Request) {
+	var in meroxa.CreatePipelineInput
+	if !decode(w, r, &in) {
+		return
+	}
+	if p, ok := s.addPipeline(w, &in); ok {
+		writeJSON(w, http.StatusOK, p)
+	}
+}
+
+func (s *Server) addPipeline(w http.ResponseWriter, in *meroxa.CreatePipelineInput) (*meroxa.Pipeline, bool) {
+	details := make(map[string][]string)
+	required(details, "name", in.Name)
+	if len(details) > 0 {
+		writeInvalid(w, details)
+		return nil, false
+	}
+	if s.pipelines.get(in.Name) != nil {
+		writeConflict(w, KindPipeline, in.Name)
+		return nil, false
+	}
+	env, ok := s.environment(w, in.Environment)
+	if !ok {
+		return nil, false
+	}
+
+	now := s.now().UTC()
+	p := &meroxa.Pipeline{
+		CreatedAt:   now,
+		Environment: env,
+		Metadata:    in.Metadata,
+		Name:        in.Name,
+		UpdatedAt:   now,
+	}
+	e := s.pipelines.add(in.Name, p, s.newTimeline(KindPipeline, in.Name, string(meroxa.PipelineStateHealthy)))
+	p.UUID = e.uuid
+	return render(s, s.pipelines, e), true
+}
+
+func (s *Server) updatePipeline(w http.ResponseWriter, r *http.Request) {
+	e := s.pipeline(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindPipeline, vars(r, "id"))
+		return
+	}
+	var in meroxa.UpdatePipelineInput
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Name != "" && in.Name != e.name {
+		if s.pipelines.get(in.Name) != nil {
+			writeConflict(w, KindPipeline, in.Name)
+			return
+		}
+		for _, c := range s.connectors.entities {
+			if c.value.PipelineName == e.name {
+				c.value.PipelineName = in.Name
+			}
+		}
+		e.name = in.Name
+		e.value.Name = in.Name
+	}
+	if in.Metadata != nil {
+		e.value.Metadata = in.Metadata
+	}
+	e.value.UpdatedAt = s.now().UTC()
+	writeJSON(w, http.StatusOK, render(s, s.pipelines, e))
+}
+
+func (s *Server) updatePipelineStatus(w http.ResponseWriter, r *http.Request) {
+	e := s.pipeline(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindPipeline, vars(r, "id"))
+		return
+	}
+	var in struct {
+		State meroxa.Action `json:"state"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+
+	// The connectors of a pipeline are paused and resumed with it.
+	var states []string
+	switch in.State {
+	case meroxa.ActionPause:
+		states = []string{string(meroxa.ConnectorStatePaused)}
+	case meroxa.ActionResume:
+		states = connectorStates[1:]
+	case meroxa.ActionRestart:
+		states = connectorStates
+	default:
+		writeInvalid(w, map[string][]string{"state": {"must be one of pause, resume, restart"}})
+		return
+	}
+	for _, c := range s.connectors.entities {
+		if c.value.PipelineName == e.name {
+			c.timeline = timeline{since: s.now().UTC(), states: states}
+		}
+	}
+	e.value.UpdatedAt = s.now().UTC()
+	writeJSON(w, http.StatusOK, render(s, s.pipelines, e))
+}
+
+func (s *Server) deletePipeline(w http.ResponseWriter, r *http.Request) {
+	e := s.pipeline(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindPipeline, vars(r, "id"))
+		return
+	}
+	for _, c := range s.connectors.entities {
+		if c.value.PipelineName == e.name {
+			writeError(w, http.StatusConflict, "conflict",
+				"pipeline \""+e.name+"\" has connector \""+c.name+"\", remove its connectors first")
+			return
+		}
+	}
+	s.pipelines.remove(e)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listPipelineConnectors(w http.ResponseWriter, r *http.Request) {
+	e := s.pipeline(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindPipeline, vars(r, "id"))
+		return
+	}
+	connectors := make([]*meroxa.Connector, 0)
+	for _, c := range s.connectors.entities {
+		if c.value.PipelineName == e.name {
+			connectors = append(connectors, render(s, s.connectors, c))
+		}
+	}
+	writeJSON(w, http.StatusOK, connectors)
+}
@@ -0,0 +1,214 @@
+package emulator
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+var resourceStates = []string{
+	string(meroxa.ResourceStatePending),
+	string(meroxa.ResourceStateStarting),
+	string(meroxa.ResourceStateReady),
+}
+
+func (s *Server) listResources(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, renderAll(s, s.resources))
+}
+
+func (s *Server) getResource(w http.ResponseWriter, r *http.Request) {
+	e := s.resources.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindResource, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, render(s, s.resources, e))
+}
+
+func (s *Server) createResource(w http.ResponseWriter, r *http.Request) {
+	var in meroxa.CreateResourceInput
+	if !decode(w, r, &in) {
+		return
+	}
+	if res, ok := s.addResource(w, &in, s.newTimeline(KindResource, in.Name, resourceStates...)); ok {
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func (s *Server) addResource(w http.ResponseWriter, in *meroxa.CreateResourceInput, tl timeline) (*meroxa.Resource, bool) {
+	details := make(map[string][]string)
+	required(details, "name", in.Name)
+	required(details, "type", string(in.Type))
+	required(details, "url", in.URL)
+	if len(details) > 0 {
+		writeInvalid(w, details)
+		return nil, false
+	}
+	if s.resources.get(in.Name) != nil {
+		writeConflict(w, KindResource, in.Name)
+		return nil, false
+	}
+	env, ok := s.environment(w, in.Environment)
+	if !ok {
+		return nil, false
+	}
+
+	now := s.now().UTC()
+	res := &meroxa.Resource{
+		Type:        in.Type,
+		Name:        in.Name,
+		URL:         in.URL,
+		Credentials: in.Credentials,
+		Metadata:    in.Metadata,
+		Environment: env,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if in.SSHTunnel != nil {
+		res.SSHTunnel = &meroxa.ResourceSSHTunnel{Address: in.SSHTunnel.Address, PublicKey: newPublicKey()}
+	}
+	e := s.resources.add(in.Name, res, tl)
+	res.UUID = e.uuid
+	return render(s, s.resources, e), true
+}
+
+func (s *Server) updateResource(w http.ResponseWriter, r *http.Request) {
+	e := s.resources.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindResource, vars(r, "id"))
+		return
+	}
+	var in meroxa.UpdateResourceInput
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Name != "" && in.Name != e.name {
+		if s.resources.get(in.Name) != nil {
+			writeConflict(w, KindResource, in.Name)
+			return
+		}
+		e.name = in.Name
+		e.value.Name = in.Name
+	}
+
+	// Resources are validated again when the way to connect to them changes.
+	restart := false
+	if in.URL != "" {
+		e.value.URL = in.URL
+		restart = true
+	}
+	if in.Credentials != nil {
+		e.value.Credentials = in.Credentials
+		restart = true
+	}
+	if in.SSHTunnel != nil {
+		e.value.SSHTunnel = &meroxa.ResourceSSHTunnel{Address: in.SSHTunnel.Address, PublicKey: newPublicKey()}
+		restart = true
+	}
+	if in.Metadata != nil {
+		e.value.Metadata = in.Metadata
+	}
+	if restart {
+		e.timeline = s.newTimeline(KindResource, e.name, resourceStates[1:]...)
+	}
+	e.value.UpdatedAt = s.now().UTC()
+	writeJSON(w, http.StatusOK, render(s, s.resources, e))
+}
+
+func (s *Server) deleteResource(w http.ResponseWriter, r *http.Request) {
+	e := s.resources.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindResource, vars(r, "id"))
+		return
+	}
+	for _, c := range s.connectors.entities {
+		if c.value.ResourceName == e.name {
+			writeError(w, http.StatusConflict, "conflict",
+				"resource \""+e.name+"\" is used by connector \""+c.name+"\", remove it first")
+			return
+		}
+	}
+	s.resources.remove(e)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) resourceAction(w http.ResponseWriter, r *http.Request) {
+	e := s.resources.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindResource, vars(r, "id"))
+		return
+	}
+	var in struct {
+		Action string `json:"action"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+
+	switch in.Action {
+	case "validate":
+	case "rotate_keys":
+		if e.value.SSHTunnel == nil {
+			writeInvalid(w, map[string][]string{"ssh_tunnel": {"resource \"" + e.name + "\" has no SSH tunnel"}})
+			return
+		}
+		e.value.SSHTunnel.PublicKey = newPublicKey()
+		e.value.UpdatedAt = s.now().UTC()
+	default:
+		writeInvalid(w, map[string][]string{"action": {"must be one of validate, rotate_keys"}})
+		return
+	}
+	writeJSON(w, http.StatusOK, render(s, s.resources, e))
+}
+
+func (s *Server) introspectResource(w http.ResponseWriter, r *http.Request) {
+	e := s.resources.get(vars(r, "id"))
+	if e == nil {
+		writeNotFound(w, KindResource, vars(r, "id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, meroxa.ResourceIntrospection{
+		UUID:        s.newUUID(),
+		Collections: []string{"public.users", "public.orders"},
+		Schemas: map[string]string{
+			"public.users":  `{"id":"integer","email":"text"}`,
+			"public.orders": `{"id":"integer","user_id":"integer","total":"numeric"}`,
+		},
+		Capabilities: map[string]string{
+			"source":      "true",
+			"destination": "true",
+		},
+		Samples: map[string][]string{
+			"public.users": {`{"id":1,"email":"user@example.com"}`},
+		},
+		ResourceVersion: "1.0",
+		IntrospectedAt:  s.now().UTC().Truncate(time.Second),
+	})
+}
+
+// environment returns the identifier of the environment of an entity, responding with an error when the
+// environment doesn't exist.
+func (s *Server) environment(w http.ResponseWriter, id *meroxa.EntityIdentifier) (*meroxa.EntityIdentifier, bool) {
+	if id == nil || id.UUID == "" && id.Name == "" {
+		return nil, true
+	}
+	nameOrUUID := id.UUID
+	if nameOrUUID == "" {
+		nameOrUUID = id.Name
+	}
+	e := s.environments.get(nameOrUUID)
+	if e == nil {
+		writeNotFound(w, KindEnvironment, nameOrUUID)
+		return nil, false
+	}
+	return &meroxa.EntityIdentifier{UUID: e.uuid, Name: e.name}, true
+}
+
+func newPublicKey() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return "ssh-ed25519 " + base64.StdEncoding.EncodeToString(b) + " emulator@meroxa.io"
+}
@@ -0,0 +1,151 @@
+package emulator
+
+import (
+	"time"
+)
+
+// Kind is a kind of entity of the Meroxa API.
+type Kind string
+
+const (
+	KindApp         Kind = "app"
+	KindBuild       Kind = "build"
+	KindConnector   Kind = "connector"
+	KindDeployment  Kind = "deployment"
+	KindEnvironment Kind = "environment"
+	KindFlink       Kind = "flink"
+	KindFunction    Kind = "function"
+	KindPipeline    Kind = "pipeline"
+	KindResource    Kind = "resource"
+)
+
+// noun is how the API refers to entities of the kind in its messages, e.g. "could not find application".
+func (k Kind) noun() string {
+	switch k {
+	case KindApp:
+		return "application"
+	case KindFlink:
+		return "flink job"
+	}
+	return string(k)
+}
+
+// state is the state an entity is in, since a point in time.
+type state struct {
+	name    string
+	details string
+	since   time.Time
+}
+
+// timeline is the states an entity goes through from since, each lasting one step. The last one is final.
+type timeline struct {
+	since  time.Time
+	states []string
+	// details are the details of the final state, e.g. why a build failed.
+	details string
+}
+
+// reached returns the states reached at now, the last one being the current one.
+func (t timeline) reached(now time.Time, step time.Duration) []state {
+	n := len(t.states)
+	if step > 0 {
+		if i := int(now.Sub(t.since)/step) + 1; i < n {
+			n = i
+		}
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	states := make([]state, 0, n)
+	for i := 0; i < n; i++ {
+		st := state{name: t.states[i], since: t.since.Add(time.Duration(i) * step)}
+		if i == len(t.states)-1 {
+			st.details = t.details
+		}
+		states = append(states, st)
+	}
+	return states
+}
+
+func (t timeline) at(now time.Time, step time.Duration) state {
+	states := t.reached(now, step)
+	return states[len(states)-1]
+}
+
+// entity is an entity stored by the emulator, whose state is given by its timeline.
+type entity[T any] struct {
+	uuid     string
+	name     string
+	value    *T
+	timeline timeline
+}
+
+// collection is the entities of a kind, in the order they were created.
+type collection[T any] struct {
+	kind     Kind
+	entities []*entity[T]
+	newUUID  func() string
+	// apply sets the state of an entity on its value.
+	apply func(v *T, st state)
+}
+
+func newCollection[T any](kind Kind, newUUID func() string, apply func(v *T, st state)) *collection[T] {
+	return &collection[T]{kind: kind, newUUID: newUUID, apply: apply}
+}
+
+// get returns the entity with the given UUID or name, nil if there's none.
+func (c *collection[T]) get(nameOrUUID string) *entity[T] {
+	for _, e := range c.entities {
+		if e.uuid == nameOrUUID {
+			return e
+		}
+	}
+	for _, e := range c.entities {
+		if e.name != "" && e.name == nameOrUUID {
+			return e
+		}
+	}
+	return nil
+}
+
+func (c *collection[T]) add(name string, v *T, tl timeline) *entity[T] {
+	e := &entity[T]{uuid: c.newUUID(), name: name, value: v, timeline: tl}
+	c.entities = append(c.entities, e)
+	return e
+}
+
+func (c *collection[T]) remove(e *entity[T]) {
+	for i, other := range c.entities {
+		if other == e {
+			c.entities = append(c.entities[:i], c.entities[i+1:]...)
+			return
+		}
+	}
+}
+
+// newTimeline returns the timeline of an entity going through states from now. Its final state is the one the
+// scenario sets for it, if any.
+func (s *Server) newTimeline(kind Kind, name string, states ...string) timeline {
+	tl := timeline{since: s.now().UTC(), states: append([]string{}, states...)}
+	if o := s.scenario.outcome(kind, name); o != nil {
+		tl.states[len(tl.states)-1] = o.State
+		tl.details = o.Details
+	}
+	return tl
+}
+
+// render returns a copy of the value of e, in its current state.
+func render[T any](s *Server, c *collection[T], e *entity[T]) *T {
+	v := *e.value
+	c.apply(&v, e.timeline.at(s.now().UTC(), s.step))
+	return &v
+}
+
+func renderAll[T any](s *Server, c *collection[T]) []*T {
+	values := make([]*T, 0, len(c.entities))
+	for _, e := range c.entities {
+		values = append(values, render(s, c, e))
+	}
+	return values
+}
@@ -14,6 +14,10 @@ type AppExtendedConnector struct {
 }
 
 func AppsTable(apps []*meroxa.Application, hideHeaders bool) string {
+	return appsTable(apps, TableOptions{HideHeaders: hideHeaders})
+}
+
+func appsTable(apps []*meroxa.Application, o TableOptions) string {
 	if len(apps) == 0 {
 		return ""
 	}
@@ -34,11 +38,14 @@ func AppsTable(apps []*meroxa.Application, hideHeaders bool) string {
 		} else {
 			r = append(r, &simpletable.Cell{Align: simpletable.AlignLeft, Text: string(meroxa.EnvironmentTypeCommon)})
 		}
+		if o.Wide {
+			r = append(r, wideCells(app.CreatedAt, app.UpdatedAt)...)
+		}
 
 		table.Body.Cells = append(table.Body.Cells, r)
 	}
 
-	if !hideHeaders {
+	if !o.HideHeaders {
 		cells := []*simpletable.Cell{
 			{Align: simpletable.AlignCenter, Text: "UUID"},
 			{Align: simpletable.AlignCenter, Text: "NAME"},
@@ -47,6 +54,9 @@ func AppsTable(apps []*meroxa.Application, hideHeaders bool) string {
 			{Align: simpletable.AlignCenter, Text: "STATE"},
 			{Align: simpletable.AlignCenter, Text: "ENVIRONMENT"},
 		}
+		if o.Wide {
+			cells = append(cells, wideHeaderCells()...)
+		}
 
 		table.Header = &simpletable.Header{
 			Cells: cells,
@@ -99,10 +99,14 @@ func ConnectorTable(connector *meroxa.Connector) string {
 }
 
 func ConnectorsTable(connectors []*meroxa.Connector, hideHeaders bool) string {
+	return connectorsTable(connectors, TableOptions{HideHeaders: hideHeaders})
+}
+
+func connectorsTable(connectors []*meroxa.Connector, o TableOptions) string {
 	if len(connectors) != 0 {
 		table := simpletable.New()
 
-		if !hideHeaders {
+		if !o.HideHeaders {
 			table.Header = &simpletable.Header{
 				Cells: []*simpletable.Cell{
 					{Align: simpletable.AlignCenter, Text: "UUID"},
@@ -114,6 +118,9 @@ func ConnectorsTable(connectors []*meroxa.Connector, hideHeaders bool) string {
 					{Align: simpletable.AlignCenter, Text: "ENVIRONMENT"},
 				},
 			}
+			if o.Wide {
+				table.Header.Cells = append(table.Header.Cells, wideHeaderCells()...)
+			}
 		}
 
 		for _, conn := range connectors {
@@ -135,6 +142,9 @@ func ConnectorsTable(connectors []*meroxa.Connector, hideHeaders bool) string {
 				{Text: conn.PipelineName},
 				{Text: env},
 			}
+			if o.Wide {
+				r = append(r, wideCells(conn.CreatedAt, conn.UpdatedAt)...)
+			}
 
 			table.Body.Cells = append(table.Body.Cells, r)
 		}
@@ -0,0 +1,82 @@
+package display
+
+import (
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+func DeploymentsTable(deployments []*meroxa.Deployment, hideHeaders bool) string {
+	if len(deployments) == 0 {
+		return ""
+	}
+
+	table := simpletable.New()
+	if !hideHeaders {
+		table.Header = &simpletable.Header{
+			Cells: []*simpletable.Cell{
+				{Align: simpletable.AlignCenter, Text: "UUID"},
+				{Align: simpletable.AlignCenter, Text: "GIT SHA"},
+				{Align: simpletable.AlignCenter, Text: "STATE"},
+				{Align: simpletable.AlignCenter, Text: "CREATED BY"},
+				{Align: simpletable.AlignCenter, Text: "CREATED AT"},
+			},
+		}
+	}
+
+	for _, d := range deployments {
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: d.UUID},
+			{Align: simpletable.AlignLeft, Text: d.GitSha},
+			{Align: simpletable.AlignLeft, Text: string(d.Status.State)},
+			{Align: simpletable.AlignLeft, Text: d.CreatedBy},
+			{Align: simpletable.AlignLeft, Text: d.CreatedAt.Format(time.RFC3339)},
+		})
+	}
+
+	table.SetStyle(simpletable.StyleCompact)
+	return table.String()
+}
+
+func DeploymentTable(d *meroxa.Deployment) string {
+	mainTable := simpletable.New()
+	mainTable.Body.Cells = [][]*simpletable.Cell{
+		{
+			{Align: simpletable.AlignRight, Text: "UUID:"},
+			{Text: d.UUID},
+		},
+		{
+			{Align: simpletable.AlignRight, Text: "Application:"},
+			{Text: d.Application.Name},
+		},
+		{
+			{Align: simpletable.AlignRight, Text: "Git SHA:"},
+			{Text: d.GitSha},
+		},
+		{
+			{Align: simpletable.AlignRight, Text: "Spec Version:"},
+			{Text: d.SpecVersion},
+		},
+		{
+			{Align: simpletable.AlignRight, Text: "Created By:"},
+			{Text: d.CreatedBy},
+		},
+		{
+			{Align: simpletable.AlignRight, Text: "Created At:"},
+			{Text: d.CreatedAt.String()},
+		},
+		{
+			{Align: simpletable.AlignRight, Text: "State:"},
+			{Text: string(d.Status.State)},
+		},
+	}
+	if d.Status.Details != "" {
+		mainTable.Body.Cells = append(mainTable.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: "Status Details:"},
+			{Text: d.Status.Details},
+		})
+	}
+	mainTable.SetStyle(simpletable.StyleCompact)
+	return mainTable.String()
+}
@@ -0,0 +1,55 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+func TestDeploymentsTable(t *testing.T) {
+	d := &meroxa.Deployment{
+		UUID:      "ac3a6ea3-5d4d-4d43-8e7b-2f1c3b6e1f8a",
+		GitSha:    "f0e1d2c3",
+		CreatedBy: "user@meroxa.io",
+		CreatedAt: time.Date(2022, 10, 18, 9, 30, 0, 0, time.UTC),
+		Status:    meroxa.DeploymentStatus{State: meroxa.DeploymentStateDeployed},
+	}
+
+	tests := map[string][]*meroxa.Deployment{
+		"Base": {d},
+	}
+
+	for name, deployments := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := DeploymentsTable(deployments, false)
+			for _, want := range []string{"UUID", "GIT SHA", "CREATED BY", d.UUID, d.GitSha, "deployed", d.CreatedBy, "2022-10-18T09:30:00Z"} {
+				if !strings.Contains(out, want) {
+					t.Errorf("%s, not found", want)
+				}
+			}
+		})
+	}
+
+	if out := DeploymentsTable([]*meroxa.Deployment{d}, true); strings.Contains(out, "GIT SHA") {
+		t.Errorf("expected headers to be hidden, got %s", out)
+	}
+}
+
+func TestDeploymentTable(t *testing.T) {
+	d := &meroxa.Deployment{
+		UUID:        "ac3a6ea3-5d4d-4d43-8e7b-2f1c3b6e1f8a",
+		GitSha:      "f0e1d2c3",
+		Application: meroxa.EntityIdentifier{Name: "my-app"},
+		SpecVersion: "0.2.0",
+		Status:      meroxa.DeploymentStatus{State: meroxa.DeploymentStateDeployingError, Details: "boom"},
+	}
+
+	out := DeploymentTable(d)
+	for _, want := range []string{d.UUID, d.GitSha, "my-app", "0.2.0", "deploying_error", "Status Details:", "boom"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s, not found", want)
+		}
+	}
+}
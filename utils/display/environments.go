@@ -9,10 +9,14 @@ import (
 )
 
 func EnvironmentsTable(environments []*meroxa.Environment, hideHeaders bool) string {
+	return environmentsTable(environments, TableOptions{HideHeaders: hideHeaders})
+}
+
+func environmentsTable(environments []*meroxa.Environment, o TableOptions) string {
 	if len(environments) != 0 {
 		table := simpletable.New()
 
-		if !hideHeaders {
+		if !o.HideHeaders {
 			table.Header = &simpletable.Header{
 				Cells: []*simpletable.Cell{
 					{Align: simpletable.AlignCenter, Text: "UUID"},
@@ -23,6 +27,9 @@ func EnvironmentsTable(environments []*meroxa.Environment, hideHeaders bool) str
 					{Align: simpletable.AlignCenter, Text: "STATE"},
 				},
 			}
+			if o.Wide {
+				table.Header.Cells = append(table.Header.Cells, wideHeaderCells()...)
+			}
 		}
 
 		for _, p := range environments {
@@ -34,6 +41,9 @@ func EnvironmentsTable(environments []*meroxa.Environment, hideHeaders bool) str
 				{Align: simpletable.AlignCenter, Text: string(p.Region)},
 				{Align: simpletable.AlignCenter, Text: string(p.Status.State)},
 			}
+			if o.Wide {
+				r = append(r, wideCells(p.CreatedAt, p.UpdatedAt)...)
+			}
 
 			table.Body.Cells = append(table.Body.Cells, r)
 		}
@@ -0,0 +1,162 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alexeyco/simpletable"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+// IntrospectionCollections returns the collections of an introspection result, sorted by name. Collections only
+// known through their schema or samples are included as well.
+func IntrospectionCollections(ri *meroxa.ResourceIntrospection) []string {
+	seen := map[string]bool{}
+	var collections []string
+	add := func(c string) {
+		if !seen[c] {
+			seen[c] = true
+			collections = append(collections, c)
+		}
+	}
+	for _, c := range ri.Collections {
+		add(c)
+	}
+	for c := range ri.Schemas {
+		add(c)
+	}
+	for c := range ri.Samples {
+		add(c)
+	}
+	sort.Strings(collections)
+	return collections
+}
+
+func ResourceIntrospectionTable(ri *meroxa.ResourceIntrospection) string {
+	mainTable := simpletable.New()
+	mainTable.Body.Cells = [][]*simpletable.Cell{
+		{
+			{Align: simpletable.AlignRight, Text: "Resource Version:"},
+			{Text: ri.ResourceVersion},
+		},
+		{
+			{Align: simpletable.AlignRight, Text: "Introspected At:"},
+			{Text: ri.IntrospectedAt.String()},
+		},
+	}
+	mainTable.SetStyle(simpletable.StyleCompact)
+	return mainTable.String()
+}
+
+func IntrospectionCollectionsTable(ri *meroxa.ResourceIntrospection, hideHeaders bool) string {
+	collections := IntrospectionCollections(ri)
+	if len(collections) == 0 {
+		return ""
+	}
+
+	table := simpletable.New()
+	if !hideHeaders {
+		table.Header = &simpletable.Header{
+			Cells: []*simpletable.Cell{
+				{Align: simpletable.AlignCenter, Text: "COLLECTION"},
+				{Align: simpletable.AlignCenter, Text: "SCHEMA"},
+				{Align: simpletable.AlignCenter, Text: "SAMPLES"},
+			},
+		}
+	}
+
+	for _, c := range collections {
+		schema := "no"
+		if ri.Schemas[c] != "" {
+			schema = "yes"
+		}
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Text: c},
+			{Align: simpletable.AlignCenter, Text: schema},
+			{Align: simpletable.AlignRight, Text: fmt.Sprint(len(ri.Samples[c]))},
+		})
+	}
+	table.SetStyle(simpletable.StyleCompact)
+	return table.String()
+}
+
+func IntrospectionCapabilitiesTable(ri *meroxa.ResourceIntrospection, hideHeaders bool) string {
+	if len(ri.Capabilities) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(ri.Capabilities))
+	for name := range ri.Capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	table := simpletable.New()
+	if !hideHeaders {
+		table.Header = &simpletable.Header{
+			Cells: []*simpletable.Cell{
+				{Align: simpletable.AlignCenter, Text: "CAPABILITY"},
+				{Align: simpletable.AlignCenter, Text: "VALUE"},
+			},
+		}
+	}
+
+	for _, name := range names {
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Text: name},
+			{Text: ri.Capabilities[name]},
+		})
+	}
+	table.SetStyle(simpletable.StyleCompact)
+	return table.String()
+}
+
+// IntrospectionCollectionTable shows the schema and sample records of a single collection.
+func IntrospectionCollectionTable(ri *meroxa.ResourceIntrospection, collection string, hideHeaders bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Collection %q\n", collection)
+
+	if schema := ri.Schemas[collection]; schema != "" {
+		fmt.Fprintf(&b, "\nSchema:\n%s\n", indentJSON(schema))
+	} else {
+		b.WriteString("\nNo schema available.\n")
+	}
+
+	samples := ri.Samples[collection]
+	if len(samples) == 0 {
+		b.WriteString("\nNo sample records available.")
+		return b.String()
+	}
+
+	table := simpletable.New()
+	if !hideHeaders {
+		table.Header = &simpletable.Header{
+			Cells: []*simpletable.Cell{
+				{Align: simpletable.AlignCenter, Text: "#"},
+				{Align: simpletable.AlignCenter, Text: "SAMPLE RECORD"},
+			},
+		}
+	}
+	for i, s := range samples {
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: fmt.Sprint(i + 1)},
+			{Text: s},
+		})
+	}
+	table.SetStyle(simpletable.StyleCompact)
+	fmt.Fprintf(&b, "\nSamples:\n%s", table.String())
+	return b.String()
+}
+
+// indentJSON pretty prints s when it's valid JSON and returns it untouched otherwise.
+func indentJSON(s string) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return out.String()
+}
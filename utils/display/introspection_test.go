@@ -0,0 +1,42 @@
+package display
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+func TestIntrospectionCollections(t *testing.T) {
+	ri := &meroxa.ResourceIntrospection{
+		Collections: []string{"orders"},
+		Schemas:     map[string]string{"customers": "{}"},
+		Samples:     map[string][]string{"orders": {"{}"}, "accounts": {"{}"}},
+	}
+
+	got := strings.Join(IntrospectionCollections(ri), ",")
+	if want := "accounts,customers,orders"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestIntrospectionCollectionTable(t *testing.T) {
+	ri := &meroxa.ResourceIntrospection{
+		Schemas: map[string]string{"orders": `{"fields":["id"]}`, "raw": "not json"},
+		Samples: map[string][]string{"orders": {`{"id":1}`}},
+	}
+
+	out := IntrospectionCollectionTable(ri, "orders", false)
+	for _, want := range []string{`Collection "orders"`, "\"fields\": [\n", "SAMPLE RECORD", `{"id":1}`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out = IntrospectionCollectionTable(ri, "raw", false)
+	for _, want := range []string{"not json", "No sample records available."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a JSONPath expression such as {.items[0].name}, supporting the subset used for scripting: fields,
+// quoted fields, indexes (negative ones from the end) and wildcards.
+type jsonPath []jsonPathStep
+
+type jsonPathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+func parseJSONPath(expr string) (jsonPath, error) {
+	s := strings.TrimSpace(expr)
+	if strings.HasPrefix(s, "{") {
+		if !strings.HasSuffix(s, "}") {
+			return nil, fmt.Errorf("missing closing brace in %q", expr)
+		}
+		s = s[1 : len(s)-1]
+	}
+	s = strings.TrimPrefix(s, "$")
+
+	var path jsonPath
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			return nil, fmt.Errorf("recursive descent isn't supported in %q", expr)
+		case strings.HasPrefix(s, "."):
+			s = s[1:]
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			key := s[:end]
+			s = s[end:]
+			switch key {
+			case "":
+				// "." alone is the root, e.g. {.} or {.[0]}
+			case "*":
+				path = append(path, jsonPathStep{wildcard: true})
+			default:
+				path = append(path, jsonPathStep{key: key})
+			}
+		case strings.HasPrefix(s, "["):
+			end := strings.Index(s, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("missing closing bracket in %q", expr)
+			}
+			sel := strings.TrimSpace(s[1:end])
+			s = s[end+1:]
+			step, err := parseJSONPathSelector(sel)
+			if err != nil {
+				return nil, fmt.Errorf("%w in %q", err, expr)
+			}
+			path = append(path, step)
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", s, expr)
+		}
+	}
+	return path, nil
+}
+
+func parseJSONPathSelector(sel string) (jsonPathStep, error) {
+	if sel == "*" {
+		return jsonPathStep{wildcard: true}, nil
+	}
+	if len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0] {
+		return jsonPathStep{key: sel[1 : len(sel)-1]}, nil
+	}
+	i, err := strconv.Atoi(sel)
+	if err != nil {
+		return jsonPathStep{}, fmt.Errorf("invalid selector [%s]", sel)
+	}
+	return jsonPathStep{index: i, isIndex: true}, nil
+}
+
+// eval returns the values matched by the expression in v, a value decoded from JSON.
+// Fields and indexes which don't exist match nothing.
+func (p jsonPath) eval(v interface{}) []interface{} {
+	values := []interface{}{v}
+	for _, step := range p {
+		var next []interface{}
+		for _, v := range values {
+			next = append(next, step.apply(v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (s jsonPathStep) apply(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			values := make([]interface{}, 0, len(v))
+			for _, k := range keys {
+				values = append(values, v[k])
+			}
+			return values
+		}
+		if s.isIndex {
+			return nil
+		}
+		if value, ok := v[s.key]; ok {
+			return []interface{}{value}
+		}
+	case []interface{}:
+		if s.wildcard {
+			return v
+		}
+		if !s.isIndex {
+			return nil
+		}
+		i := s.index
+		if i < 0 {
+			i += len(v)
+		}
+		if i >= 0 && i < len(v) {
+			return []interface{}{v[i]}
+		}
+	}
+	return nil
+}
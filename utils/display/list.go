@@ -0,0 +1,385 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+// ListOptions controls which items list commands show, set with --filter, --sort-by, --columns and --limit.
+type ListOptions struct {
+	Filters []ListFilter
+	SortBy  string
+	// Columns restricts the output to these columns, in this order.
+	Columns []string
+	// Limit is the maximum number of items shown, 0 shows all of them.
+	Limit int
+}
+
+// ListFilter keeps the items whose column Key has the value Value.
+type ListFilter struct {
+	Key   string
+	Value string
+}
+
+// ParseListOptions parses the values of --filter (key=value), --sort-by, --columns and --limit.
+func ParseListOptions(filters []string, sortBy string, columns []string, limit int) (ListOptions, error) {
+	o := ListOptions{SortBy: sortBy, Limit: limit}
+	for _, f := range filters {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return o, fmt.Errorf("invalid filter %q, expected key=value (e.g. state=running)", f)
+		}
+		o.Filters = append(o.Filters, ListFilter{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+	}
+	for _, c := range columns {
+		if c = strings.TrimSpace(c); c != "" {
+			o.Columns = append(o.Columns, c)
+		}
+	}
+	if limit < 0 {
+		return o, fmt.Errorf("--limit must be zero or positive, got %d", limit)
+	}
+	return o, nil
+}
+
+// ListColumn is a column of list commands, used by --filter, --sort-by and --columns.
+type ListColumn[T any] struct {
+	Name  string
+	Value func(T) string
+}
+
+type listColumn struct {
+	name  string
+	value func(interface{}) string
+}
+
+var listColumns = map[reflect.Type][]listColumn{}
+
+// RegisterListColumns registers the columns of listed items of type T.
+func RegisterListColumns[T any](columns ...ListColumn[T]) {
+	cc := make([]listColumn, 0, len(columns))
+	for _, c := range columns {
+		value := c.Value
+		cc = append(cc, listColumn{
+			name:  c.Name,
+			value: func(v interface{}) string { return value(v.(T)) },
+		})
+	}
+	listColumns[reflect.TypeOf((*T)(nil)).Elem()] = cc
+}
+
+func init() {
+	RegisterListColumns(
+		ListColumn[*meroxa.Application]{Name: "uuid", Value: func(a *meroxa.Application) string { return a.UUID }},
+		ListColumn[*meroxa.Application]{Name: "name", Value: func(a *meroxa.Application) string { return a.Name }},
+		ListColumn[*meroxa.Application]{Name: "language", Value: func(a *meroxa.Application) string { return a.Language }},
+		ListColumn[*meroxa.Application]{Name: "git_sha", Value: func(a *meroxa.Application) string { return a.GitSha }},
+		ListColumn[*meroxa.Application]{Name: "state", Value: func(a *meroxa.Application) string { return string(a.Status.State) }},
+		ListColumn[*meroxa.Application]{Name: "environment", Value: func(a *meroxa.Application) string { return environmentName(a.Environment) }},
+		ListColumn[*meroxa.Application]{Name: "created_at", Value: func(a *meroxa.Application) string { return formatTime(a.CreatedAt) }},
+		ListColumn[*meroxa.Application]{Name: "updated_at", Value: func(a *meroxa.Application) string { return formatTime(a.UpdatedAt) }},
+	)
+	RegisterListColumns(
+		ListColumn[*meroxa.Build]{Name: "uuid", Value: func(b *meroxa.Build) string { return b.Uuid }},
+		ListColumn[*meroxa.Build]{Name: "state", Value: func(b *meroxa.Build) string { return b.Status.State }},
+		ListColumn[*meroxa.Build]{Name: "environment", Value: func(b *meroxa.Build) string { return environmentName(b.Environment) }},
+		ListColumn[*meroxa.Build]{Name: "created_at", Value: func(b *meroxa.Build) string { return b.CreatedAt }},
+		ListColumn[*meroxa.Build]{Name: "updated_at", Value: func(b *meroxa.Build) string { return b.UpdatedAt }},
+	)
+	RegisterListColumns(
+		ListColumn[*meroxa.Connector]{Name: "uuid", Value: func(c *meroxa.Connector) string { return c.UUID }},
+		ListColumn[*meroxa.Connector]{Name: "name", Value: func(c *meroxa.Connector) string { return c.Name }},
+		ListColumn[*meroxa.Connector]{Name: "type", Value: func(c *meroxa.Connector) string { return string(c.Type) }},
+		ListColumn[*meroxa.Connector]{Name: "state", Value: func(c *meroxa.Connector) string { return string(c.State) }},
+		ListColumn[*meroxa.Connector]{Name: "pipeline", Value: func(c *meroxa.Connector) string { return c.PipelineName }},
+		ListColumn[*meroxa.Connector]{Name: "resource", Value: func(c *meroxa.Connector) string { return c.ResourceName }},
+		ListColumn[*meroxa.Connector]{Name: "environment", Value: func(c *meroxa.Connector) string { return environmentName(c.Environment) }},
+		ListColumn[*meroxa.Connector]{Name: "created_at", Value: func(c *meroxa.Connector) string { return formatTime(c.CreatedAt) }},
+		ListColumn[*meroxa.Connector]{Name: "updated_at", Value: func(c *meroxa.Connector) string { return formatTime(c.UpdatedAt) }},
+	)
+	RegisterListColumns(
+		ListColumn[*meroxa.Deployment]{Name: "uuid", Value: func(d *meroxa.Deployment) string { return d.UUID }},
+		ListColumn[*meroxa.Deployment]{Name: "git_sha", Value: func(d *meroxa.Deployment) string { return d.GitSha }},
+		ListColumn[*meroxa.Deployment]{Name: "state", Value: func(d *meroxa.Deployment) string { return string(d.Status.State) }},
+		ListColumn[*meroxa.Deployment]{Name: "created_by", Value: func(d *meroxa.Deployment) string { return d.CreatedBy }},
+		ListColumn[*meroxa.Deployment]{Name: "created_at", Value: func(d *meroxa.Deployment) string { return formatTime(d.CreatedAt) }},
+	)
+	RegisterListColumns(
+		ListColumn[*meroxa.Environment]{Name: "uuid", Value: func(e *meroxa.Environment) string { return e.UUID }},
+		ListColumn[*meroxa.Environment]{Name: "name", Value: func(e *meroxa.Environment) string { return e.Name }},
+		ListColumn[*meroxa.Environment]{Name: "type", Value: func(e *meroxa.Environment) string { return string(e.Type) }},
+		ListColumn[*meroxa.Environment]{Name: "provider", Value: func(e *meroxa.Environment) string { return string(e.Provider) }},
+		ListColumn[*meroxa.Environment]{Name: "region", Value: func(e *meroxa.Environment) string { return string(e.Region) }},
+		ListColumn[*meroxa.Environment]{Name: "state", Value: func(e *meroxa.Environment) string { return string(e.Status.State) }},
+		ListColumn[*meroxa.Environment]{Name: "created_at", Value: func(e *meroxa.Environment) string { return formatTime(e.CreatedAt) }},
+		ListColumn[*meroxa.Environment]{Name: "updated_at", Value: func(e *meroxa.Environment) string { return formatTime(e.UpdatedAt) }},
+	)
+	RegisterListColumns(
+		ListColumn[*meroxa.FlinkJob]{Name: "uuid", Value: func(j *meroxa.FlinkJob) string { return j.UUID }},
+		ListColumn[*meroxa.FlinkJob]{Name: "name", Value: func(j *meroxa.FlinkJob) string { return j.Name }},
+		ListColumn[*meroxa.FlinkJob]{Name: "environment", Value: func(j *meroxa.FlinkJob) string { return environmentName(&j.Environment) }},
+		ListColumn[*meroxa.FlinkJob]{Name: "state", Value: func(j *meroxa.FlinkJob) string { return string(j.Status.LifecycleState) }},
+		ListColumn[*meroxa.FlinkJob]{Name: "created_at", Value: func(j *meroxa.FlinkJob) string { return formatTime(j.CreatedAt) }},
+		ListColumn[*meroxa.FlinkJob]{Name: "updated_at", Value: func(j *meroxa.FlinkJob) string { return formatTime(j.UpdatedAt) }},
+	)
+	RegisterListColumns(
+		ListColumn[*meroxa.Pipeline]{Name: "uuid", Value: func(p *meroxa.Pipeline) string { return p.UUID }},
+		ListColumn[*meroxa.Pipeline]{Name: "name", Value: func(p *meroxa.Pipeline) string { return p.Name }},
+		ListColumn[*meroxa.Pipeline]{Name: "environment", Value: func(p *meroxa.Pipeline) string { return environmentName(p.Environment) }},
+		ListColumn[*meroxa.Pipeline]{Name: "state", Value: func(p *meroxa.Pipeline) string { return string(p.State) }},
+		ListColumn[*meroxa.Pipeline]{Name: "created_at", Value: func(p *meroxa.Pipeline) string { return formatTime(p.CreatedAt) }},
+		ListColumn[*meroxa.Pipeline]{Name: "updated_at", Value: func(p *meroxa.Pipeline) string { return formatTime(p.UpdatedAt) }},
+	)
+	RegisterListColumns(
+		ListColumn[*meroxa.Resource]{Name: "uuid", Value: func(r *meroxa.Resource) string { return r.UUID }},
+		ListColumn[*meroxa.Resource]{Name: "name", Value: func(r *meroxa.Resource) string { return r.Name }},
+		ListColumn[*meroxa.Resource]{Name: "type", Value: func(r *meroxa.Resource) string { return string(r.Type) }},
+		ListColumn[*meroxa.Resource]{Name: "environment", Value: func(r *meroxa.Resource) string { return environmentName(r.Environment) }},
+		ListColumn[*meroxa.Resource]{Name: "url", Value: func(r *meroxa.Resource) string { return r.URL }},
+		ListColumn[*meroxa.Resource]{Name: "state", Value: func(r *meroxa.Resource) string { return string(r.Status.State) }},
+		ListColumn[*meroxa.Resource]{Name: "created_at", Value: func(r *meroxa.Resource) string { return formatTime(r.CreatedAt) }},
+		ListColumn[*meroxa.Resource]{Name: "updated_at", Value: func(r *meroxa.Resource) string { return formatTime(r.UpdatedAt) }},
+	)
+
+	RegisterTable(func(rows ListRows, o TableOptions) string { return rows.table(o) })
+}
+
+// environmentName is the name shown for the environment of an item, items without one are in the common environment.
+func environmentName(e *meroxa.EntityIdentifier) string {
+	if e == nil || e.Name == "" {
+		return string(meroxa.EnvironmentTypeCommon)
+	}
+	return e.Name
+}
+
+// List filters, sorts and limits items as described by o. Items are returned as is unless columns are selected,
+// in which case only these columns are returned as ListRows.
+func List[T any](items []T, o ListOptions) (interface{}, error) {
+	columns := listColumns[reflect.TypeOf((*T)(nil)).Elem()]
+	column := func(name string) (listColumn, error) {
+		names := make([]string, 0, len(columns))
+		for _, c := range columns {
+			if strings.EqualFold(c.name, name) {
+				return c, nil
+			}
+			names = append(names, c.name)
+		}
+		return listColumn{}, fmt.Errorf("unknown column %q, use one of %s", name, strings.Join(names, ", "))
+	}
+
+	filters := make([]listColumn, 0, len(o.Filters))
+	for _, f := range o.Filters {
+		c, err := column(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, c)
+	}
+
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		matches := true
+		for i, c := range filters {
+			if !strings.EqualFold(c.value(item), o.Filters[i].Value) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result = append(result, item)
+		}
+	}
+
+	if o.SortBy != "" {
+		c, err := column(o.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, 0, len(result))
+		for _, item := range result {
+			values = append(values, c.value(item))
+		}
+		less := listValuesLess(values)
+		sort.SliceStable(result, func(i, j int) bool {
+			return less(c.value(result[i]), c.value(result[j]))
+		})
+	}
+
+	if o.Limit > 0 && len(result) > o.Limit {
+		result = result[:o.Limit]
+	}
+
+	if len(o.Columns) == 0 {
+		return result, nil
+	}
+
+	selected := make([]listColumn, 0, len(o.Columns))
+	rows := ListRows{Columns: make([]string, 0, len(o.Columns))}
+	for _, name := range o.Columns {
+		c, err := column(name)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, c)
+		rows.Columns = append(rows.Columns, c.name)
+	}
+	state, hasState := stateColumn(reflect.TypeOf((*T)(nil)).Elem())
+	for _, item := range result {
+		row := make([]string, 0, len(selected))
+		for _, c := range selected {
+			row = append(row, c.value(item))
+		}
+		rows.Values = append(rows.Values, row)
+		if hasState {
+			rows.states = append(rows.states, state.value(item))
+		}
+	}
+	// The state of items is kept even when its column isn't selected, e.g. for --until-state.
+	rows.hasState = hasState
+	return rows, nil
+}
+
+// listValuesLess returns how to order the values of a column: as numbers or times (e.g. created_at) when all of
+// them are, as strings otherwise. Empty values come first.
+func listValuesLess(values []string) func(a, b string) bool {
+	numbers, times := true, true
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			numbers = false
+		}
+		if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+			times = false
+		}
+	}
+
+	return func(a, b string) bool {
+		switch {
+		case a == "" || b == "":
+			return a == "" && b != ""
+		case numbers:
+			x, _ := strconv.ParseFloat(a, 64)
+			y, _ := strconv.ParseFloat(b, 64)
+			return x < y
+		case times:
+			x, _ := time.Parse(time.RFC3339Nano, a)
+			y, _ := time.Parse(time.RFC3339Nano, b)
+			return x.Before(y)
+		}
+		return a < b
+	}
+}
+
+// ListRows are the columns selected with --columns of listed items.
+type ListRows struct {
+	Columns []string
+	Values  [][]string
+
+	states   []string
+	hasState bool
+}
+
+// MarshalJSON encodes rows as a list of objects keeping the order of the columns.
+func (r ListRows) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for i, row := range r.Values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('{')
+		for j, c := range r.Columns {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			k, err := json.Marshal(c)
+			if err != nil {
+				return nil, err
+			}
+			v, err := json.Marshal(row[j])
+			if err != nil {
+				return nil, err
+			}
+			b.Write(k)
+			b.WriteByte(':')
+			b.Write(v)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(']')
+	return b.Bytes(), nil
+}
+
+func (r ListRows) table(o TableOptions) string {
+	if len(r.Values) == 0 {
+		return ""
+	}
+
+	table := simpletable.New()
+	if !o.HideHeaders {
+		table.Header = &simpletable.Header{}
+		for _, c := range r.Columns {
+			table.Header.Cells = append(table.Header.Cells, &simpletable.Cell{
+				Align: simpletable.AlignCenter,
+				Text:  strings.ToUpper(strings.ReplaceAll(c, "_", " ")),
+			})
+		}
+	}
+	for _, row := range r.Values {
+		cells := make([]*simpletable.Cell, 0, len(row))
+		for _, v := range row {
+			cells = append(cells, &simpletable.Cell{Text: v})
+		}
+		table.Body.Cells = append(table.Body.Cells, cells)
+	}
+	table.SetStyle(simpletable.StyleCompact)
+	return table.String()
+}
+
+// States returns the state of v, or the state of each of its items when v is a list, as shown by the state column of
+// list commands. ok is false when v has no state.
+func States(v interface{}) (states []string, ok bool) {
+	if v == nil {
+		return nil, false
+	}
+	if rows, ok := v.(ListRows); ok {
+		return rows.states, rows.hasState
+	}
+	if c, ok := stateColumn(reflect.TypeOf(v)); ok {
+		return []string{c.value(v)}, true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	c, ok := stateColumn(rv.Type().Elem())
+	if !ok {
+		return nil, false
+	}
+	states = make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		states = append(states, c.value(rv.Index(i).Interface()))
+	}
+	return states, true
+}
+
+func stateColumn(t reflect.Type) (listColumn, bool) {
+	for _, c := range listColumns[t] {
+		if c.name == "state" {
+			return c, true
+		}
+	}
+	return listColumn{}, false
+}
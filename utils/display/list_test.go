@@ -0,0 +1,212 @@
+package display
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meroxa/cli/utils"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+func listTestResources() []*meroxa.Resource {
+	var resources []*meroxa.Resource
+	for i, r := range []struct {
+		name  string
+		env   string
+		state meroxa.ResourceState
+	}{
+		{name: "orders", env: "prod", state: meroxa.ResourceStateReady},
+		{name: "billing", state: meroxa.ResourceStateError},
+		{name: "users", env: "prod", state: meroxa.ResourceStateError},
+	} {
+		res := utils.GenerateResourceWithNameAndStatus(r.name, "")
+		res.Status.State = r.state
+		if r.env != "" {
+			res.Environment = &meroxa.EntityIdentifier{Name: r.env}
+		}
+		res.CreatedAt = time.Date(2023, 1, 3-i, 0, 0, 0, 0, time.UTC)
+		resources = append(resources, &res)
+	}
+	return resources
+}
+
+func listNames(t *testing.T, v interface{}) []string {
+	t.Helper()
+	resources, ok := v.([]*meroxa.Resource)
+	if !ok {
+		t.Fatalf("expected resources, got %T", v)
+	}
+	names := make([]string, 0, len(resources))
+	for _, r := range resources {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func TestParseListOptions(t *testing.T) {
+	o, err := ParseListOptions([]string{"state=ready", "environment = prod"}, "name", []string{"name", " state", ""}, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ListOptions{
+		Filters: []ListFilter{{Key: "state", Value: "ready"}, {Key: "environment", Value: "prod"}},
+		SortBy:  "name",
+		Columns: []string{"name", "state"},
+		Limit:   5,
+	}
+	if !reflect.DeepEqual(o, want) {
+		t.Fatalf("expected %+v, got %+v", want, o)
+	}
+
+	if _, err = ParseListOptions([]string{"state"}, "", nil, 0); err == nil {
+		t.Fatal("expected an error for a filter without a value")
+	}
+	if _, err = ParseListOptions(nil, "", nil, -1); err == nil {
+		t.Fatal("expected an error for a negative limit")
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		desc string
+		o    ListOptions
+		want []string
+	}{
+		{desc: "no options", want: []string{"orders", "billing", "users"}},
+		{
+			desc: "filter",
+			o:    ListOptions{Filters: []ListFilter{{Key: "state", Value: "ERROR"}}},
+			want: []string{"billing", "users"},
+		},
+		{
+			desc: "filter by environment",
+			o:    ListOptions{Filters: []ListFilter{{Key: "environment", Value: "common"}}},
+			want: []string{"billing"},
+		},
+		{
+			desc: "filters",
+			o:    ListOptions{Filters: []ListFilter{{Key: "state", Value: "error"}, {Key: "environment", Value: "prod"}}},
+			want: []string{"users"},
+		},
+		{desc: "sort by name", o: ListOptions{SortBy: "name"}, want: []string{"billing", "orders", "users"}},
+		{desc: "sort by created_at", o: ListOptions{SortBy: "created_at"}, want: []string{"users", "billing", "orders"}},
+		{desc: "limit", o: ListOptions{SortBy: "name", Limit: 2}, want: []string{"billing", "orders"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := List(listTestResources(), tc.o)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if names := listNames(t, got); !reflect.DeepEqual(names, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, names)
+			}
+		})
+	}
+}
+
+func TestListUnknownColumn(t *testing.T) {
+	for _, o := range []ListOptions{
+		{Filters: []ListFilter{{Key: "foo", Value: "bar"}}},
+		{SortBy: "foo"},
+		{Columns: []string{"name", "foo"}},
+	} {
+		_, err := List([]*meroxa.Resource{}, o)
+		if err == nil || !strings.Contains(err.Error(), `unknown column "foo", use one of uuid, name, type`) {
+			t.Fatalf("expected unknown column error, got %v", err)
+		}
+	}
+}
+
+func TestListColumns(t *testing.T) {
+	got, err := List(listTestResources(), ListOptions{SortBy: "name", Columns: []string{"name", "environment", "state"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows, ok := got.(ListRows)
+	if !ok {
+		t.Fatalf("expected rows, got %T", got)
+	}
+
+	b, err := json.Marshal(rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `[{"name":"billing","environment":"common","state":"error"},` +
+		`{"name":"orders","environment":"prod","state":"ready"},` +
+		`{"name":"users","environment":"prod","state":"error"}]`
+	if string(b) != want {
+		t.Fatalf("expected %s, got %s", want, b)
+	}
+
+	table, err := Format(rows, OutputFormat{Name: OutputTable}, TableOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{"NAME", "ENVIRONMENT", "STATE", "billing", "common"} {
+		if !strings.Contains(table, s) {
+			t.Errorf("expected table to contain %q, got:\n%s", s, table)
+		}
+	}
+	if strings.Contains(table, "UUID") {
+		t.Errorf("expected table not to contain other columns, got:\n%s", table)
+	}
+
+	csv, err := Format(rows, OutputFormat{Name: OutputCSV}, TableOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(csv, "name,environment,state\nbilling,common,error\n") {
+		t.Fatalf("unexpected csv output:\n%s", csv)
+	}
+}
+
+func TestListValuesLess(t *testing.T) {
+	tests := []struct {
+		desc   string
+		values []string
+		want   []string
+	}{
+		{desc: "strings", values: []string{"b", "", "a"}, want: []string{"", "a", "b"}},
+		{desc: "numbers", values: []string{"10", "9", "100"}, want: []string{"9", "10", "100"}},
+		{
+			desc:   "times",
+			values: []string{"2023-01-02T01:00:00+02:00", "2023-01-01T23:30:00Z", ""},
+			want:   []string{"", "2023-01-02T01:00:00+02:00", "2023-01-01T23:30:00Z"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := append([]string(nil), tc.values...)
+			less := listValuesLess(got)
+			sort.SliceStable(got, func(i, j int) bool { return less(got[i], got[j]) })
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestListColumnsStates(t *testing.T) {
+	got, err := List(listTestResources(), ListOptions{SortBy: "name", Columns: []string{"name"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	states, ok := States(got)
+	if !ok {
+		t.Fatal("expected rows to keep the state of items")
+	}
+	if want := []string{"error", "ready", "error"}; !reflect.DeepEqual(states, want) {
+		t.Fatalf("expected states %v, got %v", want, states)
+	}
+
+	if _, ok = States(ListRows{Columns: []string{"name"}}); ok {
+		t.Fatal("expected rows of items without a state to have no state")
+	}
+}
@@ -11,9 +11,12 @@ func LogsTable(ll *meroxa.Logs) string {
 	var subTable string
 
 	for i := len(ll.Data) - 1; i >= 0; i-- {
-		l := ll.Data[i]
-		subTable += fmt.Sprintf("[%s]\t%s\t%q\n", l.Timestamp.Format(time.RFC3339), l.Source, l.Log)
+		subTable += LogLine(&ll.Data[i]) + "\n"
 	}
 
 	return subTable
 }
+
+func LogLine(l *meroxa.LogData) string {
+	return fmt.Sprintf("[%s]\t%s\t%q", l.Timestamp.Format(time.RFC3339), l.Source, l.Log)
+}
@@ -0,0 +1,346 @@
+package display
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats, chosen with --output.
+const (
+	OutputTable    = "table"
+	OutputWide     = "wide"
+	OutputJSON     = "json"
+	OutputYAML     = "yaml"
+	OutputCSV      = "csv"
+	OutputTemplate = "template"
+	OutputJSONPath = "jsonpath"
+)
+
+// OutputFormats are the formats accepted by --output.
+var OutputFormats = []string{
+	OutputTable, OutputJSON, OutputYAML, OutputCSV, OutputWide, OutputTemplate + "=...", OutputJSONPath + "=...",
+}
+
+// OutputFormat is a format of the output of commands, e.g. yaml or template={{.name}}.
+type OutputFormat struct {
+	Name string
+	// Arg is the template or the JSONPath expression.
+	Arg string
+
+	tmpl *template.Template
+	path jsonPath
+}
+
+// ParseOutputFormat parses the value of --output, checking templates and JSONPath expressions are valid.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	name, arg, hasArg := strings.Cut(s, "=")
+	f := OutputFormat{Name: name, Arg: arg}
+
+	switch name {
+	case "", OutputTable:
+		f.Name = OutputTable
+	case OutputWide, OutputJSON, OutputYAML, OutputCSV:
+	case OutputTemplate:
+		if !hasArg || arg == "" {
+			return f, fmt.Errorf("--output %s requires a template, e.g. %s='{{.name}}'", name, name)
+		}
+		tmpl, err := template.New("output").Option("missingkey=zero").Parse(arg)
+		if err != nil {
+			return f, fmt.Errorf("invalid template: %w", err)
+		}
+		f.tmpl = tmpl
+		return f, nil
+	case OutputJSONPath:
+		if !hasArg || arg == "" {
+			return f, fmt.Errorf("--output %s requires an expression, e.g. %s='{.name}'", name, name)
+		}
+		path, err := parseJSONPath(arg)
+		if err != nil {
+			return f, fmt.Errorf("invalid JSONPath expression: %w", err)
+		}
+		f.path = path
+		return f, nil
+	default:
+		return f, fmt.Errorf("unknown output format %q, use one of %s", s, strings.Join(OutputFormats, ", "))
+	}
+
+	if hasArg {
+		return f, fmt.Errorf("output format %q doesn't take an argument", name)
+	}
+	return f, nil
+}
+
+// Structured reports whether the format is meant for scripts, in which case only the result of commands is printed.
+func (f OutputFormat) Structured() bool {
+	return f.Name != OutputTable && f.Name != OutputWide
+}
+
+// TableOptions controls how values are rendered by the table and wide output formats.
+type TableOptions struct {
+	HideHeaders bool
+	// Wide shows additional columns.
+	Wide bool
+}
+
+var tables = map[reflect.Type]func(interface{}, TableOptions) string{}
+
+// RegisterTable registers how values of type T are rendered by the table and wide output formats.
+// Values without a table are printed as JSON by these formats.
+func RegisterTable[T any](render func(T, TableOptions) string) {
+	tables[reflect.TypeOf((*T)(nil)).Elem()] = func(v interface{}, o TableOptions) string {
+		return render(v.(T), o)
+	}
+}
+
+func init() {
+	RegisterTable(func(apps []*meroxa.Application, o TableOptions) string { return appsTable(apps, o) })
+	RegisterTable(func(app *meroxa.Application, _ TableOptions) string { return AppTable(app) })
+	RegisterTable(func(b *meroxa.Build, _ TableOptions) string { return BuildTable(b) })
+	RegisterTable(func(cc []*meroxa.Connector, o TableOptions) string { return connectorsTable(cc, o) })
+	RegisterTable(func(c *meroxa.Connector, _ TableOptions) string { return ConnectorTable(c) })
+	RegisterTable(func(dd []*meroxa.Deployment, o TableOptions) string { return DeploymentsTable(dd, o.HideHeaders) })
+	RegisterTable(func(d *meroxa.Deployment, _ TableOptions) string { return DeploymentTable(d) })
+	RegisterTable(func(ee []*meroxa.Environment, o TableOptions) string { return environmentsTable(ee, o) })
+	RegisterTable(func(e *meroxa.Environment, _ TableOptions) string { return EnvironmentTable(e) })
+	RegisterTable(func(jj []*meroxa.FlinkJob, _ TableOptions) string { return FlinkJobsTable(jj) })
+	RegisterTable(func(j *meroxa.FlinkJob, _ TableOptions) string { return FlinkJobTable(j) })
+	RegisterTable(func(ff []*meroxa.Function, o TableOptions) string { return FunctionsTable(ff, o.HideHeaders) })
+	RegisterTable(func(f *meroxa.Function, _ TableOptions) string { return FunctionTable(f) })
+	RegisterTable(func(pp []*meroxa.Pipeline, o TableOptions) string { return pipelinesTable(pp, o) })
+	RegisterTable(func(p *meroxa.Pipeline, _ TableOptions) string { return PipelineTable(p) })
+	RegisterTable(func(rr []*meroxa.Resource, o TableOptions) string { return resourcesTable(rr, o) })
+	RegisterTable(func(r *meroxa.Resource, _ TableOptions) string { return ResourceTable(r) })
+	RegisterTable(func(tt []meroxa.ResourceType, o TableOptions) string { return ResourceTypesTable(tt, o.HideHeaders) })
+	RegisterTable(func(tt []*meroxa.Transform, o TableOptions) string { return TransformsTable(tt, o.HideHeaders) })
+}
+
+// HasTable tells if values like data have a table registered. The result of commands should always have one.
+func HasTable(data interface{}) bool {
+	_, ok := tables[reflect.TypeOf(data)]
+	return ok
+}
+
+// NewFormatter returns a function rendering the result of a command in format f.
+func NewFormatter(f OutputFormat, o TableOptions) func(interface{}) (string, error) {
+	return func(data interface{}) (string, error) {
+		return Format(data, f, o)
+	}
+}
+
+// Format renders data in format f. The table and wide formats fall back to JSON for values without a table, so that
+// the result of a command is never lost.
+func Format(data interface{}, f OutputFormat, o TableOptions) (string, error) {
+	switch f.Name {
+	case OutputTable, OutputWide:
+		if data == nil {
+			return "", nil
+		}
+		if render, ok := tables[reflect.TypeOf(data)]; ok {
+			o.Wide = f.Name == OutputWide
+			return render(data, o), nil
+		}
+		return Format(data, OutputFormat{Name: OutputJSON}, o)
+	case OutputJSON:
+		if raw, ok := data.(string); ok {
+			return raw, nil
+		}
+		b, err := json.MarshalIndent(data, "", "\t")
+		return string(b), err
+	}
+
+	b, err := toJSON(data)
+	if err != nil {
+		return "", err
+	}
+
+	switch f.Name {
+	case OutputYAML:
+		return formatYAML(b)
+	case OutputCSV:
+		return formatCSV(b, o.HideHeaders)
+	}
+
+	var v interface{}
+	if err = json.Unmarshal(b, &v); err != nil {
+		return "", err
+	}
+	switch f.Name {
+	case OutputTemplate:
+		var out bytes.Buffer
+		if err = f.tmpl.Execute(&out, v); err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(out.String(), "\n"), nil
+	case OutputJSONPath:
+		results := f.path.eval(v)
+		lines := make([]string, 0, len(results))
+		for _, r := range results {
+			lines = append(lines, scalarString(r))
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+	return "", fmt.Errorf("unknown output format %q", f.Name)
+}
+
+// toJSON marshals data, strings are expected to be JSON already.
+func toJSON(data interface{}) ([]byte, error) {
+	if raw, ok := data.(string); ok {
+		if !json.Valid([]byte(raw)) {
+			return json.Marshal(raw)
+		}
+		return []byte(raw), nil
+	}
+	return json.Marshal(data)
+}
+
+// formatYAML converts JSON to YAML, keeping the order of fields. JSON is valid YAML, so it's parsed as YAML and
+// written back in block style.
+func formatYAML(b []byte) (string, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return "", err
+	}
+	blockStyle(&node)
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out.String(), "\n"), nil
+}
+
+// blockStyle drops the flow style and quotes of JSON, strings which would be read as another type are still quoted.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// formatCSV renders a JSON array of objects as a CSV row per object, or a single object as one row.
+// Columns are the fields of the objects in order, nested values are written as JSON.
+func formatCSV(b []byte, hideHeaders bool) (string, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return "", err
+	}
+	root := &node
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	var objects []*yaml.Node
+	switch root.Kind {
+	case yaml.SequenceNode:
+		objects = root.Content
+	case yaml.MappingNode:
+		objects = []*yaml.Node{root}
+	default:
+		return "", fmt.Errorf("csv output requires a list or an object")
+	}
+
+	var columns []string
+	seen := map[string]bool{}
+	for _, o := range objects {
+		if o.Kind != yaml.MappingNode {
+			return "", fmt.Errorf("csv output requires a list of objects")
+		}
+		for i := 0; i < len(o.Content); i += 2 {
+			if k := o.Content[i].Value; !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	if !hideHeaders {
+		if err := w.Write(columns); err != nil {
+			return "", err
+		}
+	}
+	for _, o := range objects {
+		values := map[string]string{}
+		for i := 0; i < len(o.Content); i += 2 {
+			v, err := csvValue(o.Content[i+1])
+			if err != nil {
+				return "", err
+			}
+			values[o.Content[i].Value] = v
+		}
+		row := make([]string, 0, len(columns))
+		for _, c := range columns {
+			row = append(row, values[c])
+		}
+		if err := w.Write(row); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return strings.TrimSuffix(out.String(), "\n"), w.Error()
+}
+
+func csvValue(n *yaml.Node) (string, error) {
+	if n.Kind == yaml.ScalarNode {
+		if n.Tag == "!!null" {
+			return "", nil
+		}
+		return n.Value, nil
+	}
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// scalarString renders a JSON value as is for scalars and as compact JSON otherwise.
+func scalarString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// wideHeaderCells returns the headers of the columns only shown by the wide output format.
+func wideHeaderCells() []*simpletable.Cell {
+	return []*simpletable.Cell{
+		{Align: simpletable.AlignCenter, Text: "CREATED"},
+		{Align: simpletable.AlignCenter, Text: "UPDATED"},
+	}
+}
+
+// wideCells returns the cells of the columns only shown by the wide output format.
+func wideCells(created, updated time.Time) []*simpletable.Cell {
+	return []*simpletable.Cell{
+		{Text: formatTime(created)},
+		{Text: formatTime(updated)},
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
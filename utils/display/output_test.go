@@ -0,0 +1,289 @@
+package display
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meroxa/cli/utils"
+	"github.com/meroxa/meroxa-go/pkg/meroxa"
+)
+
+type outputTestItem struct {
+	Name  string            `json:"name"`
+	State string            `json:"state"`
+	Count int               `json:"count"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+func mustParseOutputFormat(t *testing.T, s string) OutputFormat {
+	t.Helper()
+	f, err := ParseOutputFormat(s)
+	if err != nil {
+		t.Fatalf("unexpected error parsing %q: %v", s, err)
+	}
+	return f
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		name    string
+		wantErr string
+	}{
+		{in: "", name: OutputTable},
+		{in: "table", name: OutputTable},
+		{in: "wide", name: OutputWide},
+		{in: "json", name: OutputJSON},
+		{in: "yaml", name: OutputYAML},
+		{in: "csv", name: OutputCSV},
+		{in: "template={{.name}}", name: OutputTemplate},
+		{in: "jsonpath={.items[*].name}", name: OutputJSONPath},
+		{in: "xml", wantErr: `unknown output format "xml"`},
+		{in: "json=foo", wantErr: `output format "json" doesn't take an argument`},
+		{in: "template", wantErr: "--output template requires a template"},
+		{in: "template={{.name", wantErr: "invalid template"},
+		{in: "jsonpath=", wantErr: "--output jsonpath requires an expression"},
+		{in: "jsonpath={..name}", wantErr: "invalid JSONPath expression"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			f, err := ParseOutputFormat(tc.in)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if f.Name != tc.name {
+				t.Fatalf("expected format %q, got %q", tc.name, f.Name)
+			}
+		})
+	}
+}
+
+func TestOutputFormatStructured(t *testing.T) {
+	for _, s := range []string{"table", "wide"} {
+		if mustParseOutputFormat(t, s).Structured() {
+			t.Errorf("expected %q not to be structured", s)
+		}
+	}
+	for _, s := range []string{"json", "yaml", "csv", "template={{.}}", "jsonpath={}"} {
+		if !mustParseOutputFormat(t, s).Structured() {
+			t.Errorf("expected %q to be structured", s)
+		}
+	}
+}
+
+func TestFormatTableUsesRegisteredTables(t *testing.T) {
+	app := utils.GenerateApplication("")
+	apps := []*meroxa.Application{&app}
+	r := utils.GenerateResource()
+	resources := []*meroxa.Resource{&r}
+
+	tests := []struct {
+		desc string
+		data interface{}
+		want string
+	}{
+		{desc: "apps", data: apps, want: AppsTable(apps, false)},
+		{desc: "app", data: &app, want: AppTable(&app)},
+		{desc: "resources", data: resources, want: ResourcesTable(resources, false)},
+		{desc: "resource", data: &r, want: ResourceTable(&r)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := Format(tc.data, mustParseOutputFormat(t, "table"), TableOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+
+	got, err := Format(resources, mustParseOutputFormat(t, "table"), TableOptions{HideHeaders: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := ResourcesTable(resources, true); got != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestFormatTableWithoutRegisteredTable(t *testing.T) {
+	for _, format := range []string{"table", "wide"} {
+		got, err := Format(outputTestItem{Name: "a"}, mustParseOutputFormat(t, format), TableOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "{\n\t\"name\": \"a\",\n\t\"state\": \"\",\n\t\"count\": 0\n}"; got != want {
+			t.Fatalf("expected the %s format to fall back to JSON %q, got %q", format, want, got)
+		}
+	}
+}
+
+// TestCommandOutputsHaveTables checks the values commands print with Output have a table. log.NewTestLogger checks
+// it as well for the values printed in command tests.
+func TestCommandOutputsHaveTables(t *testing.T) {
+	outputs := []interface{}{
+		[]*meroxa.Application{}, &meroxa.Application{},
+		&meroxa.Build{},
+		[]*meroxa.Connector{}, &meroxa.Connector{},
+		[]*meroxa.Deployment{}, &meroxa.Deployment{},
+		[]*meroxa.Environment{}, &meroxa.Environment{},
+		[]*meroxa.FlinkJob{}, &meroxa.FlinkJob{},
+		[]*meroxa.Function{}, &meroxa.Function{},
+		[]*meroxa.Pipeline{}, &meroxa.Pipeline{},
+		[]*meroxa.Resource{}, &meroxa.Resource{},
+		[]meroxa.ResourceType{},
+		[]*meroxa.Transform{},
+		ListRows{},
+	}
+	for _, o := range outputs {
+		if !HasTable(o) {
+			t.Errorf("no table registered for %T", o)
+		}
+	}
+}
+
+func TestFormatWide(t *testing.T) {
+	r := utils.GenerateResource()
+	r.CreatedAt = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	resources := []*meroxa.Resource{&r}
+
+	got, err := Format(resources, mustParseOutputFormat(t, "wide"), TableOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"CREATED", "UPDATED", "2023-01-02T03:04:05Z"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected wide output to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	got, err = Format(resources, mustParseOutputFormat(t, "table"), TableOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(got, "CREATED") {
+		t.Errorf("expected table output not to contain wide columns, got:\n%s", got)
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	got, err := Format(outputTestItem{Name: "a", State: "ready"}, mustParseOutputFormat(t, "json"), TableOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "{\n\t\"name\": \"a\",\n\t\"state\": \"ready\",\n\t\"count\": 0\n}"
+	if got != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestFormatYAML(t *testing.T) {
+	items := []outputTestItem{
+		{Name: "a", State: "true", Count: 1, Tags: map[string]string{"env": "prod"}},
+		{Name: "b", State: "ready", Count: 2},
+	}
+
+	got, err := Format(items, mustParseOutputFormat(t, "yaml"), TableOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `- name: a
+  state: "true"
+  count: 1
+  tags:
+    env: prod
+- name: b
+  state: ready
+  count: 2`
+	if strings.TrimSpace(got) != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestFormatCSV(t *testing.T) {
+	items := []outputTestItem{
+		{Name: "a", State: "ready", Count: 1},
+		{Name: "b, c", State: "failed", Count: 2, Tags: map[string]string{"env": "prod"}},
+	}
+
+	got, err := Format(items, mustParseOutputFormat(t, "csv"), TableOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `name,state,count,tags
+a,ready,1,
+"b, c",failed,2,"{""env"":""prod""}"`
+	if got != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	got, err = Format(items[:1], mustParseOutputFormat(t, "csv"), TableOptions{HideHeaders: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want = "a,ready,1"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if _, err = Format([]string{"a"}, mustParseOutputFormat(t, "csv"), TableOptions{}); err == nil {
+		t.Fatal("expected an error rendering a list of strings as csv")
+	}
+}
+
+func TestFormatTemplate(t *testing.T) {
+	items := []outputTestItem{{Name: "a", State: "ready"}, {Name: "b", State: "failed"}}
+
+	got, err := Format(items, mustParseOutputFormat(t, `template={{range .}}{{.name}}={{.state}} {{.missing}}
+{{end}}`), TableOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "a=ready <no value>\nb=failed <no value>"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestFormatJSONPath(t *testing.T) {
+	data := map[string]interface{}{
+		"items": []outputTestItem{
+			{Name: "a", Count: 1, Tags: map[string]string{"env": "prod"}},
+			{Name: "b", Count: 2},
+		},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "{.items[*].name}", want: "a\nb"},
+		{path: "$.items[0].count", want: "1"},
+		{path: "{.items[-1].name}", want: "b"},
+		{path: "{.items[0]['tags'].env}", want: "prod"},
+		{path: "{.items[0].tags}", want: `{"env":"prod"}`},
+		{path: "{.items[*].missing}", want: ""},
+		{path: "{.items[5].name}", want: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			got, err := Format(data, mustParseOutputFormat(t, "jsonpath="+tc.path), TableOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -55,10 +55,14 @@ func PrintPipelineTable(pipeline *meroxa.Pipeline) {
 }
 
 func PipelinesTable(pipelines []*meroxa.Pipeline, hideHeaders bool) string {
+	return pipelinesTable(pipelines, TableOptions{HideHeaders: hideHeaders})
+}
+
+func pipelinesTable(pipelines []*meroxa.Pipeline, o TableOptions) string {
 	if len(pipelines) != 0 {
 		table := simpletable.New()
 
-		if !hideHeaders {
+		if !o.HideHeaders {
 			table.Header = &simpletable.Header{
 				Cells: []*simpletable.Cell{
 					{Align: simpletable.AlignCenter, Text: "UUID"},
@@ -67,6 +71,9 @@ func PipelinesTable(pipelines []*meroxa.Pipeline, hideHeaders bool) string {
 					{Align: simpletable.AlignCenter, Text: "STATE"},
 				},
 			}
+			if o.Wide {
+				table.Header.Cells = append(table.Header.Cells, wideHeaderCells()...)
+			}
 		}
 
 		for _, p := range pipelines {
@@ -84,6 +91,9 @@ func PipelinesTable(pipelines []*meroxa.Pipeline, hideHeaders bool) string {
 				{Align: simpletable.AlignCenter, Text: env},
 				{Align: simpletable.AlignCenter, Text: string(p.State)},
 			}
+			if o.Wide {
+				r = append(r, wideCells(p.CreatedAt, p.UpdatedAt)...)
+			}
 
 			table.Body.Cells = append(table.Body.Cells, r)
 		}
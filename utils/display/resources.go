@@ -76,10 +76,14 @@ func ResourceTable(res *meroxa.Resource) string {
 }
 
 func ResourcesTable(resources []*meroxa.Resource, hideHeaders bool) string {
+	return resourcesTable(resources, TableOptions{HideHeaders: hideHeaders})
+}
+
+func resourcesTable(resources []*meroxa.Resource, o TableOptions) string {
 	if len(resources) != 0 {
 		table := simpletable.New()
 
-		if !hideHeaders {
+		if !o.HideHeaders {
 			table.Header = &simpletable.Header{
 				Cells: []*simpletable.Cell{
 					{Align: simpletable.AlignCenter, Text: "UUID"},
@@ -91,6 +95,9 @@ func ResourcesTable(resources []*meroxa.Resource, hideHeaders bool) string {
 					{Align: simpletable.AlignCenter, Text: "STATE"},
 				},
 			}
+			if o.Wide {
+				table.Header.Cells = append(table.Header.Cells, wideHeaderCells()...)
+			}
 		}
 
 		for _, res := range resources {
@@ -116,6 +123,9 @@ func ResourcesTable(resources []*meroxa.Resource, hideHeaders bool) string {
 				{Align: simpletable.AlignCenter, Text: tunnel},
 				{Align: simpletable.AlignCenter, Text: string(res.Status.State)},
 			}
+			if o.Wide {
+				r = append(r, wideCells(res.CreatedAt, res.UpdatedAt)...)
+			}
 
 			table.Body.Cells = append(table.Body.Cells, r)
 		}